@@ -0,0 +1,10 @@
+-- +migrate Up
+-- token_transactionsにべき等キーを追加（同一VLogに対する二重課金を防止）
+ALTER TABLE token_transactions
+    ADD COLUMN reference_id VARCHAR(255) NULL COMMENT 'べき等キー（VLog IDなど）' AFTER description,
+    ADD CONSTRAINT uc_token_transactions_reference UNIQUE (reference_id, type);
+
+-- +migrate Down
+ALTER TABLE token_transactions
+    DROP INDEX uc_token_transactions_reference,
+    DROP COLUMN reference_id;
@@ -0,0 +1,57 @@
+-- +migrate Up
+ALTER TABLE payments
+    ADD COLUMN stripe_session_id VARCHAR(255) NULL COMMENT 'StripeのCheckout Session ID' AFTER stripe_payment_id,
+    ADD CONSTRAINT uc_payments_stripe_session_id UNIQUE (stripe_session_id);
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT uc_subscriptions_stripe_subscription_id UNIQUE (stripe_subscription_id);
+
+-- checkout_sessionsテーブル
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    user_id VARCHAR(255) NOT NULL COMMENT 'ユーザーID',
+    client_request_id VARCHAR(255) NOT NULL COMMENT 'クライアントが発行するべき等キー',
+    stripe_session_id VARCHAR(255) NOT NULL COMMENT 'StripeのCheckout Session ID',
+    product VARCHAR(50) NOT NULL COMMENT '課金商品',
+    type VARCHAR(50) NOT NULL COMMENT 'トークン購入、サブスクリプション',
+    plan VARCHAR(50) NULL COMMENT 'サブスクリプションのプラン',
+    tokens INT NOT NULL COMMENT '付与トークン数',
+    amount INT NOT NULL COMMENT '金額（円）',
+    url VARCHAR(1024) NOT NULL COMMENT 'CheckoutのURL',
+    status VARCHAR(50) NOT NULL COMMENT '未完了、完了',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT fk_checkout_sessions_user_id FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT uc_checkout_sessions_client_request UNIQUE (user_id, client_request_id),
+    CONSTRAINT uc_checkout_sessions_stripe_session_id UNIQUE (stripe_session_id),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- stripe_webhook_eventsテーブル
+CREATE TABLE IF NOT EXISTS stripe_webhook_events (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    event_id VARCHAR(255) NOT NULL COMMENT 'StripeのイベントID',
+    type VARCHAR(100) NOT NULL COMMENT 'イベントタイプ',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_stripe_webhook_events_event_id UNIQUE (event_id),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS stripe_webhook_events;
+DROP TABLE IF EXISTS checkout_sessions;
+
+ALTER TABLE subscriptions DROP INDEX uc_subscriptions_stripe_subscription_id;
+
+ALTER TABLE payments
+    DROP INDEX uc_payments_stripe_session_id,
+    DROP COLUMN stripe_session_id;
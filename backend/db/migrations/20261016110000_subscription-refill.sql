@@ -0,0 +1,10 @@
+-- +migrate Up
+-- 契約期間ごとのトークン付与状況を記録（定期処理のべき等性確保）
+ALTER TABLE subscriptions
+    ADD COLUMN last_refilled_period_end TIMESTAMP NULL COMMENT 'トークン付与済みの付与期間（1か月）の終了日時' AFTER current_period_end,
+    ADD INDEX idx_current_period_end (current_period_end);
+
+-- +migrate Down
+ALTER TABLE subscriptions
+    DROP INDEX idx_current_period_end,
+    DROP COLUMN last_refilled_period_end;
@@ -0,0 +1,25 @@
+-- +migrate Up
+-- task_executionsテーブル
+CREATE TABLE IF NOT EXISTS task_executions (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    task_type VARCHAR(100) NOT NULL COMMENT 'タスクタイプ',
+    task_id VARCHAR(255) NOT NULL COMMENT 'タスクID（VLog IDなど）',
+    task_version INT NOT NULL COMMENT 'タスク登録時の対象レコードのバージョン',
+    status VARCHAR(50) NOT NULL COMMENT '実行中、完了、失敗',
+    owner VARCHAR(255) NOT NULL COMMENT '実行中のワーカーの識別子',
+    lease_expires_at TIMESTAMP NOT NULL COMMENT '実行権の有効期限',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '実行回数',
+    last_error TEXT NULL COMMENT '直近のエラー',
+    completed_at TIMESTAMP NULL COMMENT '完了日時',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_task_executions_task UNIQUE (task_type, task_id, task_version),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS task_executions;
@@ -0,0 +1,32 @@
+-- +migrate Up
+ALTER TABLE task_executions
+    ADD COLUMN attempt_history JSON NULL COMMENT '各実行の結果' AFTER completed_at;
+
+-- dead_letter_tasksテーブル
+CREATE TABLE IF NOT EXISTS dead_letter_tasks (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    task_type VARCHAR(100) NOT NULL COMMENT 'タスクタイプ',
+    task_id VARCHAR(255) NOT NULL COMMENT 'タスクID（VLog IDなど）',
+    task_version INT NOT NULL COMMENT 'タスクのバージョン',
+    payload JSON NOT NULL COMMENT '登録時のタスク',
+    errors JSON NULL COMMENT '最後の失敗のエラーチェーン',
+    attempts INT NOT NULL COMMENT '実行回数',
+    attempt_history JSON NULL COMMENT '各実行の結果',
+    status VARCHAR(50) NOT NULL COMMENT '再実行待ち、再実行済み',
+    requeued_at TIMESTAMP NULL COMMENT '再実行日時',
+    requeued_task_version INT NULL COMMENT '再実行時のタスクのバージョン',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_dead_letter_tasks_task UNIQUE (task_type, task_id, task_version),
+    INDEX idx_dead_letter_tasks_status (status, created_at),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS dead_letter_tasks;
+
+ALTER TABLE task_executions DROP COLUMN attempt_history;
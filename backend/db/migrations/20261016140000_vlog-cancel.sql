@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE vlogs
+    ADD COLUMN token_reference_id VARCHAR(255) NULL COMMENT '仮引き中のトークンの参照ID' AFTER completed_at,
+    ADD COLUMN cancelled_at TIMESTAMP NULL COMMENT 'キャンセル日時' AFTER token_reference_id;
+
+-- +migrate Down
+ALTER TABLE vlogs
+    DROP COLUMN cancelled_at,
+    DROP COLUMN token_reference_id;
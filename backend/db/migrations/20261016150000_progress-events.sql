@@ -0,0 +1,15 @@
+-- +migrate Up
+-- progress_eventsテーブル（複数インスタンス間で進捗イベントを配信する）
+CREATE TABLE IF NOT EXISTS progress_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT 'イベントID（SSEのLast-Event-ID）',
+    topic VARCHAR(255) NOT NULL COMMENT '購読単位（vlog:<id>、media:<id>）',
+    name VARCHAR(50) NOT NULL DEFAULT '' COMMENT 'SSEのイベント名',
+    data JSON NOT NULL COMMENT 'イベントデータ',
+    final BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'トピックの最後のイベントかどうか',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_topic_id (topic, id),
+    INDEX idx_created_at (created_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS progress_events;
@@ -0,0 +1,24 @@
+-- +migrate Up
+-- vlog_stepsテーブル
+CREATE TABLE IF NOT EXISTS vlog_steps (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    vlog_id VARCHAR(255) NOT NULL COMMENT 'VLog ID',
+    name VARCHAR(100) NOT NULL COMMENT 'ステップ名',
+    item_index INT NOT NULL DEFAULT 0 COMMENT 'アイテム単位のステップの番号（1始まり、それ以外は0）',
+    status VARCHAR(50) NOT NULL COMMENT '実行中、完了、失敗、スキップ',
+    started_at TIMESTAMP(3) NOT NULL COMMENT '開始日時',
+    ended_at TIMESTAMP(3) NULL COMMENT '終了日時',
+    retry_count INT NOT NULL DEFAULT 0 COMMENT '再実行回数',
+    error_message TEXT NULL COMMENT 'エラーメッセージ',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_vlog_steps_step UNIQUE (vlog_id, name, item_index),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS vlog_steps;
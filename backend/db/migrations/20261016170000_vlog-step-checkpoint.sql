@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE vlog_steps
+    ADD COLUMN checkpoint MEDIUMTEXT NULL COMMENT '再実行時に再開するためのステップの出力（JSON）' AFTER error_message;
+
+-- +migrate Down
+ALTER TABLE vlog_steps
+    DROP COLUMN checkpoint;
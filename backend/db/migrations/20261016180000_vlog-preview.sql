@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE vlogs
+    ADD COLUMN preview VARCHAR(512) NULL COMMENT '動画のアニメーションプレビュー（GIF・WebP）' AFTER thumbnail;
+
+-- +migrate Down
+ALTER TABLE vlogs
+    DROP COLUMN preview;
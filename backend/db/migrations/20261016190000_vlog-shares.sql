@@ -0,0 +1,24 @@
+-- +migrate Up
+-- vlog_sharesテーブル
+CREATE TABLE IF NOT EXISTS vlog_shares (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    vlog_id VARCHAR(255) NOT NULL COMMENT 'VLog ID',
+    code VARCHAR(255) NOT NULL COMMENT '共有コード',
+    password_hash VARCHAR(255) NULL COMMENT '閲覧パスワードのハッシュ（未設定の場合はNULL）',
+    expires_at TIMESTAMP NULL COMMENT '有効期限（無期限の場合はNULL）',
+    view_count INT NOT NULL DEFAULT 0 COMMENT '閲覧数',
+    revoked_at TIMESTAMP NULL COMMENT '無効化日時',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_vlog_shares_code UNIQUE (code),
+    CONSTRAINT fk_vlog_shares_vlog_id FOREIGN KEY (vlog_id) REFERENCES vlogs (id),
+    INDEX idx_vlog_id (vlog_id),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS vlog_shares;
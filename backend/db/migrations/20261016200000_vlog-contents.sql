@@ -0,0 +1,30 @@
+-- +migrate Up
+ALTER TABLE vlogs
+    ADD COLUMN title VARCHAR(255) NULL COMMENT 'VLogのタイトル' AFTER video_id,
+    ADD COLUMN description TEXT NULL COMMENT 'VLogの説明文' AFTER title,
+    ADD COLUMN analytics_locations JSON NULL COMMENT '検出されたロケーション' AFTER cancelled_at,
+    ADD COLUMN analytics_activities JSON NULL COMMENT '検出されたアクティビティ' AFTER analytics_locations,
+    ADD COLUMN analytics_mood VARCHAR(100) NULL COMMENT '全体の雰囲気' AFTER analytics_activities,
+    ADD COLUMN analytics_highlights JSON NULL COMMENT 'ハイライトシーンの説明' AFTER analytics_mood,
+    ADD COLUMN analytics_media_count INT NOT NULL DEFAULT 0 COMMENT '使用したメディア数' AFTER analytics_highlights;
+
+-- これまで字幕はVLogと紐付けて保存していなかったため、既存の字幕はvlog_idをNULLのまま残す
+ALTER TABLE subtitle_segments
+    ADD COLUMN vlog_id VARCHAR(255) NULL COMMENT 'VLog ID（紐付け導入前の字幕はNULL）' AFTER update_user_id,
+    ADD CONSTRAINT fk_subtitle_segments_vlog_id FOREIGN KEY (vlog_id) REFERENCES vlogs (id),
+    ADD INDEX idx_vlog_id (vlog_id);
+
+-- +migrate Down
+ALTER TABLE subtitle_segments
+    DROP FOREIGN KEY fk_subtitle_segments_vlog_id,
+    DROP INDEX idx_vlog_id,
+    DROP COLUMN vlog_id;
+
+ALTER TABLE vlogs
+    DROP COLUMN analytics_media_count,
+    DROP COLUMN analytics_highlights,
+    DROP COLUMN analytics_mood,
+    DROP COLUMN analytics_activities,
+    DROP COLUMN analytics_locations,
+    DROP COLUMN description,
+    DROP COLUMN title;
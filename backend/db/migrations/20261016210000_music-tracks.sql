@@ -0,0 +1,29 @@
+-- +migrate Up
+-- music_tracksテーブル（ライブラリの曲はuser_idがNULL、ユーザーがアップロードした曲はuser_idを設定する）
+CREATE TABLE IF NOT EXISTS music_tracks (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    user_id VARCHAR(255) NULL COMMENT 'アップロードしたユーザーID（ライブラリの曲はNULL）',
+    title VARCHAR(255) NOT NULL DEFAULT '' COMMENT '曲名',
+    mood VARCHAR(100) NOT NULL DEFAULT '' COMMENT '雰囲気',
+    bpm INT NOT NULL DEFAULT 0 COMMENT 'BPM',
+    object_key VARCHAR(1024) NOT NULL COMMENT 'R2のオブジェクトキー',
+    content_type VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'コンテンツタイプ',
+    duration_seconds DOUBLE NOT NULL DEFAULT 0 COMMENT '曲の長さ（秒）',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_user_id_mood (user_id, mood),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+ALTER TABLE vlogs
+    ADD COLUMN music_track_id VARCHAR(255) NULL COMMENT 'BGMに使用した曲のID' AFTER analytics_media_count;
+
+-- +migrate Down
+ALTER TABLE vlogs
+    DROP COLUMN music_track_id;
+
+DROP TABLE IF EXISTS music_tracks;
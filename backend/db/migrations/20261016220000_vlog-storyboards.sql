@@ -0,0 +1,21 @@
+-- +migrate Up
+-- vlog_storyboardsテーブル（VLogごとに1件）
+CREATE TABLE IF NOT EXISTS vlog_storyboards (
+    id VARCHAR(255) PRIMARY KEY,
+    version INT NOT NULL DEFAULT 0 COMMENT '楽観的ロックのバージョン',
+    create_user_id VARCHAR(255) NULL COMMENT '作成者のユーザーID',
+    update_user_id VARCHAR(255) NULL COMMENT '更新者のユーザーID',
+    vlog_id VARCHAR(255) NOT NULL COMMENT 'VLog ID',
+    scenes JSON NOT NULL COMMENT '再生順のシーン',
+    input MEDIUMTEXT NOT NULL COMMENT 'VLog生成の入力（JSON）',
+    approved_at TIMESTAMP NULL COMMENT '動画の生成を開始した日時',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT uc_vlog_storyboards_vlog_id UNIQUE (vlog_id),
+    CONSTRAINT fk_vlog_storyboards_vlog_id FOREIGN KEY (vlog_id) REFERENCES vlogs (id),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- +migrate Down
+DROP TABLE IF EXISTS vlog_storyboards;
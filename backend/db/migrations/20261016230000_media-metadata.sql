@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE media
+    ADD COLUMN captured_at DATETIME NULL COMMENT '撮影日時（EXIF・動画のメタデータ）' AFTER error_message,
+    ADD COLUMN latitude DECIMAL(9, 6) NULL COMMENT '撮影地点の緯度' AFTER captured_at,
+    ADD COLUMN longitude DECIMAL(9, 6) NULL COMMENT '撮影地点の経度' AFTER latitude,
+    ADD COLUMN camera_model VARCHAR(255) NULL COMMENT 'カメラの機種' AFTER longitude,
+    ADD COLUMN orientation TINYINT NOT NULL DEFAULT 0 COMMENT '向き（EXIFのOrientation、1〜8。不明な場合は0）' AFTER camera_model;
+
+-- +migrate Down
+ALTER TABLE media
+    DROP COLUMN orientation,
+    DROP COLUMN camera_model,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude,
+    DROP COLUMN captured_at;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE media
+    ADD COLUMN duration DOUBLE NOT NULL DEFAULT 0 COMMENT '動画の長さ（秒、動画のメタデータから取得。画像や取得できない場合は0）' AFTER orientation;
+
+-- +migrate Down
+ALTER TABLE media
+    DROP COLUMN duration;
@@ -0,0 +1,19 @@
+package agent
+
+import "context"
+
+type progressCallbackKey struct{}
+
+// WithProgressCallback はフローの各ステップの進捗を通知するコールバックをコンテキストに設定する
+func WithProgressCallback(ctx context.Context, callback ProgressCallback) context.Context {
+	return context.WithValue(ctx, progressCallbackKey{}, callback)
+}
+
+// ReportProgress はコンテキストに設定されたコールバックに進捗を通知する（未設定の場合は何もしない）
+func ReportProgress(ctx context.Context, progress FlowProgress) {
+	callback, ok := ctx.Value(progressCallbackKey{}).(ProgressCallback)
+	if !ok || callback == nil {
+		return
+	}
+	callback(progress)
+}
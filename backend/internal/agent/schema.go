@@ -9,20 +9,34 @@ type VlogInput struct {
 	UserID      string      `json:"userId" jsonschema:"description=ユーザーID,required"`
 	MediaItems  []MediaItem `json:"mediaItems" jsonschema:"description=分析対象のメディアアイテム,required"`
 	Title       string      `json:"title,omitempty" jsonschema:"description=VLogのタイトル（省略時は自動生成）"`
+	Description string      `json:"description,omitempty" jsonschema:"description=VLogの説明文（タイトルを指定した場合のみ使用）"`
 	TravelDate  string      `json:"travelDate,omitempty" jsonschema:"description=旅行日（YYYY-MM-DD形式）"`
 	Destination string      `json:"destination,omitempty" jsonschema:"description=旅行先"`
 	Style       VlogStyle   `json:"style,omitempty" jsonschema:"description=VLogのスタイル設定"`
+	// ReviewStoryboard は絵コンテの生成後に停止し、ユーザーの確認を待つかどうか
+	ReviewStoryboard bool `json:"reviewStoryboard,omitempty" jsonschema:"description=絵コンテの確認後に動画を生成するかどうか"`
+	// Storyboard はユーザーが確認・編集した絵コンテ（指定した場合は絵コンテを生成しない）
+	Storyboard *Storyboard `json:"storyboard,omitempty" jsonschema:"description=確認済みの絵コンテ"`
 }
 
 // MediaItem は個別のメディアファイル情報
 type MediaItem struct {
-	FileID      string `json:"fileId" jsonschema:"description=ファイルID,required"`
-	URL         string `json:"url" jsonschema:"description=メディアのURL,required"`
-	Type        string `json:"type" jsonschema:"description=メディアタイプ（image または video）,required"`
-	ContentType string `json:"contentType" jsonschema:"description=MIMEタイプ"`
-	Timestamp   string `json:"timestamp,omitempty" jsonschema:"description=撮影日時（ISO 8601形式）"`
-	Order       int    `json:"order,omitempty" jsonschema:"description=表示順序"`
-	IsAnalyzed  bool   `json:"isAnalyzed,omitempty" jsonschema:"description=分析済みかどうか"`
+	FileID      string  `json:"fileId" jsonschema:"description=ファイルID,required"`
+	URL         string  `json:"url" jsonschema:"description=メディアのURL,required"`
+	Type        string  `json:"type" jsonschema:"description=メディアタイプ（image または video）,required"`
+	ContentType string  `json:"contentType" jsonschema:"description=MIMEタイプ"`
+	Timestamp   string  `json:"timestamp,omitempty" jsonschema:"description=撮影日時（ISO 8601形式）"`
+	Order       int     `json:"order,omitempty" jsonschema:"description=表示順序"`
+	IsAnalyzed  bool    `json:"isAnalyzed,omitempty" jsonschema:"description=分析済みかどうか"`
+	Duration    float64 `json:"duration,omitempty" jsonschema:"description=動画の長さ（秒、動画の場合のみ）"`
+	// Location はEXIF・動画のメタデータから取得した撮影地点
+	Location *GeoLocation `json:"location,omitempty" jsonschema:"description=撮影地点"`
+}
+
+// GeoLocation は撮影地点の緯度・経度
+type GeoLocation struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=緯度"`
+	Longitude float64 `json:"longitude" jsonschema:"description=経度"`
 }
 
 // VlogStyle はVLog生成スタイルの設定
@@ -31,19 +45,74 @@ type VlogStyle struct {
 	MusicMood  string `json:"musicMood,omitempty" jsonschema:"description=BGMの雰囲気"`
 	Duration   int    `json:"duration,omitempty" jsonschema:"description=目標再生時間（秒）,default=60"`
 	Transition string `json:"transition,omitempty" jsonschema:"description=トランジション効果（fade/slide/zoom）"`
+	// ReferenceMode はユーザーの写真の使い方（未指定の場合はinspired）
+	ReferenceMode string `json:"referenceMode,omitempty" jsonschema:"description=写真の使い方（inspired: 写真を参考に生成 / animate: 写真をそのまま動かす）"`
+	// SubtitleMode は字幕の出力方法（未指定の場合はsidecar）
+	SubtitleMode string `json:"subtitleMode,omitempty" jsonschema:"description=字幕の出力方法（sidecar: 字幕ファイルのみ / burned: 動画に焼き込む）"`
+	// MusicTrackID はユーザーがアップロードしたBGMの曲（未指定の場合はMusicMoodに合うライブラリの曲）
+	MusicTrackID string `json:"musicTrackId,omitempty" jsonschema:"description=BGMに使用する曲のID"`
+	// Narration は字幕を読み上げるナレーションの設定（未指定の場合はナレーションなし）
+	Narration *NarrationStyle `json:"narration,omitempty" jsonschema:"description=ナレーションの設定"`
+}
+
+// NarrationStyle はナレーションの声の設定
+type NarrationStyle struct {
+	SpeakerID  int     `json:"speakerId" jsonschema:"description=話者（VOICEVOXのスタイルID）"`
+	SpeedScale float64 `json:"speedScale,omitempty" jsonschema:"description=話速（0.5〜2.0、省略時は1.0）"`
+	PitchScale float64 `json:"pitchScale,omitempty" jsonschema:"description=音高（-0.15〜0.15、省略時は0）"`
 }
 
+// 写真の使い方
+const (
+	ReferenceModeInspired = "inspired" // 写真を参考画像として生成する
+	ReferenceModeAnimate  = "animate"  // 写真を最初のフレームとして動かす
+)
+
+// 字幕の出力方法
+const (
+	SubtitleModeSidecar = "sidecar" // 字幕ファイル（SRT・WebVTT）のみ
+	SubtitleModeBurned  = "burned"  // 字幕ファイルに加えて動画に焼き込む
+)
+
 // VlogOutput はVLog生成フローの出力スキーマ
 type VlogOutput struct {
 	VideoID      string          `json:"videoId" jsonschema:"description=生成されたVLogのID"`
 	VideoURL     string          `json:"videoUrl" jsonschema:"description=VLog動画のURL"`
 	ShareURL     string          `json:"shareUrl" jsonschema:"description=共有用URL"`
+	ShareCode    string          `json:"shareCode" jsonschema:"description=共有コード"`
 	ThumbnailURL string          `json:"thumbnailUrl" jsonschema:"description=サムネイル画像のURL"`
+	PreviewURL   string          `json:"previewUrl,omitempty" jsonschema:"description=アニメーションプレビュー（GIF・WebP）のURL"`
 	Duration     float64         `json:"duration" jsonschema:"description=動画の長さ（秒）"`
 	Title        string          `json:"title" jsonschema:"description=VLogのタイトル"`
 	Description  string          `json:"description" jsonschema:"description=VLogの説明文"`
 	Subtitles    []SubtitleEntry `json:"subtitles,omitempty" jsonschema:"description=字幕データ"`
 	Analytics    VlogAnalytics   `json:"analytics" jsonschema:"description=分析結果サマリー"`
+	MusicTrackID string          `json:"musicTrackId,omitempty" jsonschema:"description=BGMに使用した曲のID"`
+	Storyboard   *Storyboard     `json:"storyboard,omitempty" jsonschema:"description=動画の生成に使用した絵コンテ"`
+}
+
+// Storyboard はVLogのシーンごとの構成（絵コンテ）
+// シーンは再生順に並べ、Veoのクリップ生成と字幕の両方に使用する
+type Storyboard struct {
+	Scenes []StoryboardScene `json:"scenes" jsonschema:"description=再生順のシーンのリスト"`
+}
+
+// TotalDuration は絵コンテのシーンの長さの合計（秒）を返す
+func (s *Storyboard) TotalDuration() int {
+	total := 0
+	for _, scene := range s.Scenes {
+		total += scene.DurationSeconds
+	}
+	return total
+}
+
+// StoryboardScene は絵コンテの1シーン
+type StoryboardScene struct {
+	MediaIDs        []string `json:"mediaIds" jsonschema:"description=シーンの元にするメディアのファイルID"`
+	CameraDirection string   `json:"cameraDirection" jsonschema:"description=カメラワークの指示"`
+	VeoPrompt       string   `json:"veoPrompt" jsonschema:"description=Veo用のプロンプト（英語）"`
+	Caption         string   `json:"caption" jsonschema:"description=シーンの字幕"`
+	DurationSeconds int      `json:"durationSeconds" jsonschema:"description=シーンの長さ（秒、4・6・8のいずれか）"`
 }
 
 // SubtitleEntry は字幕の1エントリ
@@ -126,6 +195,7 @@ type FlowStep string
 const (
 	StepInitializing    FlowStep = "initializing"
 	StepAnalyzing       FlowStep = "analyzing"
+	StepStoryboarding   FlowStep = "storyboarding"
 	StepGeneratingVideo FlowStep = "generating_video"
 	StepUploadingVideo  FlowStep = "uploading_video"
 	StepFinalizing      FlowStep = "finalizing"
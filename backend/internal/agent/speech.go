@@ -0,0 +1,23 @@
+package agent
+
+import "context"
+
+// ISpeechSynthesizer はナレーションの音声を合成する音声合成バックエンドのインターフェース
+type ISpeechSynthesizer interface {
+	// Synthesize はテキストを読み上げた音声を合成する
+	Synthesize(ctx context.Context, req SpeechRequest) (*SpeechClip, error)
+}
+
+// SpeechRequest は音声合成のリクエスト
+type SpeechRequest struct {
+	Text       string
+	SpeakerID  int     // 話者（VOICEVOXのスタイルID）
+	SpeedScale float64 // 話速（0の場合は変更しない）
+	PitchScale float64 // 音高（0の場合は変更しない）
+}
+
+// SpeechClip は合成した音声
+type SpeechClip struct {
+	Data            []byte  // WAV
+	DurationSeconds float64 // 音声の長さ（秒）
+}
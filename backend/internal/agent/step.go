@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+// StepName はVLog生成フローのステップ名
+type StepName string
+
+const (
+	StepNameMediaAnalysis   StepName = "media_analysis"   // メディア分析（アイテムごと）
+	StepNameStoryboard      StepName = "storyboard"       // 絵コンテの生成
+	StepNameTitleGeneration StepName = "title_generation" // タイトル・説明文の生成
+	StepNameVeoRequest      StepName = "veo_request"      // Veoへの生成リクエストと完了待ち（シーンごと）
+	StepNameGCSDownload     StepName = "gcs_download"     // 生成動画のGCSからのダウンロード（シーンごと）
+	StepNameConcatenate     StepName = "concatenate"      // シーンのクリップの結合
+	StepNameNarration       StepName = "narration"        // ナレーションの合成とミックス
+	StepNameBackgroundMusic StepName = "background_music" // BGMのミックス
+	StepNameBurnSubtitles   StepName = "burn_subtitles"   // 字幕の焼き込み
+	StepNameR2Upload        StepName = "r2_upload"        // 生成動画のR2へのアップロード
+	StepNameThumbnail       StepName = "thumbnail"        // サムネイル生成
+	StepNameShareURL        StepName = "share_url"        // 共有URL生成
+)
+
+// StepObserver はフローの各ステップの開始・終了を受け取る
+// itemはアイテム・シーン単位のステップの場合は1始まりの番号、それ以外は0
+type StepObserver interface {
+	StepStarted(ctx context.Context, name StepName, item int)
+	StepFinished(ctx context.Context, name StepName, item int, err error)
+	StepSkipped(ctx context.Context, name StepName, item int)
+}
+
+type stepObserverKey struct{}
+
+// WithStepObserver はフローのステップの実行状況を受け取るオブザーバーをコンテキストに設定する
+func WithStepObserver(ctx context.Context, observer StepObserver) context.Context {
+	return context.WithValue(ctx, stepObserverKey{}, observer)
+}
+
+// RunStep はステップを実行し、開始・終了をコンテキストに設定されたオブザーバーに通知する
+func RunStep(ctx context.Context, name StepName, item int, fn func() error) error {
+	observer, ok := ctx.Value(stepObserverKey{}).(StepObserver)
+	if !ok || observer == nil {
+		return fn()
+	}
+	observer.StepStarted(ctx, name, item)
+	err := fn()
+	observer.StepFinished(ctx, name, item, err)
+	return err
+}
+
+// SkipStep は実行しなかったステップをオブザーバーに通知する
+func SkipStep(ctx context.Context, name StepName, item int) {
+	observer, ok := ctx.Value(stepObserverKey{}).(StepObserver)
+	if !ok || observer == nil {
+		return
+	}
+	observer.StepSkipped(ctx, name, item)
+}
+
+// Checkpointer はステップの出力（チェックポイント）を保存し、タスクの再実行時に復元する
+type Checkpointer interface {
+	LoadCheckpoint(ctx context.Context, name StepName, item int) ([]byte, bool)
+	SaveCheckpoint(ctx context.Context, name StepName, item int, data []byte) error
+}
+
+type checkpointerKey struct{}
+
+// WithCheckpointer はステップのチェックポイントを保存・復元するCheckpointerをコンテキストに設定する
+func WithCheckpointer(ctx context.Context, checkpointer Checkpointer) context.Context {
+	return context.WithValue(ctx, checkpointerKey{}, checkpointer)
+}
+
+// LoadCheckpoint は保存済みのチェックポイントをoutに復元する（保存されていない場合はfalse）
+func LoadCheckpoint(ctx context.Context, name StepName, item int, out any) bool {
+	checkpointer, ok := ctx.Value(checkpointerKey{}).(Checkpointer)
+	if !ok || checkpointer == nil {
+		return false
+	}
+	data, ok := checkpointer.LoadCheckpoint(ctx, name, item)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		logger.Warn(ctx, "failed to decode checkpoint", "step", name, "item", item, "error", err)
+		return false
+	}
+	return true
+}
+
+// SaveCheckpoint はステップの出力をチェックポイントとして保存する
+// 保存に失敗した場合は再実行時にステップをやり直すだけのため、処理は継続する
+func SaveCheckpoint(ctx context.Context, name StepName, item int, value any) {
+	checkpointer, ok := ctx.Value(checkpointerKey{}).(Checkpointer)
+	if !ok || checkpointer == nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = checkpointer.SaveCheckpoint(ctx, name, item, data)
+	}
+	if err != nil {
+		logger.Warn(ctx, "failed to save checkpoint", "step", name, "item", item, "error", err)
+	}
+}
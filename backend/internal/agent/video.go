@@ -0,0 +1,37 @@
+package agent
+
+import "context"
+
+// IVideoGenerator はVLogのシーンのクリップを生成する動画生成バックエンドのインターフェース
+type IVideoGenerator interface {
+	// GenerateClip はシーンのクリップを生成する。onProgressには生成の進み具合（0-1）を通知する
+	GenerateClip(ctx context.Context, req VideoClipRequest, onProgress func(fraction float64)) (*VideoClip, error)
+
+	// ReleaseClip はクリップの生成に使用した一時ファイルを削除する
+	ReleaseClip(ctx context.Context, clip *VideoClip)
+}
+
+// VideoClipRequest はシーンのクリップの生成リクエスト
+type VideoClipRequest struct {
+	SceneIndex      int             // 1始まりのシーン番号
+	Prompt          string          // 動画生成用プロンプト
+	DurationSeconds int32           // クリップの長さ（秒）
+	AspectRatio     string          // "16:9" or "9:16"
+	ReferenceImage  *ReferenceImage // ユーザーの写真（写真がない場合はnil）
+	ReferenceMode   string          // 写真の使い方（inspired/animate）
+}
+
+// ReferenceImage は動画生成に渡すユーザーの写真
+type ReferenceImage struct {
+	FileID      string
+	URL         string
+	ContentType string
+}
+
+// VideoClip は生成したシーンのクリップ
+type VideoClip struct {
+	SceneIndex      int
+	Data            []byte  // MP4
+	DurationSeconds float64 // クリップの長さ（秒）
+	SourceURI       string  // 生成元の一時ファイル（GCS URIなど、ない場合は空）
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// 課金商品定数（Checkoutで購入できる商品）
+const (
+	BillingProductTokens100  = "tokens_100"
+	BillingProductTokens500  = "tokens_500"
+	BillingProductTokens1000 = "tokens_1000"
+	BillingProductMonthly    = "monthly"
+	BillingProductYearly     = "yearly"
+)
+
+// Checkoutセッションステータス定数
+const (
+	CheckoutSessionStatusOpen      = "open"
+	CheckoutSessionStatusCompleted = "completed"
+)
+
+// CheckoutSession はクライアントリクエストIDごとに作成したStripe Checkout Session
+type CheckoutSession struct {
+	BaseModel
+	UserID          string `gorm:"column:user_id"`
+	ClientRequestID string `gorm:"column:client_request_id"` // クライアントが発行するべき等キー
+	StripeSessionID string `gorm:"column:stripe_session_id"`
+	Product         string `gorm:"column:product"` // "tokens_100", "monthly"など
+	Type            string `gorm:"column:type"`    // "token_purchase", "subscription"
+	Plan            string `gorm:"column:plan"`    // サブスクリプションの場合のみ "monthly", "yearly"
+	Tokens          int    `gorm:"column:tokens"`  // 付与トークン数
+	Amount          int    `gorm:"column:amount"`  // 金額（円）
+	URL             string `gorm:"column:url"`
+	Status          string `gorm:"column:status"` // "open", "completed"
+}
+
+// ICheckoutSessionRepository - Checkoutセッションリポジトリインターフェース
+type ICheckoutSessionRepository interface {
+	Create(ctx context.Context, session *CheckoutSession) error
+	Update(ctx context.Context, session *CheckoutSession) error
+	FindByClientRequestID(ctx context.Context, userID, clientRequestID string) (*CheckoutSession, error)
+	FindByStripeSessionID(ctx context.Context, stripeSessionID string) (*CheckoutSession, error)
+}
+
+// StripeWebhookEvent は処理済みのStripe Webhookイベント（二重処理防止用）
+type StripeWebhookEvent struct {
+	BaseModel
+	EventID string `gorm:"column:event_id"`
+	Type    string `gorm:"column:type"`
+}
+
+// IStripeWebhookEventRepository - Webhookイベントリポジトリインターフェース
+type IStripeWebhookEventRepository interface {
+	Create(ctx context.Context, event *StripeWebhookEvent) error
+	Exists(ctx context.Context, eventID string) (bool, error)
+}
+
+// ============================================================
+// Stripe APIクライアント
+// ============================================================
+
+// StripeCheckoutParams はCheckout Session作成パラメータ
+type StripeCheckoutParams struct {
+	Product           string
+	Mode              string // "payment", "subscription"
+	SuccessURL        string
+	CancelURL         string
+	ClientReferenceID string
+	IdempotencyKey    string
+	Metadata          map[string]string
+}
+
+// StripeCheckoutSession はStripeのCheckout Session
+type StripeCheckoutSession struct {
+	ID                string
+	URL               string
+	Mode              string
+	CustomerID        string
+	SubscriptionID    string
+	PaymentIntentID   string
+	ClientReferenceID string
+	AmountTotal       int
+	Metadata          map[string]string
+}
+
+// StripeSubscription はStripeのSubscription
+type StripeSubscription struct {
+	ID               string
+	CustomerID       string
+	Status           string // "active", "trialing", "past_due", "canceled", "unpaid"など
+	CurrentPeriodEnd time.Time
+	PriceID          string // 契約中のPrice ID
+	Product          string // Price IDに対応する課金商品（設定にないPrice IDの場合は空）
+	Metadata         map[string]string
+}
+
+// StripeEvent は署名検証済みのWebhookイベント
+type StripeEvent struct {
+	ID              string
+	Type            string
+	CheckoutSession *StripeCheckoutSession // checkout.session.* の場合のみ
+	Subscription    *StripeSubscription    // customer.subscription.* の場合のみ
+}
+
+// IStripeClient - Stripe APIクライアントインターフェース
+type IStripeClient interface {
+	CreateCheckoutSession(ctx context.Context, params *StripeCheckoutParams) (*StripeCheckoutSession, error)
+	GetSubscription(ctx context.Context, id string) (*StripeSubscription, error)
+	// ParseWebhookEvent はStripe-Signatureヘッダーを検証してイベントを返す
+	ParseWebhookEvent(payload []byte, signature string) (*StripeEvent, error)
+}
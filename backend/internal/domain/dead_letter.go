@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// デッドレターステータス定数
+const (
+	DeadLetterStatusPending  = "pending"  // 再実行待ち
+	DeadLetterStatusRequeued = "requeued" // 再実行済み
+)
+
+// DeadLetterTask は最大実行回数に達しても成功しなかったタスク
+type DeadLetterTask struct {
+	BaseModel
+	TaskType            string        `gorm:"column:task_type"`
+	TaskID              string        `gorm:"column:task_id"`
+	TaskVersion         int           `gorm:"column:task_version"`
+	Payload             string        `gorm:"column:payload"`                         // 登録時のタスク（JSON）
+	Errors              []string      `gorm:"column:errors;serializer:json"`          // 最後の失敗のエラーチェーン（外側から順）
+	Attempts            int           `gorm:"column:attempts"`                        // 実行回数
+	AttemptHistory      []TaskAttempt `gorm:"column:attempt_history;serializer:json"` // 各実行の結果
+	Status              string        `gorm:"column:status"`                          // "pending", "requeued"
+	RequeuedAt          *time.Time    `gorm:"column:requeued_at"`
+	RequeuedTaskVersion int           `gorm:"column:requeued_task_version"`
+}
+
+// IDeadLetterTaskRepository - デッドレターリポジトリインターフェース
+type IDeadLetterTaskRepository interface {
+	Create(ctx context.Context, task *DeadLetterTask) error
+	Update(ctx context.Context, task *DeadLetterTask) error
+	FindByID(ctx context.Context, id string) (*DeadLetterTask, error)
+	// FindPendingByTask はタスクの再実行待ちのデッドレターを取得する
+	FindPendingByTask(ctx context.Context, taskType, taskID string) (*DeadLetterTask, error)
+	// List はステータスで絞り込んだデッドレターを新しい順に取得する（statusが空の場合は全件）
+	List(ctx context.Context, status string, opts *ListOpts) ([]*DeadLetterTask, error)
+	Count(ctx context.Context, status string) (int64, error)
+}
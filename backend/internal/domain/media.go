@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"time"
 )
 
 // MediaStatus はメディアの処理状態を表す
@@ -33,6 +34,13 @@ type Media struct {
 	Status       MediaStatus    `gorm:"column:status;default:completed" json:"status"`       // 処理状態
 	Progress     float64        `gorm:"column:progress;default:1.0" json:"progress"`         // 進捗率（0.0〜1.0）
 	ErrorMessage string         `gorm:"column:error_message" json:"error_message,omitempty"` // エラーメッセージ
+	// アップロード時にEXIF・動画のメタデータから取得した撮影情報
+	CapturedAt  *time.Time `gorm:"column:captured_at" json:"captured_at,omitempty"`   // 撮影日時
+	Latitude    *float64   `gorm:"column:latitude" json:"latitude,omitempty"`         // 撮影地点の緯度
+	Longitude   *float64   `gorm:"column:longitude" json:"longitude,omitempty"`       // 撮影地点の経度
+	CameraModel string     `gorm:"column:camera_model" json:"camera_model,omitempty"` // カメラの機種
+	Orientation int        `gorm:"column:orientation" json:"orientation,omitempty"`   // 向き（EXIFのOrientation、1〜8）
+	Duration    float64    `gorm:"column:duration" json:"duration,omitempty"`         // 動画の長さ（秒、画像や取得できない場合は0）
 }
 
 type IMediaRepository interface {
@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// MusicTrack はVLogのBGMに使用する曲
+// ライブラリの曲は雰囲気・BPMで分類し、ユーザーがアップロードした曲はアップロードしたVLogでのみ使用する
+type MusicTrack struct {
+	BaseModel
+	UserID          *string `gorm:"column:user_id"` // アップロードしたユーザー（ライブラリの曲はnil）
+	Title           string  `gorm:"column:title"`
+	Mood            string  `gorm:"column:mood"` // 雰囲気（VlogStyle.MusicMood・分析結果の雰囲気と照合する）
+	BPM             int     `gorm:"column:bpm"`
+	ObjectKey       string  `gorm:"column:object_key"` // R2のオブジェクトキー
+	ContentType     string  `gorm:"column:content_type"`
+	DurationSeconds float64 `gorm:"column:duration_seconds"` // 曲の長さ（秒、不明の場合は0）
+}
+
+// IMusicTrackRepository - BGMリポジトリインターフェース
+type IMusicTrackRepository interface {
+	Create(ctx context.Context, track *MusicTrack) error
+	FindByID(ctx context.Context, id string) (*MusicTrack, error)
+	// FindLibraryByMood は雰囲気が一致するライブラリの曲を取得する
+	FindLibraryByMood(ctx context.Context, mood string) ([]*MusicTrack, error)
+}
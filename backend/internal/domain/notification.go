@@ -7,10 +7,13 @@ import (
 
 // 通知タイプ定数
 const (
-	NotificationTypeMediaCompleted = "media_completed"
-	NotificationTypeMediaFailed    = "media_failed"
-	NotificationTypeVlogCompleted  = "vlog_completed"
-	NotificationTypeVlogFailed     = "vlog_failed"
+	NotificationTypeMediaCompleted      = "media_completed"
+	NotificationTypeMediaFailed         = "media_failed"
+	NotificationTypeVlogCompleted       = "vlog_completed"
+	NotificationTypeVlogFailed          = "vlog_failed"
+	NotificationTypeVlogStoryboardReady = "vlog_storyboard_ready"
+	NotificationTypeTokenRefilled       = "token_refilled"
+	NotificationTypePlanExpired         = "plan_expired"
 )
 
 // Notification - 通知ドメインモデル
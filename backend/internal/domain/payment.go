@@ -1,19 +1,36 @@
 package domain
 
 import (
+	"context"
 	"time"
+)
+
+// 決済タイプ定数
+const (
+	PaymentTypeTokenPurchase = "token_purchase" // トークン購入
+	PaymentTypeSubscription  = "subscription"   // サブスクリプション
+)
 
-	"gorm.io/gorm"
+// 決済ステータス定数
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusCompleted = "completed"
+	PaymentStatusFailed    = "failed"
 )
 
 type Payment struct {
-	gorm.Model
-	UID             string     `gorm:"column:uid"`
+	BaseModel
+	UserID          string     `gorm:"column:user_id"`
 	Type            string     `gorm:"column:type"`           // "token_purchase", "subscription"
 	Amount          int        `gorm:"column:amount"`         // 金額（円）
 	TokensGranted   int        `gorm:"column:tokens_granted"` // 付与トークン数
 	Status          string     `gorm:"column:status"`         // "pending", "completed", "failed"
 	StripePaymentID string     `gorm:"column:stripe_payment_id"`
-	CreatedAt       time.Time  `gorm:"column:created_at"`
+	StripeSessionID string     `gorm:"column:stripe_session_id"` // Checkout SessionのID
 	CompletedAt     *time.Time `gorm:"column:completed_at"`
 }
+
+// IPaymentRepository - 決済リポジトリインターフェース
+type IPaymentRepository interface {
+	Create(ctx context.Context, payment *Payment) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProgressEvent はSSEで配信する進捗イベント
+// IDはイベントの発行順に単調増加し、SSEのLast-Event-IDとして使用する
+type ProgressEvent struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Topic     string    `gorm:"column:topic" json:"topic"` // 購読単位（vlog:<id>、media:<id>）
+	Name      string    `gorm:"column:name" json:"name"`   // SSEのイベント名（空の場合はmessage）
+	Data      string    `gorm:"column:data" json:"data"`   // JSON
+	Final     bool      `gorm:"column:final" json:"final"` // トピックの最後のイベントかどうか
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// IProgressEventRepository - 進捗イベントリポジトリインターフェース
+type IProgressEventRepository interface {
+	Create(ctx context.Context, event *ProgressEvent) error
+	// ListAfter はafterIDより後のイベントをID順に取得する
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*ProgressEvent, error)
+	// ListByTopicsAfter は指定したトピックのafterIDより後のイベントをID順に取得する
+	ListByTopicsAfter(ctx context.Context, topics []string, afterID int64, limit int) ([]*ProgressEvent, error)
+	// LatestID は最新のイベントIDを取得する（イベントがない場合は0）
+	LatestID(ctx context.Context) (int64, error)
+	// DeleteBefore は指定日時より前のイベントを削除する
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
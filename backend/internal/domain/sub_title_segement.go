@@ -1,11 +1,40 @@
 package domain
 
-import "gorm.io/gorm"
+import "github.com/o-ga09/zenn-hackthon-2026/pkg/subtitle"
 
+// SubtitleSegment はVLogの字幕の1区間
 type SubtitleSegment struct {
-	gorm.Model
-	Index int    `gorm:"column:index" json:"index"`
-	Start string `gorm:"column:start" json:"start"` // "00:00:01,000"
-	End   string `gorm:"column:end" json:"end"`     // "00:00:04,000"
-	Text  string `gorm:"column:text" json:"text"`   // 表示テキスト
+	BaseModel
+	VlogID string `gorm:"column:vlog_id" json:"vlog_id"`
+	Index  int    `gorm:"column:index" json:"index"` // 表示順（1始まり）
+	Start  string `gorm:"column:start" json:"start"` // "00:00:01,000"
+	End    string `gorm:"column:end" json:"end"`     // "00:00:04,000"
+	Text   string `gorm:"column:text" json:"text"`   // 表示テキスト
+}
+
+// NewSubtitleSegment は秒で指定した区間の字幕を作成する
+func NewSubtitleSegment(index int, start, end float64, text string) SubtitleSegment {
+	return SubtitleSegment{
+		Index: index,
+		Start: subtitle.FormatTime(start, subtitle.FormatSRT),
+		End:   subtitle.FormatTime(end, subtitle.FormatSRT),
+		Text:  text,
+	}
+}
+
+// StartSeconds は開始時間を秒で返す
+func (s *SubtitleSegment) StartSeconds() float64 {
+	sec, _ := subtitle.ParseTime(s.Start)
+	return sec
+}
+
+// EndSeconds は終了時間を秒で返す
+func (s *SubtitleSegment) EndSeconds() float64 {
+	sec, _ := subtitle.ParseTime(s.End)
+	return sec
+}
+
+// Cue は字幕ファイルに書き出す区間を返す
+func (s *SubtitleSegment) Cue() subtitle.Cue {
+	return subtitle.Cue{Start: s.StartSeconds(), End: s.EndSeconds(), Text: s.Text}
 }
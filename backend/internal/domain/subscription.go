@@ -1,19 +1,60 @@
 package domain
 
 import (
+	"context"
 	"time"
+)
+
+// サブスクリプションプラン定数
+const (
+	SubscriptionPlanMonthly = "monthly"
+	SubscriptionPlanYearly  = "yearly"
+)
 
-	"gorm.io/gorm"
+// サブスクリプションステータス定数
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusCancelled = "cancelled"
+	SubscriptionStatusExpired   = "expired"
 )
 
 type Subscription struct {
-	gorm.Model
-	UID              string    `gorm:"column:uid"`
+	BaseModel
+	UserID           string    `gorm:"column:user_id"`
 	Plan             string    `gorm:"column:plan"`   // "monthly", "yearly"
 	Status           string    `gorm:"column:status"` // "active", "cancelled", "expired"
 	StripeCustomerID string    `gorm:"column:stripe_customer_id"`
 	StripeSubID      string    `gorm:"column:stripe_subscription_id"`
 	CurrentPeriodEnd time.Time `gorm:"column:current_period_end"`
-	CreatedAt        time.Time `gorm:"column:created_at"`
-	UpdatedAt        time.Time `gorm:"column:updated_at"`
+	// トークン付与済みの付与期間（1か月）の終了日時（現在日時を過ぎたら次回付与対象）
+	LastRefilledPeriodEnd *time.Time `gorm:"column:last_refilled_period_end"`
+}
+
+// RefillPeriodEnd はnowを含むトークン付与期間の終了日時を返す
+// トークンは契約期間にかかわらず毎月付与するため、年額プランは契約期間終了日時から1か月ずつ遡って区切る
+func (s *Subscription) RefillPeriodEnd(now time.Time) time.Time {
+	if s.Plan != SubscriptionPlanYearly {
+		return s.CurrentPeriodEnd
+	}
+	for months := 11; months > 0; months-- {
+		if end := s.CurrentPeriodEnd.AddDate(0, -months, 0); end.After(now) {
+			return end
+		}
+	}
+	return s.CurrentPeriodEnd
+}
+
+// ISubscriptionRepository - サブスクリプションリポジトリインターフェース
+type ISubscriptionRepository interface {
+	Create(ctx context.Context, subscription *Subscription) error
+	Update(ctx context.Context, subscription *Subscription) error
+	FindByStripeSubscriptionID(ctx context.Context, stripeSubID string) (*Subscription, error)
+	// FindActiveByUserID ユーザーの有効なサブスクリプションを取得
+	FindActiveByUserID(ctx context.Context, userID string) (*Subscription, error)
+	// FindDueForRefill 付与済みの付与期間がnowまでに終了し、契約期間内の有効なサブスクリプションを取得
+	FindDueForRefill(ctx context.Context, now time.Time) ([]*Subscription, error)
+	// FindLapsed 契約期間終了日時がbeforeより前のまま更新されていない有効なサブスクリプションを取得
+	FindLapsed(ctx context.Context, before time.Time) ([]*Subscription, error)
+	// FindUserIDsToDowngrade 有効なサブスクリプションがないプレミアムユーザーのIDを取得
+	FindUserIDsToDowngrade(ctx context.Context) ([]string, error)
 }
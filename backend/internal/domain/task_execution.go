@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// タスク実行ステータス定数
+const (
+	TaskExecutionStatusRunning   = "running"
+	TaskExecutionStatusCompleted = "completed"
+	TaskExecutionStatusFailed    = "failed"
+	TaskExecutionStatusDead      = "dead" // 最大実行回数に達し、デッドレターに移動済み
+)
+
+// TaskExecution はタスクID・バージョン単位の実行記録
+// 実行中のワーカー（Owner）はリース期限まで実行権を持ち、期限切れのリースは他のワーカーが引き継げる
+type TaskExecution struct {
+	BaseModel
+	TaskType       string        `gorm:"column:task_type"`
+	TaskID         string        `gorm:"column:task_id"`
+	TaskVersion    int           `gorm:"column:task_version"`
+	Status         string        `gorm:"column:status"` // "running", "completed", "failed", "dead"
+	Owner          string        `gorm:"column:owner"`
+	LeaseExpiresAt time.Time     `gorm:"column:lease_expires_at"`
+	Attempts       int           `gorm:"column:attempts"`
+	LastError      string        `gorm:"column:last_error"`
+	CompletedAt    *time.Time    `gorm:"column:completed_at"`
+	AttemptHistory []TaskAttempt `gorm:"column:attempt_history;serializer:json"`
+}
+
+// TaskAttempt は1回分の実行結果
+type TaskAttempt struct {
+	Attempt    int       `json:"attempt"`
+	Owner      string    `json:"owner"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// ITaskExecutionRepository - タスク実行記録リポジトリインターフェース
+type ITaskExecutionRepository interface {
+	Create(ctx context.Context, execution *TaskExecution) error
+	Update(ctx context.Context, execution *TaskExecution) error
+	FindByTask(ctx context.Context, taskType, taskID string, taskVersion int) (*TaskExecution, error)
+	// FindLatestVersion はタスクIDで記録されている最大のバージョンを返す（記録がない場合は0）
+	FindLatestVersion(ctx context.Context, taskType, taskID string) (int, error)
+}
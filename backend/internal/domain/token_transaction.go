@@ -1,15 +1,35 @@
 package domain
 
 import (
-	"gorm.io/gorm"
+	"context"
+)
+
+// トークン取引タイプ定数
+const (
+	TokenTransactionTypePurchase    = "purchase"    // トークン購入
+	TokenTransactionTypeConsumption = "consumption" // 消費（仮引きの確定）
+	TokenTransactionTypeBonus       = "bonus"       // ボーナス付与
+	TokenTransactionTypeRefund      = "refund"      // 返金
+	TokenTransactionTypeReserve     = "reserve"     // 仮引き
+	TokenTransactionTypeRollback    = "rollback"    // 仮引きの取り消し
 )
 
 type TokenTransaction struct {
-	gorm.Model
-	UID         string                 `gorm:"column:uid"`
-	Type        string                 `gorm:"column:type"`        // "purchase", "consumption", "bonus", "refund"
-	Amount      int                    `gorm:"column:amount"`      // トークン数（消費時はマイナス）
-	Balance     int                    `gorm:"column:balance"`     // 取引後の残高
-	Description string                 `gorm:"column:description"` // "動画生成", "月額プラン付与"など
-	Metadata    map[string]interface{} `gorm:"column:metadata"`
+	BaseModel
+	UserID      string                 `gorm:"column:user_id" json:"user_id"`
+	Type        string                 `gorm:"column:type" json:"type"`                           // "purchase", "consumption", "bonus", "refund", "reserve", "rollback"
+	Amount      int                    `gorm:"column:amount" json:"amount"`                       // トークン数（消費時はマイナス）
+	Balance     int                    `gorm:"column:balance" json:"balance"`                     // 取引後の残高
+	Description string                 `gorm:"column:description" json:"description"`             // "動画生成", "月額プラン付与"など
+	ReferenceID string                 `gorm:"column:reference_id" json:"reference_id,omitempty"` // べき等キー（VLog IDなど）
+	Metadata    map[string]interface{} `gorm:"column:metadata;serializer:json" json:"metadata,omitempty"`
+}
+
+// ITokenTransactionRepository - トークン取引リポジトリインターフェース
+type ITokenTransactionRepository interface {
+	Create(ctx context.Context, transaction *TokenTransaction) error
+	Update(ctx context.Context, transaction *TokenTransaction) error
+	FindByReferenceID(ctx context.Context, referenceID string) ([]*TokenTransaction, error)
+	FindByUserID(ctx context.Context, userID string, opts *ListOpts) ([]*TokenTransaction, error)
+	CountByUserID(ctx context.Context, userID string) (int64, error)
 }
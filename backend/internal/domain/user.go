@@ -38,6 +38,8 @@ type IUserRepository interface {
 	Update(ctx context.Context, user *User) error
 	// Delete ユーザーを削除（論理削除）
 	Delete(ctx context.Context, cond *User) error
+	// AddTokenBalance トークン残高を加減算し、更新後の残高を返す（残高がマイナスになる場合はエラー）
+	AddTokenBalance(ctx context.Context, id string, delta int) (int, error)
 }
 
 type IUserStorage interface {
@@ -18,24 +18,56 @@ func (s VlogStatus) Equals(other VlogStatus) bool {
 }
 
 const (
-	VlogStatusPending    VlogStatus = "pending"
-	VlogStatusProcessing VlogStatus = "processing"
-	VlogStatusCompleted  VlogStatus = "completed"
-	VlogStatusFailed     VlogStatus = "failed"
+	VlogStatusPending         VlogStatus = "pending"
+	VlogStatusProcessing      VlogStatus = "processing"
+	VlogStatusStoryboardReady VlogStatus = "storyboard_ready" // 絵コンテを作成し、ユーザーの確認を待っている
+	VlogStatusCompleted       VlogStatus = "completed"
+	VlogStatusFailed          VlogStatus = "failed"
+	VlogStatusCancelled       VlogStatus = "cancelled"
 )
 
+// IsCancellable はキャンセル可能な状態（生成待ち・生成中・絵コンテの確認待ち）かどうかを返す
+func (s VlogStatus) IsCancellable() bool {
+	return s == VlogStatusPending || s == VlogStatusProcessing || s == VlogStatusStoryboardReady
+}
+
 type Vlog struct {
 	BaseModel
-	VideoID      string     `gorm:"column:video_id" json:"video_id"`
-	VideoURL     string     `gorm:"column:video_url" json:"video_url"`
-	ShareURL     string     `gorm:"column:share_url" json:"share_url"`
-	Duration     float64    `gorm:"column:duration" json:"duration"`
-	Thumbnail    string     `gorm:"column:thumbnail" json:"thumbnail"`
-	Status       VlogStatus `gorm:"column:status;default:pending" json:"status"`
-	ErrorMessage string     `gorm:"column:error_message" json:"error_message,omitempty"`
-	Progress     float64    `gorm:"column:progress;default:0" json:"progress"`
-	StartedAt    *time.Time `gorm:"column:started_at" json:"started_at,omitempty"`
-	CompletedAt  *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	VideoID          string            `gorm:"column:video_id" json:"video_id"`
+	Title            string            `gorm:"column:title" json:"title"`
+	Description      string            `gorm:"column:description" json:"description"`
+	VideoURL         string            `gorm:"column:video_url" json:"video_url"`
+	ShareURL         string            `gorm:"column:share_url" json:"share_url"`
+	Duration         float64           `gorm:"column:duration" json:"duration"`
+	Thumbnail        string            `gorm:"column:thumbnail" json:"thumbnail"`
+	Preview          string            `gorm:"column:preview" json:"preview,omitempty"` // アニメーションプレビュー（GIF・WebP）のURL
+	Status           VlogStatus        `gorm:"column:status;default:pending" json:"status"`
+	ErrorMessage     string            `gorm:"column:error_message" json:"error_message,omitempty"`
+	Progress         float64           `gorm:"column:progress;default:0" json:"progress"`
+	StartedAt        *time.Time        `gorm:"column:started_at" json:"started_at,omitempty"`
+	CompletedAt      *time.Time        `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	TokenReferenceID string            `gorm:"column:token_reference_id" json:"-"` // 仮引き中のトークンの参照ID（空の場合はVLog ID）
+	CancelledAt      *time.Time        `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	Analytics        VlogAnalytics     `gorm:"embedded;embeddedPrefix:analytics_" json:"analytics"`
+	MusicTrackID     *string           `gorm:"column:music_track_id" json:"music_track_id,omitempty"` // BGMに使用した曲（BGMなしの場合はnil）
+	Subtitles        []SubtitleSegment `gorm:"foreignKey:VlogID" json:"subtitles"`
+}
+
+// VlogAnalytics はVLogに使用したメディアの分析結果のサマリー
+type VlogAnalytics struct {
+	Locations  []string `gorm:"column:locations;serializer:json" json:"locations"`
+	Activities []string `gorm:"column:activities;serializer:json" json:"activities"`
+	Mood       string   `gorm:"column:mood" json:"mood"`
+	Highlights []string `gorm:"column:highlights;serializer:json" json:"highlights"`
+	MediaCount int      `gorm:"column:media_count" json:"media_count"`
+}
+
+// ReservationReferenceID は仮引き中のトークンの参照IDを返す
+func (v *Vlog) ReservationReferenceID() string {
+	if v.TokenReferenceID != "" {
+		return v.TokenReferenceID
+	}
+	return v.ID
 }
 
 type IVLogRepository interface {
@@ -45,6 +77,8 @@ type IVLogRepository interface {
 	Create(ctx context.Context, vlog *Vlog) error
 	Update(ctx context.Context, vlog *Vlog) error
 	UpdateStatus(ctx context.Context, vlog *Vlog) error
+	// ReplaceSubtitles はVLogの字幕をvlog.Subtitlesで置き換える
+	ReplaceSubtitles(ctx context.Context, vlog *Vlog) error
 }
 
 type ListOptions struct {
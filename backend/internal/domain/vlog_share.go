@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// VlogShare はVLogの共有リンク
+// 共有コードは再発行すると以前のコードを無効にする
+type VlogShare struct {
+	BaseModel
+	VlogID       string     `gorm:"column:vlog_id"`
+	Code         string     `gorm:"column:code"`
+	PasswordHash *string    `gorm:"column:password_hash"` // パスワード未設定の場合はnil
+	ExpiresAt    *time.Time `gorm:"column:expires_at"`    // 無期限の場合はnil
+	ViewCount    int        `gorm:"column:view_count"`
+	RevokedAt    *time.Time `gorm:"column:revoked_at"`
+}
+
+// HasPassword はパスワードが設定されているかどうかを返す
+func (s *VlogShare) HasPassword() bool {
+	return s.PasswordHash != nil && *s.PasswordHash != ""
+}
+
+// IsExpired は有効期限が過ぎているかどうかを返す
+func (s *VlogShare) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// IVlogShareRepository - VLog共有リンクリポジトリインターフェース
+type IVlogShareRepository interface {
+	Create(ctx context.Context, share *VlogShare) error
+	// FindByCode は無効化されていない共有リンクを共有コードで取得する
+	FindByCode(ctx context.Context, code string) (*VlogShare, error)
+	// FindActiveByVlogID はVLogの無効化されていない共有リンクを取得する
+	FindActiveByVlogID(ctx context.Context, vlogID string) (*VlogShare, error)
+	// RevokeByVlogID はVLogの共有リンクをすべて無効にする
+	RevokeByVlogID(ctx context.Context, vlogID string, revokedAt time.Time) error
+	// IncrementViewCount は閲覧数を1増やす
+	IncrementViewCount(ctx context.Context, id string) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// VLog生成ステップのステータス定数
+const (
+	VlogStepStatusRunning   = "running"
+	VlogStepStatusCompleted = "completed"
+	VlogStepStatusFailed    = "failed"
+	VlogStepStatusSkipped   = "skipped" // 分析済みのメディアなど、実行不要だったステップ
+)
+
+// VlogStep はVLog生成フローのステップごとの実行記録
+// 同じステップが再実行された場合は記録を更新し、再実行回数（RetryCount）を加算する
+// 再実行時はCheckpointに保存した出力から処理を再開する
+type VlogStep struct {
+	BaseModel
+	VlogID       string     `gorm:"column:vlog_id"`
+	Name         string     `gorm:"column:name"`
+	ItemIndex    int        `gorm:"column:item_index"` // アイテム単位のステップの番号（1始まり、それ以外は0）
+	Status       string     `gorm:"column:status"`     // "running", "completed", "failed", "skipped"
+	StartedAt    time.Time  `gorm:"column:started_at"`
+	EndedAt      *time.Time `gorm:"column:ended_at"`
+	RetryCount   int        `gorm:"column:retry_count"`
+	ErrorMessage string     `gorm:"column:error_message"`
+	Checkpoint   *string    `gorm:"column:checkpoint"` // 再実行時に再開するためのステップの出力（JSON）
+}
+
+// Duration はステップの所要時間を返す（終了していない場合は0）
+func (s *VlogStep) Duration() time.Duration {
+	if s.EndedAt == nil {
+		return 0
+	}
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// IVlogStepRepository - VLog生成ステップリポジトリインターフェース
+type IVlogStepRepository interface {
+	Create(ctx context.Context, step *VlogStep) error
+	Update(ctx context.Context, step *VlogStep) error
+	// ListByVlogID はVLogのステップを開始日時の順に返す
+	ListByVlogID(ctx context.Context, vlogID string) ([]*VlogStep, error)
+}
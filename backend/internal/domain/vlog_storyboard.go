@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// VlogStoryboard はVLogの絵コンテ
+// 絵コンテの確認を指定したVLogはユーザーが確認・編集してから動画を生成する
+type VlogStoryboard struct {
+	BaseModel
+	VlogID     string            `gorm:"column:vlog_id"`
+	Scenes     []StoryboardScene `gorm:"column:scenes;serializer:json"`
+	Input      string            `gorm:"column:input"`       // 動画の生成を再開するためのVLog生成の入力（JSON）
+	ApprovedAt *time.Time        `gorm:"column:approved_at"` // 動画の生成を開始した日時（未確認の場合はnil）
+}
+
+// StoryboardScene は絵コンテの1シーン
+type StoryboardScene struct {
+	MediaIDs        []string `json:"media_ids"`
+	CameraDirection string   `json:"camera_direction"`
+	VeoPrompt       string   `json:"veo_prompt"`
+	Caption         string   `json:"caption"`
+	DurationSeconds int      `json:"duration_seconds"`
+}
+
+// TotalDuration はシーンの長さの合計（秒）を返す
+func (s *VlogStoryboard) TotalDuration() int {
+	total := 0
+	for _, scene := range s.Scenes {
+		total += scene.DurationSeconds
+	}
+	return total
+}
+
+// IVlogStoryboardRepository - VLog絵コンテリポジトリインターフェース
+type IVlogStoryboardRepository interface {
+	FindByVlogID(ctx context.Context, vlogID string) (*VlogStoryboard, error)
+	Create(ctx context.Context, storyboard *VlogStoryboard) error
+	Update(ctx context.Context, storyboard *VlogStoryboard) error
+}
@@ -17,23 +17,28 @@ import (
 	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/storage"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
 	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/image"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/metadata"
 	nullvalue "github.com/o-ga09/zenn-hackthon-2026/pkg/null_value"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/uuid"
 	"gorm.io/gorm"
 )
 
 type IAgentServer interface {
 	CreateVLog(echo.Context) error
+	CreateDraft(echo.Context) error
 	AnalyzeMedia(echo.Context) error
 	StreamAnalysisStatus(echo.Context) error
-	ProcessVLogTask(echo.Context) error
-	ProcessMediaAnalysisTask(echo.Context) error
 }
 
 type AgentServer struct {
@@ -45,9 +50,15 @@ type AgentServer struct {
 	taskClient         queue.IQueue
 	txManager          domain.ITransactionManager
 	notificationRepo   domain.INotificationRepository
+	tokenLedger        service.ITokenLedger
+	progressBus        progress.IBus
+	vlogStepRepo       domain.IVlogStepRepository
+	shareService       service.IVlogShareService
+	musicTrackRepo     domain.IMusicTrackRepository
+	storyboardRepo     domain.IVlogStoryboardRepository
 }
 
-func NewAgentServer(ctx context.Context, storage domain.IImageStorage, agentInstance agent.IAgent, vlogRepo domain.IVLogRepository, mediaRepo domain.IMediaRepository, mediaAnalyticsRepo domain.IMediaAnalyticsRepository, taskClient queue.IQueue, txManager domain.ITransactionManager, notificationRepo domain.INotificationRepository) *AgentServer {
+func NewAgentServer(ctx context.Context, storage domain.IImageStorage, agentInstance agent.IAgent, vlogRepo domain.IVLogRepository, mediaRepo domain.IMediaRepository, mediaAnalyticsRepo domain.IMediaAnalyticsRepository, taskClient queue.IQueue, txManager domain.ITransactionManager, notificationRepo domain.INotificationRepository, tokenLedger service.ITokenLedger, progressBus progress.IBus, vlogStepRepo domain.IVlogStepRepository, shareService service.IVlogShareService, musicTrackRepo domain.IMusicTrackRepository, storyboardRepo domain.IVlogStoryboardRepository) *AgentServer {
 	return &AgentServer{
 		storage:            storage,
 		agent:              agentInstance,
@@ -57,11 +68,29 @@ func NewAgentServer(ctx context.Context, storage domain.IImageStorage, agentInst
 		taskClient:         taskClient,
 		txManager:          txManager,
 		notificationRepo:   notificationRepo,
+		tokenLedger:        tokenLedger,
+		progressBus:        progressBus,
+		vlogStepRepo:       vlogStepRepo,
+		shareService:       shareService,
+		musicTrackRepo:     musicTrackRepo,
+		storyboardRepo:     storyboardRepo,
 	}
 }
 
 // CreateVLog はメディアからVLogを生成する
 func (s *AgentServer) CreateVLog(c echo.Context) error {
+	return s.createVLog(c, false)
+}
+
+// CreateDraft はメディアの分析と構成の作成のみを行い、編集可能なVLogの下書きを作成する
+// 動画（Veo）の生成は下書きの編集後に POST /api/vlogs/drafts/:id/render で開始する
+func (s *AgentServer) CreateDraft(c echo.Context) error {
+	return s.createVLog(c, true)
+}
+
+// createVLog はVLog生成タスクを登録する
+// draftの場合は絵コンテの確認を指定した場合と同様に、動画を生成せずに下書き（絵コンテ）の確認待ちにする
+func (s *AgentServer) createVLog(c echo.Context, draft bool) error {
 	ctx := c.Request().Context()
 	// ユーザーIDをコンテキストから取得
 	userIDStr := Ctx.GetCtxFromUser(ctx)
@@ -117,71 +146,87 @@ func (s *AgentServer) CreateVLog(c echo.Context) error {
 				URL:         media.URL.String,
 				ContentType: media.ContentType,
 				Type:        detectMediaType(media.ContentType),
+				Timestamp:   capturedAtString(media),
+				Location:    mediaLocation(media),
+				Duration:    media.Duration,
 				IsAnalyzed:  isAnalyzed,
 			})
 		}
 	}
 
+	// 撮影日時の順に並べ、同じ場所で撮影したメディアをまとめる
+	mediaItems = service.ArrangeMediaItems(mediaItems)
+
 	// スタイル設定を取得
-	d := constant.DefaultVLogDurationSeconds
-	if req.Duration != nil && ptr.PtrToInt(req.Duration) < d {
-		d = ptr.PtrToInt(req.Duration)
+	style := agent.VlogStyle{
+		Theme:         ptr.PtrToString(req.Theme),
+		MusicMood:     ptr.PtrToString(req.MusicMood),
+		Duration:      resolveVlogDuration(req.Duration),
+		Transition:    ptr.PtrToString(req.Transition),
+		ReferenceMode: ptr.PtrToString(req.ReferenceMode),
+		SubtitleMode:  ptr.PtrToString(req.SubtitleMode),
+		Narration:     resolveNarrationStyle(&req),
 	}
 
-	style := agent.VlogStyle{
-		Theme:      ptr.PtrToString(req.Theme),
-		MusicMood:  ptr.PtrToString(req.MusicMood),
-		Duration:   d,
-		Transition: ptr.PtrToString(req.Transition),
+	// 3. BGMがアップロードされた場合はライブラリの曲の代わりに使用する
+	if req.BGM != nil {
+		track, err := s.uploadMusicTrack(ctx, userIDStr, req.BGM)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		style.MusicTrackID = track.ID
+	}
+
+	// 旅行日が指定されていない場合は最初に撮影したメディアの撮影日を使用する
+	travelDate := ptr.PtrToString(req.TravelDate)
+	if travelDate == "" {
+		travelDate = service.TravelDateFromMedia(mediaItems)
 	}
 
 	// 入力を構築
 	input := &agent.VlogInput{
-		UserID:      userIDStr,
-		MediaItems:  mediaItems,
-		Title:       ptr.PtrToString(req.Title),
-		TravelDate:  ptr.PtrToString(req.TravelDate),
-		Destination: ptr.PtrToString(req.Destination),
-		Style:       style,
+		UserID:           userIDStr,
+		MediaItems:       mediaItems,
+		Title:            ptr.PtrToString(req.Title),
+		TravelDate:       travelDate,
+		Destination:      ptr.PtrToString(req.Destination),
+		Style:            style,
+		ReviewStoryboard: draft || ptr.PtrToBool(req.ReviewStoryboard),
+	}
+
+	// 必要トークン数を見積もる
+	// 絵コンテを確認する場合は分析と構成の作成分のみを仮引きし、動画の生成分は生成開始時に仮引きする
+	estimate := service.EstimateVlogTokenCost(input)
+	description := "VLog生成"
+	if draft {
+		description = "VLog下書き作成"
 	}
 
-	// VLogレコードをPENDINGステータスで作成
+	// VLogレコードをPENDINGステータスで作成し、同一トランザクションでトークンを仮引きする
 	vlog := &domain.Vlog{
 		Status: domain.VlogStatusPending,
 	}
-	if err := s.vlogRepo.Create(ctx, vlog); err != nil {
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.vlogRepo.Create(ctx, vlog); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		return s.tokenLedger.Reserve(ctx, userIDStr, vlog.ID, estimate.Total, description)
+	})
+	if err != nil {
 		return errors.Wrap(ctx, err)
 	}
 
-	// Cloud Tasksにタスクを登録
-	payload := &queue.Task{
-		ID:      vlog.ID,
-		Version: vlog.Version,
-		Type:    "ProcessVLogTask",
-		Data:    input,
-		Status:  domain.MediaStatusPending.String(),
-	}
-
-	cfg := config.GetCtxEnv(ctx)
-	if cfg.Env == "local" {
-		// 新しいバックグラウンドコンテキストを作成
-		bgCtx := context.Background()
-		bgCtx = Ctx.SetConfig(bgCtx, cfg)
-		bgCtx = Ctx.SetCtxFromUser(bgCtx, userIDStr)
-		bgCtx = Ctx.SetRequestTime(bgCtx, time.Now())
-		bgCtx = Ctx.SetDB(bgCtx, Ctx.GetDB(ctx))
-
-		go func() {
-			// ローカル環境ではGoroutineで直接実行
-			if err := s.executeVLogGeneration(bgCtx, payload); err != nil {
-				// エラーはログに出力（DBなどのステータスはexecuteVLogGeneration内で更新済み）
-				fmt.Printf("Local VLog generation failed: %v\n", err)
-			}
-		}()
-	} else {
-		if err := s.taskClient.Enqueue(ctx, payload); err != nil {
-			return errors.Wrap(ctx, err)
+	// タスクキューに登録
+	task, err := queue.NewTask(queue.TaskTypeProcessVLog, vlog.ID, vlog.Version, input, domain.MediaStatusPending.String())
+	if err == nil {
+		err = s.taskClient.Enqueue(ctx, task)
+	}
+	if err != nil {
+		// タスク登録に失敗した場合は仮引きを取り消す
+		if rbErr := s.tokenLedger.Rollback(ctx, vlog.ID); rbErr != nil {
+			fmt.Printf("[CreateVLog] Failed to rollback token reservation: %v\n", rbErr)
 		}
+		return errors.Wrap(ctx, err)
 	}
 	fmt.Println(input.MediaItems[0].IsAnalyzed)
 	return c.JSON(http.StatusAccepted, response.CreateVLogResponse{
@@ -190,39 +235,22 @@ func (s *AgentServer) CreateVLog(c echo.Context) error {
 	})
 }
 
-// ProcessVLogTask はCloud Tasksからのリクエストを受け取り、VLog生成を非同期に実行する
-func (s *AgentServer) ProcessVLogTask(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	var task queue.Task
-	if err := c.Bind(&task); err != nil {
-		return errors.Wrap(ctx, err)
-	}
-
-	if err := s.executeVLogGeneration(ctx, &task); err != nil {
-		return errors.Wrap(ctx, err)
-	}
-
-	return c.NoContent(http.StatusNoContent)
-}
+// キャンセル要求を確認する間隔
+const vlogCancelCheckInterval = 5 * time.Second
 
 // executeVLogGeneration はVLog生成のコアロジックを実行する
-func (s *AgentServer) executeVLogGeneration(ctx context.Context, task *queue.Task) error {
-	// タスクデータから*agent.VlogInputを取得
-	b, err := json.Marshal(task.Data)
-	if err != nil {
-		return errors.Wrap(ctx, fmt.Errorf("failed to marshal task data: %w", err))
-	}
-	var vlogInput *agent.VlogInput
-	if err := json.Unmarshal(b, &vlogInput); err != nil {
-		return errors.Wrap(ctx, fmt.Errorf("failed to unmarshal task data to VlogInput: %w", err))
-	}
-
+func (s *AgentServer) executeVLogGeneration(ctx context.Context, task *queue.Task, vlogInput *agent.VlogInput) error {
 	// 最新のVlogレコードを取得
 	vlogRef, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: task.ID}})
 	if err != nil {
 		return errors.Wrap(ctx, err)
 	}
+	// キャンセル済みの場合は生成しない（トークンはキャンセル時に返却済み）
+	if vlogRef.Status == domain.VlogStatusCancelled {
+		logger.Info(ctx, "skip cancelled vlog", "vlog_id", vlogRef.ID)
+		return nil
+	}
+	tokenReferenceID := vlogRef.ReservationReferenceID()
 
 	// ステータスをPROCESSINGに更新
 	now := time.Now()
@@ -231,34 +259,75 @@ func (s *AgentServer) executeVLogGeneration(ctx context.Context, task *queue.Tas
 	vlogRef.Progress = 0.1
 	vlogRef.StartedAt = &now
 	if err := s.vlogRepo.Update(ctx, vlogRef); err != nil {
+		if errors.Is(err, errors.ErrOptimisticLock) && s.isVLogCancelled(ctx, vlogRef.ID) {
+			return nil
+		}
 		return errors.Wrap(ctx, err)
 	}
 
+	// フローの各ステップの実行状況と出力をvlog_stepsに記録する
+	// タスクの再実行時は完了済みのステップの出力（チェックポイント）から再開し、Veoの再生成を避ける
+	steps, err := service.NewVlogStepRecorder(ctx, s.vlogStepRepo, vlogRef.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	publishVLogEvent(ctx, s.progressBus, vlogRef, nil, steps.Finished())
+
+	// キャンセルされた場合は実行中の生成処理（Veoのポーリングなど）を中断する
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go s.watchVLogCancellation(runCtx, vlogRef.ID, cancel)
+	runCtx = agent.WithStepObserver(runCtx, steps)
+	runCtx = agent.WithCheckpointer(runCtx, steps)
+
 	// VLog生成を実行
-	var res *agent.VlogOutput
-	err = s.txManager.Do(ctx, func(ctx context.Context) error {
-		var err error
-		res, err = s.agent.CreateVlogWithProgress(ctx, vlogInput, func(p agent.FlowProgress) {
-			// 進捗をDBに更新（最新のレコードを取得してから更新）
-			latestVlog, getErr := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogRef.ID}})
-			if getErr == nil {
-				latestVlog.Status = domain.VlogStatusProcessing
-				latestVlog.ErrorMessage = ""
-				latestVlog.Progress = p.Progress
-				_ = s.vlogRepo.Update(ctx, latestVlog)
+	// キャンセル要求でVLogを更新できるよう、生成処理はトランザクション外で実行する
+	res, err := s.agent.CreateVlogWithProgress(runCtx, vlogInput, func(p agent.FlowProgress) {
+		// 進捗をDBに更新（最新のレコードを取得してから更新）し、購読中のクライアントに配信する
+		// FlowProgressは0-100、VLogの進捗は0-1で保持する
+		latestVlog, getErr := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogRef.ID}})
+		if getErr == nil && latestVlog.Status != domain.VlogStatusCancelled {
+			latestVlog.Status = domain.VlogStatusProcessing
+			latestVlog.ErrorMessage = ""
+			latestVlog.Progress = p.Progress / 100
+			if updateErr := s.vlogRepo.Update(ctx, latestVlog); updateErr == nil {
+				publishVLogEvent(ctx, s.progressBus, latestVlog, &p, steps.Finished())
 			}
-		})
-		return errors.Wrap(ctx, err)
+		}
 	})
 
 	if err != nil {
+		if errors.Is(context.Cause(runCtx), errors.ErrVlogCancelled) || s.isVLogCancelled(ctx, vlogRef.ID) {
+			logger.Info(ctx, "vlog generation cancelled", "vlog_id", vlogRef.ID)
+			return nil
+		}
+
+		// リトライが残っている場合はエラー内容のみ記録し、処理中のまま再実行を待つ
+		if !queue.IsFinalAttempt(ctx) {
+			latestVlog, getErr := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogRef.ID}})
+			if getErr == nil {
+				latestVlog.ErrorMessage = err.Error()
+				if updateErr := s.vlogRepo.Update(ctx, latestVlog); updateErr == nil {
+					publishVLogEvent(ctx, s.progressBus, latestVlog, nil, steps.Finished())
+				}
+			}
+			return errors.Wrap(ctx, err)
+		}
+
 		// 最新のレコードを取得してから失敗ステータスに更新
 		latestVlog, getErr := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogRef.ID}})
 		if getErr == nil {
 			latestVlog.Status = domain.VlogStatusFailed
 			latestVlog.ErrorMessage = err.Error()
 			latestVlog.Progress = 0
-			_ = s.vlogRepo.Update(ctx, latestVlog)
+			if updateErr := s.vlogRepo.Update(ctx, latestVlog); updateErr == nil {
+				publishVLogEvent(ctx, s.progressBus, latestVlog, nil, steps.Finished())
+			}
+		}
+
+		// 仮引きしたトークンを返却
+		if rbErr := s.tokenLedger.Rollback(ctx, tokenReferenceID); rbErr != nil {
+			fmt.Printf("[executeVLogGeneration] Failed to rollback token reservation: %v\n", rbErr)
 		}
 
 		// VLog生成失敗の通知を作成
@@ -285,20 +354,58 @@ func (s *AgentServer) executeVLogGeneration(ctx context.Context, task *queue.Tas
 	if err != nil {
 		return errors.Wrap(ctx, err)
 	}
+	if latestVlog.Status == domain.VlogStatusCancelled {
+		logger.Info(ctx, "discard generated vlog of cancelled request", "vlog_id", latestVlog.ID)
+		return nil
+	}
+
+	// 絵コンテの確認を指定した場合は動画を生成せずに確認待ちにする
+	if vlogInput.ReviewStoryboard && vlogInput.Storyboard == nil {
+		return s.awaitStoryboardReview(ctx, latestVlog, vlogInput, res, steps)
+	}
 
 	latestVlog.VideoID = res.VideoID
 	latestVlog.VideoURL = res.VideoURL
-	latestVlog.ShareURL = res.ShareURL
 	latestVlog.Duration = res.Duration
 	latestVlog.Thumbnail = res.ThumbnailURL
+	latestVlog.Preview = res.PreviewURL
+	latestVlog.Title = res.Title
+	latestVlog.Description = res.Description
+	latestVlog.Analytics = toVlogAnalytics(res.Analytics)
+	latestVlog.Subtitles = toSubtitleSegments(res.Subtitles)
+	latestVlog.MusicTrackID = ptr.StringToPtr(res.MusicTrackID)
 	latestVlog.Status = domain.VlogStatusCompleted
+	latestVlog.ErrorMessage = ""
 	latestVlog.Progress = 1.0
 	completedAt := time.Now()
 	latestVlog.CompletedAt = &completedAt
 
-	if err := s.vlogRepo.Update(ctx, latestVlog); err != nil {
+	// 完了ステータスの更新とトークン消費の確定を同一トランザクションで行う
+	// 直前にキャンセルされた場合は楽観ロックにより更新に失敗する
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.vlogRepo.Update(ctx, latestVlog); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if err := s.vlogRepo.ReplaceSubtitles(ctx, latestVlog); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if err := s.saveStoryboard(ctx, latestVlog.ID, vlogInput, res.Storyboard); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		// フローで発行した共有コードを保存し、共有URLから閲覧できるようにする
+		if _, err := s.shareService.Issue(ctx, latestVlog, service.ShareOptions{Code: res.ShareCode}); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		return s.tokenLedger.Confirm(ctx, tokenReferenceID)
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrOptimisticLock) && s.isVLogCancelled(ctx, latestVlog.ID) {
+			logger.Info(ctx, "discard generated vlog of cancelled request", "vlog_id", latestVlog.ID)
+			return nil
+		}
 		return errors.Wrap(ctx, err)
 	}
+	publishVLogEvent(ctx, s.progressBus, latestVlog, nil, steps.Finished())
 
 	// VLog生成完了の通知を作成
 	if latestVlog.CreateUserID != nil {
@@ -318,6 +425,154 @@ func (s *AgentServer) executeVLogGeneration(ctx context.Context, task *queue.Tas
 	return nil
 }
 
+// awaitStoryboardReview は生成したタイトルと絵コンテを保存し、VLogをユーザーの絵コンテの確認待ちにする
+// 分析と構成の作成分として仮引きしたトークンは同一トランザクションで確定する（動画の生成分は生成開始時に仮引きする）
+func (s *AgentServer) awaitStoryboardReview(ctx context.Context, vlog *domain.Vlog, vlogInput *agent.VlogInput, res *agent.VlogOutput, steps *service.VlogStepRecorder) error {
+	vlog.Title = res.Title
+	vlog.Description = res.Description
+	vlog.Analytics = toVlogAnalytics(res.Analytics)
+	vlog.Status = domain.VlogStatusStoryboardReady
+	vlog.ErrorMessage = ""
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if err := s.saveStoryboard(ctx, vlog.ID, vlogInput, res.Storyboard); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		return s.tokenLedger.Confirm(ctx, vlog.ReservationReferenceID())
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrOptimisticLock) && s.isVLogCancelled(ctx, vlog.ID) {
+			logger.Info(ctx, "discard storyboard of cancelled request", "vlog_id", vlog.ID)
+			return nil
+		}
+		return errors.Wrap(ctx, err)
+	}
+	publishVLogEvent(ctx, s.progressBus, vlog, nil, steps.Finished())
+
+	if vlog.CreateUserID != nil {
+		notification := &domain.Notification{
+			UserID:  *vlog.CreateUserID,
+			Type:    domain.NotificationTypeVlogStoryboardReady,
+			Title:   "絵コンテ作成完了",
+			Message: "絵コンテを確認して、動画の生成を開始してください",
+			VlogID:  nullvalue.ToNullString(vlog.ID),
+			Read:    false,
+		}
+		if notifErr := s.notificationRepo.Create(ctx, notification); notifErr != nil {
+			fmt.Printf("[awaitStoryboardReview] Failed to create notification: %v\n", notifErr)
+		}
+	}
+	return nil
+}
+
+// saveStoryboard はVLog生成に使用した絵コンテを保存する
+// 確認後の再開に使用するため、VLog生成の入力も合わせて保存する
+func (s *AgentServer) saveStoryboard(ctx context.Context, vlogID string, vlogInput *agent.VlogInput, storyboard *agent.Storyboard) error {
+	if storyboard == nil {
+		return nil
+	}
+	input := *vlogInput
+	input.Storyboard = nil
+	data, err := json.Marshal(input)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	existing, err := s.storyboardRepo.FindByVlogID(ctx, vlogID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrap(ctx, err)
+	}
+	if existing == nil {
+		return s.storyboardRepo.Create(ctx, &domain.VlogStoryboard{
+			VlogID: vlogID,
+			Scenes: toStoryboardScenes(storyboard),
+			Input:  string(data),
+		})
+	}
+	existing.Scenes = toStoryboardScenes(storyboard)
+	existing.Input = string(data)
+	return s.storyboardRepo.Update(ctx, existing)
+}
+
+// toStoryboardScenes はVLog生成フローの絵コンテをVLogに保存する形式にする
+func toStoryboardScenes(storyboard *agent.Storyboard) []domain.StoryboardScene {
+	scenes := make([]domain.StoryboardScene, 0, len(storyboard.Scenes))
+	for _, scene := range storyboard.Scenes {
+		scenes = append(scenes, domain.StoryboardScene{
+			MediaIDs:        scene.MediaIDs,
+			CameraDirection: scene.CameraDirection,
+			VeoPrompt:       scene.VeoPrompt,
+			Caption:         scene.Caption,
+			DurationSeconds: scene.DurationSeconds,
+		})
+	}
+	return scenes
+}
+
+// toAgentStoryboard は保存した絵コンテをVLog生成フローの入力の形式にする
+func toAgentStoryboard(scenes []domain.StoryboardScene) *agent.Storyboard {
+	storyboard := &agent.Storyboard{Scenes: make([]agent.StoryboardScene, 0, len(scenes))}
+	for _, scene := range scenes {
+		storyboard.Scenes = append(storyboard.Scenes, agent.StoryboardScene{
+			MediaIDs:        scene.MediaIDs,
+			CameraDirection: scene.CameraDirection,
+			VeoPrompt:       scene.VeoPrompt,
+			Caption:         scene.Caption,
+			DurationSeconds: scene.DurationSeconds,
+		})
+	}
+	return storyboard
+}
+
+// toVlogAnalytics はVLog生成フローの分析結果サマリーをVLogに保存する形式にする
+func toVlogAnalytics(analytics agent.VlogAnalytics) domain.VlogAnalytics {
+	return domain.VlogAnalytics{
+		Locations:  analytics.Locations,
+		Activities: analytics.Activities,
+		Mood:       analytics.Mood,
+		Highlights: analytics.Highlights,
+		MediaCount: analytics.MediaCount,
+	}
+}
+
+// toSubtitleSegments はVLog生成フローの字幕をVLogに保存する形式にする
+func toSubtitleSegments(entries []agent.SubtitleEntry) []domain.SubtitleSegment {
+	segments := make([]domain.SubtitleSegment, 0, len(entries))
+	for i, entry := range entries {
+		segments = append(segments, domain.NewSubtitleSegment(i+1, entry.StartTime, entry.EndTime, entry.Text))
+	}
+	return segments
+}
+
+// watchVLogCancellation は生成中のVLogのキャンセル要求を定期的に確認し、
+// キャンセルされた場合はErrVlogCancelledで処理をキャンセルする
+func (s *AgentServer) watchVLogCancellation(ctx context.Context, vlogID string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(vlogCancelCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if s.isVLogCancelled(ctx, vlogID) {
+			cancel(errors.ErrVlogCancelled)
+			return
+		}
+	}
+}
+
+// isVLogCancelled はVLogがキャンセル済みかどうかを返す
+func (s *AgentServer) isVLogCancelled(ctx context.Context, vlogID string) bool {
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogID}})
+	if err != nil {
+		return false
+	}
+	return vlog.Status == domain.VlogStatusCancelled
+}
+
 // uploadMediaFiles はマルチパートファイルをストレージにアップロードしてMediaItemsを返す
 func (s *AgentServer) uploadMediaFiles(ctx context.Context, userID string, files []*multipart.FileHeader) ([]agent.MediaItem, error) {
 	mediaItems := make([]agent.MediaItem, 0, len(files))
@@ -361,6 +616,7 @@ func (s *AgentServer) uploadMediaFiles(ctx context.Context, userID string, files
 			Size:        int64(len(data)),
 			URL:         nullvalue.ToNullString(key),
 		}
+		applyMediaMetadata(ctx, media, data)
 		if err := s.mediaRepo.Save(ctx, media); err != nil {
 			return nil, fmt.Errorf("failed to save media record: %w", err)
 		}
@@ -384,6 +640,9 @@ func (s *AgentServer) uploadMediaFiles(ctx context.Context, userID string, files
 			URL:         url,
 			Type:        mediaType,
 			ContentType: contentType,
+			Timestamp:   capturedAtString(media),
+			Location:    mediaLocation(media),
+			Duration:    media.Duration,
 			Order:       i + 1,
 		})
 	}
@@ -391,6 +650,80 @@ func (s *AgentServer) uploadMediaFiles(ctx context.Context, userID string, files
 	return mediaItems, nil
 }
 
+// uploadMusicTrack はユーザーがアップロードしたBGMをストレージに保存し、曲として登録する
+func (s *AgentServer) uploadMusicTrack(ctx context.Context, userID string, fileHeader *multipart.FileHeader) (*domain.MusicTrack, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", fileHeader.Filename, err)
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = image.DetectContentType(data)
+	}
+	if !strings.HasPrefix(contentType, "audio/") {
+		return nil, errors.MakeInvalidArgumentError(ctx, "BGMには音声ファイルを指定してください")
+	}
+
+	// オブジェクトキーに使用するため、IDは登録前に採番する
+	ext := filepath.Ext(fileHeader.Filename)
+	track := &domain.MusicTrack{
+		BaseModel:   domain.BaseModel{ID: uuid.GenerateID()},
+		UserID:      &userID,
+		Title:       strings.TrimSuffix(fileHeader.Filename, ext),
+		ContentType: contentType,
+	}
+	key := fmt.Sprintf("users/%s/bgm/%s%s", userID, track.ID, ext)
+	track.ObjectKey, err = s.storage.UploadFile(ctx, key, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file %s: %w", fileHeader.Filename, err)
+	}
+	if err := s.musicTrackRepo.Create(ctx, track); err != nil {
+		return nil, fmt.Errorf("failed to save music track: %w", err)
+	}
+	return track, nil
+}
+
+// applyMediaMetadata はファイルのEXIF・動画のメタデータから撮影情報と動画の長さを取得してメディアに設定する
+// 撮影情報を取得できなくてもアップロードは続行する
+func applyMediaMetadata(ctx context.Context, media *domain.Media, data []byte) {
+	meta, err := metadata.Extract(data)
+	if err != nil {
+		logger.Warn(ctx, "failed to extract media metadata", "media_id", media.ID, "error", err)
+		return
+	}
+	media.CapturedAt = meta.CapturedAt
+	if meta.Location != nil {
+		media.Latitude = &meta.Location.Latitude
+		media.Longitude = &meta.Location.Longitude
+	}
+	media.CameraModel = meta.CameraModel
+	media.Orientation = meta.Orientation
+	media.Duration = meta.Duration
+}
+
+// capturedAtString はメディアの撮影日時をRFC3339形式で返す（撮影日時がない場合は空）
+func capturedAtString(media *domain.Media) string {
+	if media.CapturedAt == nil {
+		return ""
+	}
+	return media.CapturedAt.Format(time.RFC3339)
+}
+
+// mediaLocation はメディアの撮影地点を返す（撮影地点がない場合はnil）
+func mediaLocation(media *domain.Media) *agent.GeoLocation {
+	if media.Latitude == nil || media.Longitude == nil {
+		return nil
+	}
+	return &agent.GeoLocation{Latitude: *media.Latitude, Longitude: *media.Longitude}
+}
+
 // detectMediaType はコンテンツタイプからメディアタイプ（image/video）を判定する
 func detectMediaType(contentType string) string {
 	if strings.HasPrefix(contentType, "video/") {
@@ -445,7 +778,7 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 			Status:      domain.MediaStatusUploading,
 			Progress:    0.0,
 		}
-		if err := s.mediaRepo.Save(ctx, media); err != nil {
+		if err := s.saveMedia(ctx, media); err != nil {
 			continue
 		}
 
@@ -454,7 +787,7 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 		if err != nil {
 			media.Status = domain.MediaStatusFailed
 			media.ErrorMessage = fmt.Sprintf("Failed to open file: %v", err)
-			s.mediaRepo.Save(ctx, media)
+			s.saveMedia(ctx, media)
 			continue
 		}
 
@@ -463,7 +796,7 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 		if err != nil {
 			media.Status = domain.MediaStatusFailed
 			media.ErrorMessage = fmt.Sprintf("Failed to read file: %v", err)
-			s.mediaRepo.Save(ctx, media)
+			s.saveMedia(ctx, media)
 			continue
 		}
 
@@ -477,7 +810,7 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 		if err != nil {
 			media.Status = domain.MediaStatusFailed
 			media.ErrorMessage = fmt.Sprintf("Failed to upload: %v", err)
-			s.mediaRepo.Save(ctx, media)
+			s.saveMedia(ctx, media)
 			continue
 		}
 
@@ -491,7 +824,8 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 		media.URL = nullvalue.ToNullString(url)
 		media.Progress = 0.5                     // アップロード完了で50%
 		media.Status = domain.MediaStatusPending // 分析待ちに戻す
-		if err := s.mediaRepo.Save(ctx, media); err != nil {
+		applyMediaMetadata(ctx, media, data)
+		if err := s.saveMedia(ctx, media); err != nil {
 			continue
 		}
 
@@ -503,33 +837,18 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 		return errors.MakeBusinessError(ctx, "No media files were successfully uploaded")
 	}
 
-	// Cloud Tasksにタスクを登録
-	payload := &queue.Task{
-		Type: "ProcessMediaAnalysisTask",
-		Data: map[string]interface{}{
-			"user_id":   userIDStr,
-			"media_ids": mediaIDs,
-		},
-		Status: domain.MediaStatusPending.String(),
-	}
-
-	env := config.GetCtxEnv(ctx)
-	if env.Env == "local" {
-		// ローカル環境ではGoroutineで直接実行
-		bgCtx := context.Background()
-		bgCtx = Ctx.SetConfig(bgCtx, env)
-		bgCtx = Ctx.SetCtxFromUser(bgCtx, userIDStr)
-		bgCtx = Ctx.SetRequestTime(bgCtx, time.Now())
-		bgCtx = Ctx.SetDB(bgCtx, Ctx.GetDB(ctx))
-		go func() {
-			if err := s.executeMediaAnalysis(bgCtx, payload); err != nil {
-				fmt.Printf("Media analysis failed: %v\n", err)
-			}
-		}()
-	} else {
-		if err := s.taskClient.Enqueue(ctx, payload); err != nil {
-			return errors.Wrap(ctx, err)
-		}
+	// タスクキューに登録
+	// 再配信時の重複実行を防ぐため、分析リクエストごとにタスクIDを採番する
+	task, err := queue.NewTask(queue.TaskTypeProcessMediaAnalysis, uuid.GenerateID(), 1, &queue.MediaAnalysisPayload{
+		UserID:   userIDStr,
+		MediaIDs: mediaIDs,
+	}, domain.MediaStatusPending.String())
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	if err := s.taskClient.Enqueue(ctx, task); err != nil {
+		return errors.Wrap(ctx, err)
 	}
 
 	// 即座にmediaIDリストを返却
@@ -539,37 +858,9 @@ func (s *AgentServer) AnalyzeMedia(c echo.Context) error {
 	})
 }
 
-// ProcessMediaAnalysisTask はCloud Tasksからのリクエストを受け取り、メディア分析を非同期に実行する
-func (s *AgentServer) ProcessMediaAnalysisTask(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	var task queue.Task
-	if err := c.Bind(&task); err != nil {
-		return c.NoContent(http.StatusBadRequest)
-	}
-
-	if err := s.executeMediaAnalysis(ctx, &task); err != nil {
-		return errors.Wrap(ctx, err)
-	}
-
-	return c.NoContent(http.StatusOK)
-}
-
 // executeMediaAnalysis はメディア分析のコアロジックを実行する
-func (s *AgentServer) executeMediaAnalysis(ctx context.Context, task *queue.Task) error {
-	// タスクデータから必要な情報を取得
-	data, ok := task.Data.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid task data format")
-	}
-
-	userID, _ := data["user_id"].(string)
-	mediaIDsInterface, _ := data["media_ids"].([]interface{})
-	mediaIDs := make([]string, len(mediaIDsInterface))
-	for i, v := range mediaIDsInterface {
-		mediaIDs[i], _ = v.(string)
-	}
-	return s.processMediaAnalysisFromIDs(ctx, userID, mediaIDs)
+func (s *AgentServer) executeMediaAnalysis(ctx context.Context, task *queue.Task, payload *queue.MediaAnalysisPayload) error {
+	return s.processMediaAnalysisFromIDs(ctx, payload.UserID, payload.MediaIDs)
 }
 
 // processMediaAnalysisFromIDs はメディアIDから分析を実行する
@@ -591,14 +882,14 @@ func (s *AgentServer) processMediaAnalysisFromIDs(ctx context.Context, userID st
 			// URLが無効な場合は失敗ステータスに更新
 			media.Status = domain.MediaStatusFailed
 			media.ErrorMessage = "Media URL is invalid"
-			s.mediaRepo.Save(ctx, media)
+			s.saveMedia(ctx, media)
 			continue
 		}
 
 		// Status: ANALYZING
 		media.Status = domain.MediaStatusAnalyzing
 		media.Progress = 0.6
-		if err := s.mediaRepo.Save(ctx, media); err != nil {
+		if err := s.saveMedia(ctx, media); err != nil {
 			fmt.Printf("Failed to update media status: %v\n", err)
 		}
 
@@ -669,14 +960,24 @@ func (s *AgentServer) processMediaAnalysisFromIDs(ctx context.Context, userID st
 				fmt.Printf("Failed to create notification: %v\n", notifErr)
 			}
 		}
-		if err := s.mediaRepo.Save(ctx, media); err != nil {
+		if err := s.saveMedia(ctx, media); err != nil {
 			fmt.Printf("Failed to save media: %v\n", err)
 		}
 	}
 	return nil
 }
 
+// saveMedia はメディアを保存し、分析状況を購読中のクライアントに配信する
+func (s *AgentServer) saveMedia(ctx context.Context, media *domain.Media) error {
+	if err := s.mediaRepo.Save(ctx, media); err != nil {
+		return err
+	}
+	publishMediaEvent(ctx, s.progressBus, media)
+	return nil
+}
+
 // StreamAnalysisStatus はメディア分析の進捗をSSEでストリーミングする
+// メディアごとの進捗イベントを購読し、変化があるたびに全体の集計を送信する
 func (s *AgentServer) StreamAnalysisStatus(c echo.Context) error {
 	ctx := c.Request().Context()
 	var req request.AnalyzeMediaStreamRequest
@@ -689,82 +990,161 @@ func (s *AgentServer) StreamAnalysisStatus(c echo.Context) error {
 	}
 
 	mediaIDs := make([]string, 0, len(req.IDs))
+	topics := make([]string, 0, len(req.IDs))
 	for _, id := range req.IDs {
+		id = strings.TrimSpace(id)
 		mediaIDs = append(mediaIDs, id)
+		topics = append(topics, progress.MediaTopic(id))
 	}
 
-	// SSEヘッダー設定
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().Header().Set("X-Accel-Buffering", "no")
+	// 購読してから現在の状態を取得し、取得までの間のイベントを取りこぼさないようにする
+	afterID := lastEventID(c)
+	events, err := s.progressBus.Subscribe(ctx, topics, afterID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	medias := make(map[string]*domain.Media, len(mediaIDs))
+	for _, id := range mediaIDs {
+		media, err := s.mediaRepo.GetByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		medias[id] = media
+	}
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	startSSE(c)
 
-	iterationCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			iterationCount++
+	// send は集計を送信し、全て完了した場合は終了イベントを送信してtrueを返す
+	send := func(id int64) (bool, error) {
+		res := response.ToMediaStatusResponse(mediaIDs, medias)
+		data, err := json.Marshal(res)
+		if err != nil {
+			return true, errors.Wrap(ctx, err)
+		}
+		if err := writeSSE(c, id, "", data); err != nil {
+			return true, nil
+		}
+		if !res.AllCompleted {
+			return false, nil
+		}
+		// 明示的な終了イベントを送信
+		_ = writeSSE(c, 0, "complete", []byte(`{"status":"done"}`))
+		return true, nil
+	}
 
-			medias := make([]*domain.Media, 0, len(mediaIDs))
-			completedCount := 0
-			failedCount := 0
+	// 初回接続時と、再接続時に既に全て終了している場合は現在の状態を送信する
+	if afterID == 0 || response.ToMediaStatusResponse(mediaIDs, medias).AllCompleted {
+		done, err := send(0)
+		if err != nil || done {
+			return err
+		}
+	}
 
-			for _, id := range mediaIDs {
-				media, err := s.mediaRepo.GetByID(ctx, strings.TrimSpace(id))
-				if err != nil {
-					continue
-				}
-				medias = append(medias, media)
+	return streamSSE(c, events, func(event *domain.ProgressEvent) (bool, error) {
+		var media domain.Media
+		if err := json.Unmarshal([]byte(event.Data), &media); err != nil {
+			logger.Warn(ctx, "failed to decode media progress", "event_id", event.ID, "error", err)
+			return false, nil
+		}
+		medias[media.ID] = &media
+		return send(event.ID)
+	})
+}
 
-				if media.Status == domain.MediaStatusCompleted {
-					completedCount++
-				} else if media.Status == domain.MediaStatusFailed {
-					failedCount++
-				}
-			}
+// resolveVlogDuration はリクエストの目標再生時間を最大秒数以内に丸める（未指定の場合はデフォルト秒数）
+func resolveVlogDuration(duration *int) int {
+	d := ptr.PtrToInt(duration)
+	if d <= 0 {
+		return constant.DefaultVLogDurationSeconds
+	}
+	return min(d, constant.MaxVLogDurationSeconds)
+}
+
+// resolveNarrationStyle はリクエストからナレーションの設定を作成する（ナレーションを付けない場合はnil）
+func resolveNarrationStyle(req *request.CreateVLogRequest) *agent.NarrationStyle {
+	if req.Narration == nil || !*req.Narration {
+		return nil
+	}
+	style := &agent.NarrationStyle{SpeakerID: constant.DefaultNarrationSpeakerID}
+	if req.NarrationSpeaker != nil {
+		style.SpeakerID = *req.NarrationSpeaker
+	}
+	if req.NarrationSpeed != nil {
+		style.SpeedScale = *req.NarrationSpeed
+	}
+	return style
+}
 
-			allCompleted := (completedCount + failedCount) == len(mediaIDs)
+// vlogTokenReferenceID はVLog生成ごとのトークン仮引きの参照IDを返す
+// 初回はVLog ID、デッドレターからの再実行時は再実行するタスクのバージョンを付与して別の仮引きとして扱う
+func vlogTokenReferenceID(vlogID string, taskVersion int) string {
+	if taskVersion <= 1 {
+		return vlogID
+	}
+	return fmt.Sprintf("%s:%d", vlogID, taskVersion)
+}
 
-			response := response.MediaStatusResponse{
-				Medias:         medias,
-				TotalItems:     len(mediaIDs),
-				CompletedItems: completedCount,
-				FailedItems:    failedCount,
-				AllCompleted:   allCompleted,
-			}
+// prepareVLogRequeue は失敗したVLogを生成待ちに戻し、再実行分のトークンを仮引きする
+func (s *AgentServer) prepareVLogRequeue(ctx context.Context, task *queue.Task) error {
+	var input agent.VlogInput
+	if err := json.Unmarshal(task.Data, &input); err != nil {
+		return errors.Wrap(ctx, err)
+	}
 
-			// JSON送信
-			data, err := json.Marshal(response)
-			if err != nil {
-				return errors.Wrap(ctx, err)
-			}
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: task.ID}})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.MakeNotFoundError(ctx, "VLogが見つかりません")
+		}
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.Status != domain.VlogStatusFailed {
+		return errors.MakeConflictError(ctx, "失敗したVLogのみ再実行できます")
+	}
 
-			_, err = fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			if err != nil {
-				return errors.Wrap(ctx, err)
-			}
-			c.Response().Flush()
-
-			// 全て完了で終了
-			if allCompleted {
-				// 明示的な終了イベントを送信
-				_, err = fmt.Fprintf(c.Response(), "event: complete\ndata: {\"status\":\"done\"}\n\n")
-				if err != nil {
-					c.Response().Flush()
-					return errors.Wrap(ctx, err)
-				}
+	// 仮引きは再実行ごとに分け、参照IDをVLogに記録する
+	vlog.Status = domain.VlogStatusPending
+	vlog.ErrorMessage = ""
+	vlog.Progress = 0
+	vlog.StartedAt = nil
+	vlog.CompletedAt = nil
+	vlog.TokenReferenceID = vlogTokenReferenceID(vlog.ID, task.Version)
+	if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+		return errors.Wrap(ctx, err)
+	}
 
-				// クライアントがメッセージを処理する時間を確保
-				time.Sleep(100 * time.Millisecond)
+	// 更新後のVLogのバージョンで登録する
+	task.Version = vlog.Version
+	task.Status = domain.MediaStatusPending.String()
+	estimate := service.EstimateVlogTokenCost(&input)
+	return s.tokenLedger.Reserve(ctx, input.UserID, vlog.TokenReferenceID, estimate.Total, "VLog再生成")
+}
 
-				// 強制クローズはせず、return nil で自然に接続を閉じる
-				return nil
-			}
-		}
+// abortVLogRequeue は再実行の登録に失敗した場合に仮引きを取り消し、VLogを失敗に戻す
+func (s *AgentServer) abortVLogRequeue(ctx context.Context, task *queue.Task) error {
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: task.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := s.tokenLedger.Rollback(ctx, vlog.ReservationReferenceID()); err != nil {
+		return err
+	}
+
+	vlog.Status = domain.VlogStatusFailed
+	vlog.ErrorMessage = "VLogの再実行の登録に失敗しました"
+	if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+		return errors.Wrap(ctx, err)
 	}
+	return nil
+}
+
+// RegisterTasks はVLog生成・メディア分析のタスクをレジストリに登録する
+func (s *AgentServer) RegisterTasks(registry *queue.Registry) {
+	queue.Register(registry, queue.TaskTypeProcessVLog, "create-vlog", retry.DefaultConfig, s.executeVLogGeneration,
+		queue.WithRequeueHook(queue.RequeueHook{
+			Prepare: s.prepareVLogRequeue,
+			Abort:   s.abortVLogRequeue,
+		}),
+	)
+	queue.Register(registry, queue.TaskTypeProcessMediaAnalysis, "analyze-media", retry.DefaultConfig, s.executeMediaAnalysis)
 }
@@ -0,0 +1,124 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent は生成中に任意の処理（ユーザーによるキャンセルなど）を挟んで固定の生成結果を返す
+type fakeAgent struct {
+	agent.IAgent
+	output *agent.VlogOutput
+	during func()
+	calls  int
+}
+
+func (a *fakeAgent) CreateVlogWithProgress(ctx context.Context, input *agent.VlogInput, onProgress func(agent.FlowProgress)) (*agent.VlogOutput, error) {
+	a.calls++
+	onProgress(agent.FlowProgress{Progress: 50})
+	if a.during != nil {
+		a.during()
+	}
+	return a.output, nil
+}
+
+func TestAgentServer_ProcessVLog_Cancelled(t *testing.T) {
+	const vlogID = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+	type env struct {
+		vlogRepo *fakeVLogRepo
+		ledger   *fakeTokenLedger
+		agent    *fakeAgent
+		cancel   func()
+		process  func(input *agent.VlogInput) error
+	}
+	setup := func(t *testing.T, status domain.VlogStatus) *env {
+		vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+			vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: status},
+		}}
+		ledger := newFakeTokenLedger()
+		bus := progress.NewMemoryBus()
+		fake := &fakeAgent{output: &agent.VlogOutput{VideoID: "video-1", VideoURL: "https://r2.example.com/vlog.mp4", Title: "京都の旅"}}
+
+		vlogServer := handler.NewVLogServer(vlogRepo, nil, nil, ledger, fakeTransactionManager{}, bus, nil, nil)
+		agentServer := handler.NewAgentServer(context.Background(), nil, fake, vlogRepo, nil, nil, nil, fakeTransactionManager{}, nil, ledger, bus, &fakeVlogStepRepo{}, nil, nil, nil)
+		registry := queue.NewRegistry()
+		agentServer.RegisterTasks(registry)
+		def, err := registry.Lookup(queue.TaskTypeProcessVLog)
+		require.NoError(t, err)
+
+		return &env{
+			vlogRepo: vlogRepo,
+			ledger:   ledger,
+			agent:    fake,
+			cancel: func() {
+				_, err := serveVLogRequest(vlogServer.Cancel, http.MethodPost, "/api/vlogs/"+vlogID+"/cancel", "", "owner", vlogID)
+				require.NoError(t, err)
+			},
+			process: func(input *agent.VlogInput) error {
+				task, err := queue.NewTask(queue.TaskTypeProcessVLog, vlogID, 1, input, string(status))
+				require.NoError(t, err)
+				return def.Handle(queue.WithAttempt(context.Background(), 1), task)
+			},
+		}
+	}
+	// 生成結果を破棄し、トークンはキャンセル時の一度だけ返却したことを確認する
+	assertDiscarded := func(t *testing.T, e *env) {
+		saved := e.vlogRepo.vlogs[vlogID]
+		assert.Equal(t, domain.VlogStatusCancelled, saved.Status)
+		assert.Empty(t, saved.VideoURL)
+		assert.Empty(t, e.ledger.confirms)
+		assert.Equal(t, map[string]int{vlogID: 1}, e.ledger.rollbacks)
+	}
+
+	t.Run("生成開始前にキャンセルされた場合は生成しない", func(t *testing.T) {
+		e := setup(t, domain.VlogStatusPending)
+		e.cancel()
+
+		require.NoError(t, e.process(&agent.VlogInput{UserID: "owner"}))
+		assert.Zero(t, e.agent.calls)
+		assertDiscarded(t, e)
+	})
+
+	t.Run("生成中にキャンセルされた場合は生成結果を破棄する", func(t *testing.T) {
+		e := setup(t, domain.VlogStatusPending)
+		e.agent.during = e.cancel
+
+		require.NoError(t, e.process(&agent.VlogInput{UserID: "owner"}))
+		assertDiscarded(t, e)
+	})
+
+	t.Run("完了の更新の直前にキャンセルされた場合は楽観ロックの競合により生成結果を破棄する", func(t *testing.T) {
+		e := setup(t, domain.VlogStatusPending)
+		e.vlogRepo.beforeUpdate = func(vlog *domain.Vlog) {
+			if vlog.Status == domain.VlogStatusCompleted {
+				e.cancel()
+			}
+		}
+
+		require.NoError(t, e.process(&agent.VlogInput{UserID: "owner"}))
+		assertDiscarded(t, e)
+	})
+
+	t.Run("絵コンテの確認待ちへの更新の直前にキャンセルされた場合は楽観ロックの競合により絵コンテを破棄する", func(t *testing.T) {
+		e := setup(t, domain.VlogStatusPending)
+		e.vlogRepo.beforeUpdate = func(vlog *domain.Vlog) {
+			if vlog.Status == domain.VlogStatusStoryboardReady {
+				e.cancel()
+			}
+		}
+
+		require.NoError(t, e.process(&agent.VlogInput{UserID: "owner", ReviewStoryboard: true}))
+		assertDiscarded(t, e)
+		assert.Empty(t, e.vlogRepo.vlogs[vlogID].Title)
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type IBillingServer interface {
+	CreateCheckout(c echo.Context) error
+	StripeWebhook(c echo.Context) error
+}
+
+type BillingServer struct {
+	billingService service.IBillingService
+}
+
+func NewBillingServer(billingService service.IBillingService) *BillingServer {
+	return &BillingServer{
+		billingService: billingService,
+	}
+}
+
+// CreateCheckout Stripe Checkout Sessionを作成する
+func (s *BillingServer) CreateCheckout(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := Ctx.GetCtxFromUser(ctx)
+
+	var req request.CreateCheckoutRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	product := req.Plan
+	if req.Type == domain.PaymentTypeTokenPurchase {
+		product = fmt.Sprintf("tokens_%d", req.Tokens)
+	}
+
+	session, err := s.billingService.CreateCheckout(ctx, userID, req.ClientRequestID, product, req.SuccessURL, req.CancelURL)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToCreateCheckoutResponse(session))
+}
+
+// StripeWebhook Stripe Webhookを受信する
+func (s *BillingServer) StripeWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	if err := s.billingService.HandleWebhook(ctx, payload, c.Request().Header.Get("Stripe-Signature")); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.StripeWebhookResponse{Received: true})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"gorm.io/gorm"
+)
+
+type IDeadLetterServer interface {
+	List(c echo.Context) error
+	GetByID(c echo.Context) error
+	Requeue(c echo.Context) error
+}
+
+type DeadLetterServer struct {
+	deadLetterRepo    domain.IDeadLetterTaskRepository
+	deadLetterService service.IDeadLetterService
+}
+
+func NewDeadLetterServer(deadLetterRepo domain.IDeadLetterTaskRepository, deadLetterService service.IDeadLetterService) *DeadLetterServer {
+	return &DeadLetterServer{
+		deadLetterRepo:    deadLetterRepo,
+		deadLetterService: deadLetterService,
+	}
+}
+
+// List デッドレター一覧を取得する（管理者のみ）
+func (s *DeadLetterServer) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var query request.DeadLetterListQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&query); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	limit := ptr.PtrToInt(query.Limit)
+	if limit == 0 {
+		limit = 20
+	}
+	deadLetters, err := s.deadLetterRepo.List(ctx, query.Status, &domain.ListOpts{
+		Limit:  limit,
+		Offset: ptr.PtrToInt(query.Offset),
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	total, err := s.deadLetterRepo.Count(ctx, query.Status)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToDeadLetterListResponse(deadLetters, total))
+}
+
+// GetByID デッドレターの詳細を取得する（管理者のみ）
+func (s *DeadLetterServer) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req request.DeadLetterIDRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	deadLetter, err := s.deadLetterRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.MakeNotFoundError(ctx, "タスクが見つかりません")
+		}
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToDeadLetter(deadLetter))
+}
+
+// Requeue デッドレターのタスクを再実行する（管理者のみ）
+func (s *DeadLetterServer) Requeue(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req request.DeadLetterIDRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	deadLetter, err := s.deadLetterService.Requeue(ctx, req.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusAccepted, response.ToDeadLetter(deadLetter))
+}
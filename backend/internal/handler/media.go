@@ -62,7 +62,7 @@ func (s *ImageServer) List(c echo.Context) error {
 		if env.Env == "local" {
 			url = strings.ReplaceAll(media.URL.String, "localstack", "localhost")
 		}
-		mediaResponses = append(mediaResponses, &response.MediaListItem{
+		item := &response.MediaListItem{
 			ID:          media.ID,
 			ContentType: media.ContentType,
 			Size:        media.Size,
@@ -70,7 +70,16 @@ func (s *ImageServer) List(c echo.Context) error {
 			Status:      string(media.Status),
 			ImageData:   base64Images[media.URL.String],
 			CreatedAt:   date.Format(media.CreatedAt),
-		})
+			Latitude:    media.Latitude,
+			Longitude:   media.Longitude,
+			CameraModel: media.CameraModel,
+			Orientation: media.Orientation,
+			Duration:    media.Duration,
+		}
+		if media.CapturedAt != nil {
+			item.CapturedAt = ptr.StringToPtr(date.Format(*media.CapturedAt))
+		}
+		mediaResponses = append(mediaResponses, item)
 	}
 
 	return c.JSON(http.StatusOK, response.MediaListResponse{
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+// SSEのハートビート（コメント）の送信間隔
+const sseHeartbeatInterval = 15 * time.Second
+
+// publishVLogEvent はVLogの状態を進捗イベントとして発行する
+// 発行に失敗してもVLogの処理は継続する（SSEはGET /api/vlogs/:idの状態で補完できる）
+func publishVLogEvent(ctx context.Context, bus progress.IBus, vlog *domain.Vlog, p *agent.FlowProgress, steps []*domain.VlogStep) {
+	msg := progress.Message{Data: response.ToVLogStreamEvent(vlog, p, steps)}
+	switch vlog.Status {
+	case domain.VlogStatusCancelled:
+		msg.Name = progress.EventCancelled
+		msg.Final = true
+	case domain.VlogStatusCompleted, domain.VlogStatusFailed, domain.VlogStatusStoryboardReady:
+		msg.Final = true
+	}
+	if err := bus.Publish(ctx, progress.VLogTopic(vlog.ID), msg); err != nil {
+		logger.Warn(ctx, "failed to publish vlog progress", "vlog_id", vlog.ID, "error", err)
+	}
+}
+
+// publishMediaEvent はメディアの分析状態を進捗イベントとして発行する
+func publishMediaEvent(ctx context.Context, bus progress.IBus, media *domain.Media) {
+	msg := progress.Message{
+		Data:  media,
+		Final: media.Status == domain.MediaStatusCompleted || media.Status == domain.MediaStatusFailed,
+	}
+	if err := bus.Publish(ctx, progress.MediaTopic(media.ID), msg); err != nil {
+		logger.Warn(ctx, "failed to publish media progress", "media_id", media.ID, "error", err)
+	}
+}
+
+// startSSE はSSEのレスポンスヘッダーを送信する
+func startSSE(c echo.Context) {
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+}
+
+// lastEventID はEventSourceの再接続時に送信されるLast-Event-IDを返す（未指定・不正な場合は0）
+// ヘッダーを付与できないクライアント向けにクエリパラメータlastEventIdも受け付ける
+func lastEventID(c echo.Context) int64 {
+	value := c.Request().Header.Get("Last-Event-ID")
+	if value == "" {
+		value = c.QueryParam("lastEventId")
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
+
+// writeSSE はSSE形式でデータを送信する（idが0の場合はidを付与しない）
+func writeSSE(c echo.Context, id int64, name string, data []byte) error {
+	w := c.Response()
+	if id > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	if name != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", name); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+// streamSSE は受信したイベントをhandleで送信し、一定間隔でハートビートのコメントを送信する
+// handleがtrueを返した場合、イベントのチャネルが閉じられた場合、クライアントが切断した場合に終了する
+func streamSSE(c echo.Context, events <-chan *domain.ProgressEvent, handle func(event *domain.ProgressEvent) (bool, error)) error {
+	ctx := c.Request().Context()
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			done, err := handle(event)
+			if err != nil || done {
+				return err
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Response(), ": heartbeat\n\n"); err != nil {
+				return err
+			}
+			c.Response().Flush()
+		}
+	}
+}
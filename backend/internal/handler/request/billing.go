@@ -0,0 +1,11 @@
+package request
+
+// CreateCheckoutRequest Checkout Session作成リクエスト
+type CreateCheckoutRequest struct {
+	ClientRequestID string `json:"client_request_id" validate:"required,max=255"`
+	Type            string `json:"type" validate:"required,oneof=token_purchase subscription"`
+	Plan            string `json:"plan,omitempty" validate:"required_if=Type subscription,omitempty,oneof=monthly yearly"`
+	Tokens          int    `json:"tokens,omitempty" validate:"required_if=Type token_purchase,omitempty,oneof=100 500 1000"`
+	SuccessURL      string `json:"success_url" validate:"required,url"`
+	CancelURL       string `json:"cancel_url" validate:"required,url"`
+}
@@ -0,0 +1,13 @@
+package request
+
+// DeadLetterListQuery デッドレター一覧取得のクエリパラメータ
+type DeadLetterListQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending requeued"`
+	Limit  *int   `query:"limit" validate:"omitempty,gte=0,lte=100"`
+	Offset *int   `query:"offset" validate:"omitempty,gte=0"`
+}
+
+// DeadLetterIDRequest デッドレターIDを指定するリクエスト
+type DeadLetterIDRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
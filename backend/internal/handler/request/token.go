@@ -0,0 +1,14 @@
+package request
+
+// TransactionListQuery トークン取引履歴取得のクエリパラメータ
+type TransactionListQuery struct {
+	Limit  *int `query:"limit" validate:"omitempty,gte=0,lte=100"`
+	Offset *int `query:"offset" validate:"omitempty,gte=0"`
+}
+
+// EstimateTokenCostRequest トークン消費見積もりリクエスト
+// 動画の長さ・分析済みかどうかはクライアントの申告ではなく、アップロード済みのメディアから取得する
+type EstimateTokenCostRequest struct {
+	MediaIDs []string `json:"media_ids" validate:"required,min=1,dive,required"` // アップロード済みのメディアのID
+	Duration *int     `json:"duration,omitempty" validate:"omitempty,gte=1"`
+}
@@ -1,6 +1,9 @@
 package request
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type VLogListRequest struct {
 	Offset *int `query:"offset" validate:"omitempty"`
@@ -15,16 +18,116 @@ type VLogDeleteRequest struct {
 	ID string `param:"id" validate:"required,uuid"`
 }
 
+// VLogUpdateRequest はVLogのタイトル・説明文・字幕の編集リクエスト
+// 指定しなかった項目は変更しない（字幕は空の配列を指定すると削除する）
+type VLogUpdateRequest struct {
+	ID          string                `param:"id" validate:"required,uuid"`
+	Title       *string               `json:"title,omitempty" validate:"omitempty,min=1,max=255"` // タイトルは空にできない
+	Description *string               `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Subtitles   []VLogSubtitleRequest `json:"subtitles,omitempty" validate:"omitempty,dive"`
+}
+
+type VLogSubtitleRequest struct {
+	StartTime float64 `json:"start_time" validate:"min=0"`           // 秒
+	EndTime   float64 `json:"end_time" validate:"gtfield=StartTime"` // 秒
+	Text      string  `json:"text" validate:"required,max=500"`
+}
+
+// VLogSubtitlesRequest は字幕ファイルの取得リクエスト
+type VLogSubtitlesRequest struct {
+	ID     string `param:"id" validate:"required,uuid"`
+	Format string `query:"format" validate:"omitempty,oneof=srt vtt json"` // 未指定の場合はsrt
+}
+
+type VLogRetryRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+type VLogCancelRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+type VLogTimelineRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+type VLogStoryboardRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+// VLogStoryboardUpdateRequest は絵コンテの編集リクエスト（シーンをすべて置き換える）
+type VLogStoryboardUpdateRequest struct {
+	ID     string                       `param:"id" validate:"required,uuid"`
+	Scenes []VLogStoryboardSceneRequest `json:"scenes" validate:"required,min=1,max=20,dive"`
+}
+
+type VLogStoryboardSceneRequest struct {
+	MediaIDs        []string `json:"media_ids" validate:"omitempty,dive,uuid"`
+	CameraDirection string   `json:"camera_direction" validate:"max=500"`
+	VeoPrompt       string   `json:"veo_prompt" validate:"required,max=2000"`
+	Caption         string   `json:"caption" validate:"max=500"`
+	DurationSeconds int      `json:"duration_seconds" validate:"oneof=4 6 8"` // Veoが生成できるクリップの長さ
+}
+
+type VLogRenderRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+type VLogDraftRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+// VLogDraftUpdateRequest は下書きのタイトル・メディアの並び順・スタイルの編集リクエスト
+// 指定しなかった項目は変更しない
+type VLogDraftUpdateRequest struct {
+	ID          string  `param:"id" validate:"required,uuid"`
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"` // タイトルは空にできない
+	Description *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+	// 使用するメディアの並び順（下書きのメディアのみ指定できる。指定しなかったメディアは使用しない）
+	MediaIDs      []string `json:"media_ids,omitempty" validate:"omitempty,dive,uuid"`
+	Theme         *string  `json:"theme,omitempty" validate:"omitempty,max=50"`
+	MusicMood     *string  `json:"music_mood,omitempty" validate:"omitempty,max=50"`
+	Transition    *string  `json:"transition,omitempty" validate:"omitempty,max=50"`
+	ReferenceMode *string  `json:"reference_mode,omitempty" validate:"omitempty,oneof=inspired animate"`
+	SubtitleMode  *string  `json:"subtitle_mode,omitempty" validate:"omitempty,oneof=sidecar burned"`
+	// ナレーションの設定
+	Narration        *bool    `json:"narration,omitempty"`
+	NarrationSpeaker *int     `json:"narration_speaker,omitempty" validate:"omitempty,gte=0"`
+	NarrationSpeed   *float64 `json:"narration_speed,omitempty" validate:"omitempty,gte=0.5,lte=2"`
+}
+
+// VLogDraftSceneUpdateRequest は下書きの1シーンの編集リクエスト
+// 指定しなかった項目は変更しない（media_idsは空の配列を指定するとメディアの割り当てを外す）
+type VLogDraftSceneUpdateRequest struct {
+	ID              string   `param:"id" validate:"required,uuid"`
+	Index           string   `param:"index" validate:"required,number"` // 1始まりのシーン番号
+	MediaIDs        []string `json:"media_ids,omitempty" validate:"omitempty,dive,uuid"`
+	CameraDirection *string  `json:"camera_direction,omitempty" validate:"omitempty,max=500"`
+	VeoPrompt       *string  `json:"veo_prompt,omitempty" validate:"omitempty,max=2000"`
+	Caption         *string  `json:"caption,omitempty" validate:"omitempty,max=500"`
+	DurationSeconds *int     `json:"duration_seconds,omitempty" validate:"omitempty,oneof=4 6 8"` // Veoが生成できるクリップの長さ
+}
+
 type CreateVLogRequest struct {
-	Files       []*multipart.FileHeader `form:"files" validate:"omitempty,min=1,dive"`
-	MediaIDs    []string                `form:"mediaIds" validate:"omitempty,dive,uuid"`
-	Title       *string                 `form:"title,omitempty"`
-	TravelDate  *string                 `form:"travelDate,omitempty"`
-	Destination *string                 `form:"destination,omitempty"`
-	Theme       *string                 `form:"theme,omitempty"`
-	MusicMood   *string                 `form:"musicMood,omitempty"`
-	Duration    *int                    `form:"duration,omitempty"`
-	Transition  *string                 `form:"transition,omitempty"`
+	Files         []*multipart.FileHeader `form:"files" validate:"omitempty,min=1,dive"`
+	MediaIDs      []string                `form:"mediaIds" validate:"omitempty,dive,uuid"`
+	Title         *string                 `form:"title,omitempty"`
+	TravelDate    *string                 `form:"travelDate,omitempty"`
+	Destination   *string                 `form:"destination,omitempty"`
+	Theme         *string                 `form:"theme,omitempty"`
+	MusicMood     *string                 `form:"musicMood,omitempty"`
+	Duration      *int                    `form:"duration,omitempty"`
+	Transition    *string                 `form:"transition,omitempty"`
+	ReferenceMode *string                 `form:"referenceMode,omitempty" validate:"omitempty,oneof=inspired animate"`
+	SubtitleMode  *string                 `form:"subtitleMode,omitempty" validate:"omitempty,oneof=sidecar burned"`
+	// ナレーションの設定
+	Narration        *bool    `form:"narration,omitempty"`
+	NarrationSpeaker *int     `form:"narrationSpeaker,omitempty" validate:"omitempty,gte=0"`
+	NarrationSpeed   *float64 `form:"narrationSpeed,omitempty" validate:"omitempty,gte=0.5,lte=2"`
+	// BGMの音声ファイル（未指定の場合はmusicMoodに合うライブラリの曲を使用する）
+	BGM *multipart.FileHeader `form:"bgm"`
+	// 絵コンテを確認・編集してから動画を生成する
+	ReviewStoryboard *bool `form:"reviewStoryboard,omitempty"`
 }
 
 type AnalyzeMediaRequest struct {
@@ -35,3 +138,18 @@ type AnalyzeMediaRequest struct {
 type AnalyzeMediaStreamRequest struct {
 	IDs []string `query:"ids" validate:"required,dive,uuid"`
 }
+
+type VLogShareRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+// VLogIssueShareRequest 共有リンクの発行（再発行）リクエスト
+type VLogIssueShareRequest struct {
+	ID        string     `param:"id" validate:"required,uuid"`
+	Password  *string    `json:"password,omitempty" validate:"omitempty,min=4,max=72"` // 閲覧パスワード（bcryptの上限の72バイトまで）
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`                                 // 有効期限（未指定の場合は無期限）
+}
+
+type SharedVLogRequest struct {
+	Code string `param:"code" validate:"required,alphanum,max=64"`
+}
@@ -0,0 +1,21 @@
+package response
+
+import "github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+
+// CreateCheckoutResponse Checkout Session作成レスポンス
+type CreateCheckoutResponse struct {
+	CheckoutURL string `json:"checkout_url"`
+	SessionID   string `json:"session_id"`
+}
+
+// StripeWebhookResponse Webhook受信レスポンス
+type StripeWebhookResponse struct {
+	Received bool `json:"received"`
+}
+
+func ToCreateCheckoutResponse(session *domain.CheckoutSession) *CreateCheckoutResponse {
+	return &CreateCheckoutResponse{
+		CheckoutURL: session.URL,
+		SessionID:   session.StripeSessionID,
+	}
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/date"
+)
+
+// DeadLetterListResponse デッドレター一覧レスポンス
+type DeadLetterListResponse struct {
+	Total int64         `json:"total"`
+	Items []*DeadLetter `json:"items"`
+}
+
+type DeadLetter struct {
+	ID                  string           `json:"id"`
+	TaskType            string           `json:"task_type"`
+	TaskID              string           `json:"task_id"`
+	TaskVersion         int              `json:"task_version"`
+	Payload             json.RawMessage  `json:"payload"`
+	Errors              []string         `json:"errors"`
+	Attempts            int              `json:"attempts"`
+	AttemptHistory      []*AttemptResult `json:"attempt_history"`
+	Status              string           `json:"status"`
+	RequeuedAt          string           `json:"requeued_at,omitempty"`
+	RequeuedTaskVersion int              `json:"requeued_task_version,omitempty"`
+	CreatedAt           string           `json:"created_at"`
+}
+
+type AttemptResult struct {
+	Attempt    int    `json:"attempt"`
+	Owner      string `json:"owner"`
+	Error      string `json:"error,omitempty"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+}
+
+func ToDeadLetter(deadLetter *domain.DeadLetterTask) *DeadLetter {
+	history := make([]*AttemptResult, 0, len(deadLetter.AttemptHistory))
+	for _, a := range deadLetter.AttemptHistory {
+		history = append(history, &AttemptResult{
+			Attempt:    a.Attempt,
+			Owner:      a.Owner,
+			Error:      a.Error,
+			StartedAt:  date.Format(a.StartedAt),
+			FinishedAt: date.Format(a.FinishedAt),
+		})
+	}
+	res := &DeadLetter{
+		ID:                  deadLetter.ID,
+		TaskType:            deadLetter.TaskType,
+		TaskID:              deadLetter.TaskID,
+		TaskVersion:         deadLetter.TaskVersion,
+		Payload:             json.RawMessage(deadLetter.Payload),
+		Errors:              deadLetter.Errors,
+		Attempts:            deadLetter.Attempts,
+		AttemptHistory:      history,
+		Status:              deadLetter.Status,
+		RequeuedTaskVersion: deadLetter.RequeuedTaskVersion,
+		CreatedAt:           date.Format(deadLetter.CreatedAt),
+	}
+	if deadLetter.RequeuedAt != nil {
+		res.RequeuedAt = date.Format(*deadLetter.RequeuedAt)
+	}
+	return res
+}
+
+func ToDeadLetterListResponse(deadLetters []*domain.DeadLetterTask, total int64) *DeadLetterListResponse {
+	items := make([]*DeadLetter, 0, len(deadLetters))
+	for _, d := range deadLetters {
+		items = append(items, ToDeadLetter(d))
+	}
+	return &DeadLetterListResponse{
+		Total: total,
+		Items: items,
+	}
+}
@@ -24,4 +24,11 @@ type MediaListItem struct {
 	Status      string  `json:"status"`               // ステータス
 	ImageData   string  `json:"image_data,omitempty"` // Base64エンコードされた画像データ
 	CreatedAt   string  `json:"created_at"`           // 作成日時
+	// EXIF・動画のメタデータから取得した撮影情報
+	CapturedAt  *string  `json:"captured_at,omitempty"`  // 撮影日時
+	Latitude    *float64 `json:"latitude,omitempty"`     // 撮影地点の緯度
+	Longitude   *float64 `json:"longitude,omitempty"`    // 撮影地点の経度
+	CameraModel string   `json:"camera_model,omitempty"` // カメラの機種
+	Orientation int      `json:"orientation,omitempty"`  // 向き（EXIFのOrientation）
+	Duration    float64  `json:"duration,omitempty"`     // 動画の長さ（秒）
 }
@@ -0,0 +1,18 @@
+package response
+
+import "github.com/o-ga09/zenn-hackthon-2026/internal/service"
+
+// SubscriptionJobResponse サブスクリプション定期処理の実行結果
+type SubscriptionJobResponse struct {
+	Refilled   int `json:"refilled"`
+	Expired    int `json:"expired"`
+	Downgraded int `json:"downgraded"`
+}
+
+func ToSubscriptionJobResponse(result *service.SubscriptionJobResult) *SubscriptionJobResponse {
+	return &SubscriptionJobResponse{
+		Refilled:   result.Refilled,
+		Expired:    result.Expired,
+		Downgraded: result.Downgraded,
+	}
+}
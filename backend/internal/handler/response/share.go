@@ -0,0 +1,69 @@
+package response
+
+import (
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+)
+
+// VLogShareResponse はVLogの共有リンクの情報（所有者向け）
+type VLogShareResponse struct {
+	Code        string     `json:"code"`
+	ShareURL    string     `json:"share_url"`
+	HasPassword bool       `json:"has_password"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ViewCount   int        `json:"view_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SharedVLogResponse は共有リンクから閲覧するVLog
+// 認証なしで返すため、作成者のIDなどは含めない
+type SharedVLogResponse struct {
+	VideoURL    string               `json:"video_url"`
+	Thumbnail   string               `json:"thumbnail"`
+	Duration    float64              `json:"duration"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Subtitles   []SharedVLogSubtitle `json:"subtitles"`
+	ViewCount   int                  `json:"view_count"`
+	ExpiresAt   *time.Time           `json:"expires_at,omitempty"`
+}
+
+// SharedVLogSubtitle は共有リンクから閲覧するVLogの字幕
+type SharedVLogSubtitle struct {
+	StartTime float64 `json:"start_time"` // 秒
+	EndTime   float64 `json:"end_time"`   // 秒
+	Text      string  `json:"text"`
+}
+
+func ToVLogShareResponse(share *domain.VlogShare, shareURL string) VLogShareResponse {
+	return VLogShareResponse{
+		Code:        share.Code,
+		ShareURL:    shareURL,
+		HasPassword: share.HasPassword(),
+		ExpiresAt:   share.ExpiresAt,
+		ViewCount:   share.ViewCount,
+		CreatedAt:   share.CreatedAt,
+	}
+}
+
+func ToSharedVLogResponse(share *domain.VlogShare, vlog *domain.Vlog) SharedVLogResponse {
+	subtitles := make([]SharedVLogSubtitle, 0, len(vlog.Subtitles))
+	for _, segment := range vlog.Subtitles {
+		subtitles = append(subtitles, SharedVLogSubtitle{
+			StartTime: segment.StartSeconds(),
+			EndTime:   segment.EndSeconds(),
+			Text:      segment.Text,
+		})
+	}
+	return SharedVLogResponse{
+		VideoURL:    vlog.VideoURL,
+		Thumbnail:   vlog.Thumbnail,
+		Duration:    vlog.Duration,
+		Title:       vlog.Title,
+		Description: vlog.Description,
+		Subtitles:   subtitles,
+		ViewCount:   share.ViewCount,
+		ExpiresAt:   share.ExpiresAt,
+	}
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/date"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+)
+
+// TokenBalanceResponse トークン残高レスポンス
+type TokenBalanceResponse struct {
+	Balance    int     `json:"balance"`
+	Plan       string  `json:"plan"`        // 契約中のサブスクリプションのプラン（monthly / yearly）、契約していない場合はユーザーのプラン
+	NextRefill *string `json:"next_refill"` // 次回のトークン付与日時（サブスクリプションを契約していない場合はnull）
+}
+
+// TokenTransactionListResponse トークン取引履歴レスポンス
+type TokenTransactionListResponse struct {
+	Transactions []*TokenTransaction `json:"transactions"`
+	Total        int64               `json:"total"`
+}
+
+type TokenTransaction struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Amount      int    `json:"amount"`
+	Balance     int    `json:"balance"`
+	Description string `json:"description"`
+	ReferenceID string `json:"reference_id,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// TokenCostEstimateResponse トークン消費見積もりレスポンス
+type TokenCostEstimateResponse struct {
+	Required   int                `json:"required"`
+	Available  int                `json:"available"`
+	Sufficient bool               `json:"sufficient"`
+	Breakdown  TokenCostBreakdown `json:"breakdown"`
+}
+
+type TokenCostBreakdown struct {
+	ImageAnalysis    int `json:"image_analysis"`
+	VideoAnalysis    int `json:"video_analysis"`
+	ScriptGeneration int `json:"script_generation"`
+	VideoGeneration  int `json:"video_generation"`
+}
+
+// ToTokenBalanceResponse はトークン残高レスポンスに変換する（subscriptionは契約していない場合nil）
+func ToTokenBalanceResponse(user *domain.User, subscription *domain.Subscription, now time.Time) *TokenBalanceResponse {
+	res := &TokenBalanceResponse{
+		Balance: int(user.TokenBalance.Int64),
+		Plan:    user.Plan,
+	}
+	if subscription != nil {
+		res.Plan = subscription.Plan
+		res.NextRefill = ptr.StringToPtr(date.Format(subscription.RefillPeriodEnd(now)))
+	}
+	return res
+}
+
+func ToTokenTransactionListResponse(transactions []*domain.TokenTransaction, total int64) *TokenTransactionListResponse {
+	items := make([]*TokenTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		items = append(items, &TokenTransaction{
+			ID:          tx.ID,
+			Type:        tx.Type,
+			Amount:      tx.Amount,
+			Balance:     tx.Balance,
+			Description: tx.Description,
+			ReferenceID: tx.ReferenceID,
+			CreatedAt:   date.Format(tx.CreatedAt),
+		})
+	}
+	return &TokenTransactionListResponse{
+		Transactions: items,
+		Total:        total,
+	}
+}
+
+func ToTokenCostEstimateResponse(estimate *service.TokenCostEstimate, user *domain.User) *TokenCostEstimateResponse {
+	available := int(user.TokenBalance.Int64)
+	return &TokenCostEstimateResponse{
+		Required:   estimate.Total,
+		Available:  available,
+		Sufficient: available >= estimate.Total,
+		Breakdown: TokenCostBreakdown{
+			ImageAnalysis:    estimate.ImageAnalysis,
+			VideoAnalysis:    estimate.VideoAnalysis,
+			ScriptGeneration: estimate.ScriptGeneration,
+			VideoGeneration:  estimate.VideoGeneration,
+		},
+	}
+}
@@ -1,6 +1,12 @@
 package response
 
-import "github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+import (
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+)
 
 type VLogListResponse struct {
 	Total int        `json:"total"`
@@ -10,10 +16,12 @@ type VLogListResponse struct {
 type VLogItem struct {
 	ID           string  `json:"id"`
 	VideoID      string  `json:"video_id"`
+	Title        string  `json:"title"`
 	VideoURL     string  `json:"video_url"`
 	ShareURL     string  `json:"share_url"`
 	Duration     float64 `json:"duration"`
 	Thumbnail    string  `json:"thumbnail"`
+	Preview      string  `json:"preview,omitempty"`
 	Status       string  `json:"status"`
 	ErrorMessage string  `json:"error_message,omitempty"`
 	Progress     float64 `json:"progress"`
@@ -21,16 +29,124 @@ type VLogItem struct {
 }
 
 type VLogGetByIDResponse struct {
-	ID           string  `json:"id"`
-	VideoID      string  `json:"video_id"`
-	VideoURL     string  `json:"video_url"`
-	ShareURL     string  `json:"share_url"`
-	Duration     float64 `json:"duration"`
-	Thumbnail    string  `json:"thumbnail"`
-	Status       string  `json:"status"`
-	ErrorMessage string  `json:"error_message,omitempty"`
-	Progress     float64 `json:"progress"`
-	CreatedAt    string  `json:"created_at"`
+	ID           string         `json:"id"`
+	VideoID      string         `json:"video_id"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	VideoURL     string         `json:"video_url"`
+	ShareURL     string         `json:"share_url"`
+	Duration     float64        `json:"duration"`
+	Thumbnail    string         `json:"thumbnail"`
+	Preview      string         `json:"preview,omitempty"`
+	Status       string         `json:"status"`
+	ErrorMessage string         `json:"error_message,omitempty"`
+	Progress     float64        `json:"progress"`
+	Subtitles    []VLogSubtitle `json:"subtitles"`
+	Analytics    VLogAnalytics  `json:"analytics"`
+	MusicTrackID string         `json:"music_track_id,omitempty"` // BGMに使用した曲
+	CreatedAt    string         `json:"created_at"`
+}
+
+// VLogSubtitle はVLogの字幕の1区間
+type VLogSubtitle struct {
+	Index     int     `json:"index"`
+	StartTime float64 `json:"start_time"` // 秒
+	EndTime   float64 `json:"end_time"`   // 秒
+	Text      string  `json:"text"`
+}
+
+// VLogAnalytics はVLogに使用したメディアの分析結果のサマリー
+type VLogAnalytics struct {
+	Locations  []string `json:"locations"`
+	Activities []string `json:"activities"`
+	Mood       string   `json:"mood"`
+	Highlights []string `json:"highlights"`
+	MediaCount int      `json:"media_count"`
+}
+
+// VLogStreamEvent はVLog進捗SSEのイベント
+// VLogの状態に加えて、生成中のステップを含む
+type VLogStreamEvent struct {
+	VLogGetByIDResponse
+	Step        string     `json:"step,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	CurrentItem int        `json:"current_item,omitempty"`
+	TotalItems  int        `json:"total_items,omitempty"`
+	Steps       []VLogStep `json:"steps,omitempty"` // 終了したステップ
+}
+
+// VLogStep はVLog生成フローのステップの実行記録
+type VLogStep struct {
+	Name         string     `json:"name"`
+	ItemIndex    int        `json:"item_index,omitempty"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	DurationMs   int64      `json:"duration_ms"`
+	RetryCount   int        `json:"retry_count"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+}
+
+// VLogTimelineResponse はVLog生成のタイムラインAPIのレスポンス
+type VLogTimelineResponse struct {
+	VlogID      string     `json:"vlog_id"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Steps       []VLogStep `json:"steps"`
+}
+
+// VLogStoryboardResponse はVLogの絵コンテ
+type VLogStoryboardResponse struct {
+	VlogID        string                `json:"vlog_id"`
+	Scenes        []VLogStoryboardScene `json:"scenes"`
+	TotalDuration int                   `json:"total_duration"` // シーンの長さの合計（秒）
+	ApprovedAt    *time.Time            `json:"approved_at,omitempty"`
+}
+
+// VLogStoryboardScene は絵コンテの1シーン
+type VLogStoryboardScene struct {
+	MediaIDs        []string `json:"media_ids"`
+	CameraDirection string   `json:"camera_direction"`
+	VeoPrompt       string   `json:"veo_prompt"`
+	Caption         string   `json:"caption"`
+	DurationSeconds int      `json:"duration_seconds"`
+}
+
+// VLogDraftResponse は編集可能なVLogの下書き
+type VLogDraftResponse struct {
+	VlogID          string                `json:"vlog_id"`
+	Status          string                `json:"status"`
+	Title           string                `json:"title"`
+	Description     string                `json:"description"`
+	Style           VLogDraftStyle        `json:"style"`
+	Media           []VLogDraftMedia      `json:"media"` // 使用するメディア（並び順）
+	Scenes          []VLogStoryboardScene `json:"scenes"`
+	TotalDuration   int                   `json:"total_duration"`   // シーンの長さの合計（秒）
+	EstimatedTokens int                   `json:"estimated_tokens"` // 動画の生成に必要なトークン数
+}
+
+// VLogDraftStyle は下書きのスタイル設定
+type VLogDraftStyle struct {
+	Theme         string              `json:"theme"`
+	MusicMood     string              `json:"music_mood"`
+	Transition    string              `json:"transition"`
+	ReferenceMode string              `json:"reference_mode"`
+	SubtitleMode  string              `json:"subtitle_mode"`
+	Narration     *VLogDraftNarration `json:"narration,omitempty"` // ナレーションなしの場合は省略
+}
+
+// VLogDraftNarration は下書きのナレーションの設定
+type VLogDraftNarration struct {
+	SpeakerID  int     `json:"speaker_id"`
+	SpeedScale float64 `json:"speed_scale,omitempty"`
+}
+
+// VLogDraftMedia は下書きに使用するメディア
+type VLogDraftMedia struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
 }
 
 // CreateVLogResponse はVLog生成APIのレスポンス
@@ -58,10 +174,12 @@ func ToVLogItem(vlog *domain.Vlog) VLogItem {
 	return VLogItem{
 		ID:           vlog.ID,
 		VideoID:      vlog.VideoID,
+		Title:        vlog.Title,
 		ShareURL:     vlog.ShareURL,
 		Duration:     vlog.Duration,
 		VideoURL:     vlog.VideoURL,
 		Thumbnail:    vlog.Thumbnail,
+		Preview:      vlog.Preview,
 		Status:       string(vlog.Status),
 		ErrorMessage: vlog.ErrorMessage,
 		Progress:     vlog.Progress,
@@ -73,13 +191,171 @@ func ToVLogGetByIDResponse(vlog *domain.Vlog) VLogGetByIDResponse {
 	return VLogGetByIDResponse{
 		ID:           vlog.ID,
 		VideoID:      vlog.VideoID,
+		Title:        vlog.Title,
+		Description:  vlog.Description,
 		ShareURL:     vlog.ShareURL,
 		Duration:     vlog.Duration,
 		VideoURL:     vlog.VideoURL,
 		Thumbnail:    vlog.Thumbnail,
+		Preview:      vlog.Preview,
 		Status:       string(vlog.Status),
 		ErrorMessage: vlog.ErrorMessage,
 		Progress:     vlog.Progress,
+		Subtitles:    ToVLogSubtitles(vlog.Subtitles),
+		Analytics:    ToVLogAnalytics(vlog.Analytics),
+		MusicTrackID: ptr.PtrToString(vlog.MusicTrackID),
 		CreatedAt:    vlog.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
+
+func ToVLogSubtitles(segments []domain.SubtitleSegment) []VLogSubtitle {
+	subtitles := make([]VLogSubtitle, 0, len(segments))
+	for _, segment := range segments {
+		subtitles = append(subtitles, VLogSubtitle{
+			Index:     segment.Index,
+			StartTime: segment.StartSeconds(),
+			EndTime:   segment.EndSeconds(),
+			Text:      segment.Text,
+		})
+	}
+	return subtitles
+}
+
+// ToVLogAnalytics は分析結果のサマリーを返す（未設定の項目は空の配列にする）
+func ToVLogAnalytics(analytics domain.VlogAnalytics) VLogAnalytics {
+	return VLogAnalytics{
+		Locations:  nonNilStrings(analytics.Locations),
+		Activities: nonNilStrings(analytics.Activities),
+		Mood:       analytics.Mood,
+		Highlights: nonNilStrings(analytics.Highlights),
+		MediaCount: analytics.MediaCount,
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func ToVLogStreamEvent(vlog *domain.Vlog, progress *agent.FlowProgress, steps []*domain.VlogStep) VLogStreamEvent {
+	res := VLogStreamEvent{VLogGetByIDResponse: ToVLogGetByIDResponse(vlog)}
+	for _, step := range steps {
+		if step.EndedAt != nil {
+			res.Steps = append(res.Steps, ToVLogStep(step))
+		}
+	}
+	if progress != nil {
+		res.Step = progress.Step
+		res.Message = progress.Message
+		res.CurrentItem = progress.CurrentItem
+		res.TotalItems = progress.TotalItems
+	}
+	return res
+}
+
+func ToVLogStep(step *domain.VlogStep) VLogStep {
+	return VLogStep{
+		Name:         step.Name,
+		ItemIndex:    step.ItemIndex,
+		Status:       step.Status,
+		StartedAt:    step.StartedAt,
+		EndedAt:      step.EndedAt,
+		DurationMs:   step.Duration().Milliseconds(),
+		RetryCount:   step.RetryCount,
+		ErrorMessage: step.ErrorMessage,
+	}
+}
+
+func ToVLogTimelineResponse(vlog *domain.Vlog, steps []*domain.VlogStep) VLogTimelineResponse {
+	res := VLogTimelineResponse{
+		VlogID:      vlog.ID,
+		Status:      string(vlog.Status),
+		StartedAt:   vlog.StartedAt,
+		CompletedAt: vlog.CompletedAt,
+		Steps:       make([]VLogStep, 0, len(steps)),
+	}
+	for _, step := range steps {
+		res.Steps = append(res.Steps, ToVLogStep(step))
+	}
+	return res
+}
+
+func ToVLogStoryboardResponse(storyboard *domain.VlogStoryboard) VLogStoryboardResponse {
+	res := VLogStoryboardResponse{
+		VlogID:        storyboard.VlogID,
+		Scenes:        make([]VLogStoryboardScene, 0, len(storyboard.Scenes)),
+		TotalDuration: storyboard.TotalDuration(),
+		ApprovedAt:    storyboard.ApprovedAt,
+	}
+	for _, scene := range storyboard.Scenes {
+		res.Scenes = append(res.Scenes, VLogStoryboardScene{
+			MediaIDs:        nonNilStrings(scene.MediaIDs),
+			CameraDirection: scene.CameraDirection,
+			VeoPrompt:       scene.VeoPrompt,
+			Caption:         scene.Caption,
+			DurationSeconds: scene.DurationSeconds,
+		})
+	}
+	return res
+}
+
+// ToVLogDraftResponse はVLogと絵コンテ、VLog生成の入力から下書きを作成する
+func ToVLogDraftResponse(vlog *domain.Vlog, storyboard *domain.VlogStoryboard, input *agent.VlogInput, estimatedTokens int) VLogDraftResponse {
+	plan := ToVLogStoryboardResponse(storyboard)
+	res := VLogDraftResponse{
+		VlogID:      vlog.ID,
+		Status:      string(vlog.Status),
+		Title:       vlog.Title,
+		Description: vlog.Description,
+		Style: VLogDraftStyle{
+			Theme:         input.Style.Theme,
+			MusicMood:     input.Style.MusicMood,
+			Transition:    input.Style.Transition,
+			ReferenceMode: input.Style.ReferenceMode,
+			SubtitleMode:  input.Style.SubtitleMode,
+		},
+		Media:           make([]VLogDraftMedia, 0, len(input.MediaItems)),
+		Scenes:          plan.Scenes,
+		TotalDuration:   plan.TotalDuration,
+		EstimatedTokens: estimatedTokens,
+	}
+	if narration := input.Style.Narration; narration != nil {
+		res.Style.Narration = &VLogDraftNarration{
+			SpeakerID:  narration.SpeakerID,
+			SpeedScale: narration.SpeedScale,
+		}
+	}
+	for _, item := range input.MediaItems {
+		res.Media = append(res.Media, VLogDraftMedia{
+			ID:   item.FileID,
+			Type: item.Type,
+			URL:  item.URL,
+		})
+	}
+	return res
+}
+
+// ToMediaStatusResponse はメディアIDの順にメディア分析の進捗を集計する
+func ToMediaStatusResponse(mediaIDs []string, medias map[string]*domain.Media) MediaStatusResponse {
+	res := MediaStatusResponse{
+		Medias:     make([]*domain.Media, 0, len(mediaIDs)),
+		TotalItems: len(mediaIDs),
+	}
+	for _, id := range mediaIDs {
+		media, ok := medias[id]
+		if !ok {
+			continue
+		}
+		res.Medias = append(res.Medias, media)
+		switch media.Status {
+		case domain.MediaStatusCompleted:
+			res.CompletedItems++
+		case domain.MediaStatusFailed:
+			res.FailedItems++
+		}
+	}
+	res.AllCompleted = res.CompletedItems+res.FailedItems == len(mediaIDs)
+	return res
+}
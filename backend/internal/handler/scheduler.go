@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type ISchedulerServer interface {
+	RunSubscriptionJobs(c echo.Context) error
+}
+
+type SchedulerServer struct {
+	subscriptionScheduler service.ISubscriptionScheduler
+}
+
+func NewSchedulerServer(subscriptionScheduler service.ISubscriptionScheduler) *SchedulerServer {
+	return &SchedulerServer{
+		subscriptionScheduler: subscriptionScheduler,
+	}
+}
+
+// RunSubscriptionJobs サブスクリプションの定期処理（トークン付与・期限切れ処理）を実行する
+// Cloud Schedulerから定期的に呼び出される（何度呼び出しても二重付与されない）
+func (s *SchedulerServer) RunSubscriptionJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	result, err := s.subscriptionScheduler.Run(ctx, time.Now())
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToSubscriptionJobResponse(result))
+}
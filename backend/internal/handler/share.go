@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+// SharePasswordHeader はパスワード付きの共有リンクのパスワードを送るヘッダー
+const SharePasswordHeader = "X-Share-Password"
+
+type IShareServer interface {
+	GetShared(ctx echo.Context) error
+	Get(ctx echo.Context) error
+	Issue(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+}
+
+type ShareServer struct {
+	vlogRepo     domain.IVLogRepository
+	shareService service.IVlogShareService
+}
+
+func NewShareServer(vlogRepo domain.IVLogRepository, shareService service.IVlogShareService) *ShareServer {
+	return &ShareServer{
+		vlogRepo:     vlogRepo,
+		shareService: shareService,
+	}
+}
+
+// GetShared 共有コードからVLogを返す（認証不要）
+// パスワード付きの共有リンクはX-Share-Passwordヘッダーでパスワードを受け取る
+func (s *ShareServer) GetShared(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.SharedVLogRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	share, vlog, err := s.shareService.Resolve(ctx, req.Code, c.Request().Header.Get(SharePasswordHeader))
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return c.JSON(http.StatusOK, response.ToSharedVLogResponse(share, vlog))
+}
+
+// Get VLogの有効な共有リンクを返す
+func (s *ShareServer) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogShareRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.getOwnedVLog(ctx, req.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	share, err := s.shareService.Current(ctx, vlog.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return c.JSON(http.StatusOK, response.ToVLogShareResponse(share, s.shareService.ShareURL(share.Code)))
+}
+
+// Issue 共有リンクを再発行する（以前の共有コードは無効になる）
+func (s *ShareServer) Issue(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogIssueShareRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.getOwnedVLog(ctx, req.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.Status != domain.VlogStatusCompleted {
+		return errors.MakeConflictError(ctx, "生成が完了したVLogのみ共有できます")
+	}
+
+	opts := service.ShareOptions{ExpiresAt: req.ExpiresAt}
+	if req.Password != nil {
+		opts.Password = *req.Password
+	}
+	share, err := s.shareService.Issue(ctx, vlog, opts)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return c.JSON(http.StatusCreated, response.ToVLogShareResponse(share, vlog.ShareURL))
+}
+
+// Revoke 共有リンクを無効にする
+func (s *ShareServer) Revoke(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogShareRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.getOwnedVLog(ctx, req.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := s.shareService.Revoke(ctx, vlog); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getOwnedVLog はログインユーザーが作成したVLogを取得する
+func (s *ShareServer) getOwnedVLog(ctx context.Context, id string) (*domain.Vlog, error) {
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: id}})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return nil, errors.MakeForbiddenError(ctx, "このVLogの共有リンクを管理する権限がありません")
+	}
+	return vlog, nil
+}
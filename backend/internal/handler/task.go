@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type ITaskServer interface {
+	Definitions() []*queue.Definition
+	Process(def *queue.Definition) echo.HandlerFunc
+}
+
+type TaskServer struct {
+	registry *queue.Registry
+}
+
+func NewTaskServer(registry *queue.Registry) *TaskServer {
+	return &TaskServer{
+		registry: registry,
+	}
+}
+
+// Definitions はエンドポイントを公開するタスク定義を返す
+func (s *TaskServer) Definitions() []*queue.Definition {
+	return s.registry.Definitions()
+}
+
+// Process はCloud Tasksからのリクエストを受け取り、タスク定義の処理関数を実行する
+func (s *TaskServer) Process(def *queue.Definition) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var task queue.Task
+		if err := c.Bind(&task); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if task.Type != def.Type {
+			return errors.MakeBusinessError(ctx, "タスクタイプがエンドポイントと一致しません")
+		}
+
+		if err := def.Handle(ctx, &task); err != nil {
+			if errors.Is(err, queue.ErrInvalidPayload) {
+				return errors.MakeBusinessError(ctx, err.Error())
+			}
+			return errors.Wrap(ctx, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"gorm.io/gorm"
+)
+
+type ITokenServer interface {
+	GetTokens(c echo.Context) error
+	ListTransactions(c echo.Context) error
+	Estimate(c echo.Context) error
+}
+
+type TokenServer struct {
+	userRepo           domain.IUserRepository
+	tokenTxRepo        domain.ITokenTransactionRepository
+	subscriptionRepo   domain.ISubscriptionRepository
+	mediaRepo          domain.IMediaRepository
+	mediaAnalyticsRepo domain.IMediaAnalyticsRepository
+}
+
+func NewTokenServer(
+	userRepo domain.IUserRepository,
+	tokenTxRepo domain.ITokenTransactionRepository,
+	subscriptionRepo domain.ISubscriptionRepository,
+	mediaRepo domain.IMediaRepository,
+	mediaAnalyticsRepo domain.IMediaAnalyticsRepository,
+) *TokenServer {
+	return &TokenServer{
+		userRepo:           userRepo,
+		tokenTxRepo:        tokenTxRepo,
+		subscriptionRepo:   subscriptionRepo,
+		mediaRepo:          mediaRepo,
+		mediaAnalyticsRepo: mediaAnalyticsRepo,
+	}
+}
+
+// GetTokens ログインユーザーのトークン残高・プラン・次回のトークン付与日時を取得
+func (s *TokenServer) GetTokens(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := s.findLoginUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := s.subscriptionRepo.FindActiveByUserID(ctx, user.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.Wrap(ctx, err)
+		}
+		subscription = nil
+	}
+
+	return c.JSON(http.StatusOK, response.ToTokenBalanceResponse(user, subscription, time.Now()))
+}
+
+// ListTransactions ログインユーザーのトークン取引履歴を取得
+func (s *TokenServer) ListTransactions(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := Ctx.GetCtxFromUser(ctx)
+
+	var query request.TransactionListQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&query); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	limit := ptr.PtrToInt(query.Limit)
+	if limit == 0 {
+		limit = 20
+	}
+	transactions, err := s.tokenTxRepo.FindByUserID(ctx, userID, &domain.ListOpts{
+		Limit:  limit,
+		Offset: ptr.PtrToInt(query.Offset),
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	total, err := s.tokenTxRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToTokenTransactionListResponse(transactions, total))
+}
+
+// Estimate アップロード済みのメディアからVLog生成に必要なトークン数を見積もる
+func (s *TokenServer) Estimate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req request.EstimateTokenCostRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	user, err := s.findLoginUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := make([]agent.MediaItem, 0, len(req.MediaIDs))
+	for _, id := range req.MediaIDs {
+		item, err := s.estimateMediaItem(ctx, user.ID, id)
+		if err != nil {
+			return err
+		}
+		items = append(items, *item)
+	}
+	estimate := service.EstimateVlogTokenCost(&agent.VlogInput{
+		UserID:     user.ID,
+		MediaItems: items,
+		Style:      agent.VlogStyle{Duration: resolveVlogDuration(req.Duration)},
+	})
+
+	return c.JSON(http.StatusOK, response.ToTokenCostEstimateResponse(estimate, user))
+}
+
+// estimateMediaItem はログインユーザーのメディアの種類・動画の長さ・分析済みかどうかを取得する
+// 動画の長さはアップロード時に動画のメタデータから取得した値を使用する
+func (s *TokenServer) estimateMediaItem(ctx context.Context, userID, mediaID string) (*agent.MediaItem, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.MakeNotFoundError(ctx, "Media not found")
+		}
+		return nil, errors.Wrap(ctx, err)
+	}
+	if media.CreateUserID == nil || *media.CreateUserID != userID {
+		return nil, errors.MakeForbiddenError(ctx, "このメディアを使用する権限がありません")
+	}
+
+	mediaAnalytics, err := s.mediaAnalyticsRepo.FindByFileID(ctx, media.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(ctx, err)
+	}
+
+	return &agent.MediaItem{
+		FileID:     media.ID,
+		Type:       detectMediaType(media.ContentType),
+		Duration:   media.Duration,
+		IsAnalyzed: mediaAnalytics != nil,
+	}, nil
+}
+
+// findLoginUser ログインユーザーを取得
+func (s *TokenServer) findLoginUser(ctx context.Context) (*domain.User, error) {
+	userID := Ctx.GetCtxFromUser(ctx)
+
+	user, err := s.userRepo.FindByID(ctx, &domain.User{BaseModel: domain.BaseModel{ID: userID}})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.MakeNotFoundError(ctx, "User not found")
+		}
+		return nil, errors.Wrap(ctx, err)
+	}
+	return user, nil
+}
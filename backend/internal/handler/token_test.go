@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/date"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	domain.IUserRepository
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, user *domain.User) (*domain.User, error) {
+	found, ok := r.users[user.ID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found, nil
+}
+
+type fakeSubscriptionRepo struct {
+	domain.ISubscriptionRepository
+	active map[string]*domain.Subscription
+}
+
+func (r *fakeSubscriptionRepo) FindActiveByUserID(ctx context.Context, userID string) (*domain.Subscription, error) {
+	subscription, ok := r.active[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return subscription, nil
+}
+
+type fakeMediaRepo struct {
+	domain.IMediaRepository
+	media map[string]*domain.Media
+}
+
+func (r *fakeMediaRepo) GetByID(ctx context.Context, id string) (*domain.Media, error) {
+	media, ok := r.media[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return media, nil
+}
+
+type fakeMediaAnalyticsRepo struct {
+	domain.IMediaAnalyticsRepository
+	analyzed map[string]bool
+}
+
+func (r *fakeMediaAnalyticsRepo) FindByFileID(ctx context.Context, fileID string) (*domain.MediaAnalytics, error) {
+	if !r.analyzed[fileID] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &domain.MediaAnalytics{FileID: fileID}, nil
+}
+
+func TestTokenServer_GetTokens(t *testing.T) {
+	periodEnd := time.Now().AddDate(0, 3, 0).Add(12 * time.Hour).Truncate(time.Second)
+	userRepo := &fakeUserRepo{users: map[string]*domain.User{
+		"free-user":    {BaseModel: domain.BaseModel{ID: "free-user"}, Plan: constant.UserPlanFree, TokenBalance: sql.NullInt64{Int64: 30, Valid: true}},
+		"premium-user": {BaseModel: domain.BaseModel{ID: "premium-user"}, Plan: constant.UserPlanPremium, TokenBalance: sql.NullInt64{Int64: 230, Valid: true}},
+	}}
+	subscriptionRepo := &fakeSubscriptionRepo{active: map[string]*domain.Subscription{
+		"premium-user": {UserID: "premium-user", Plan: domain.SubscriptionPlanYearly, Status: domain.SubscriptionStatusActive, CurrentPeriodEnd: periodEnd},
+	}}
+	tokenServer := handler.NewTokenServer(userRepo, nil, subscriptionRepo, nil, nil)
+
+	getTokens := func(t *testing.T, userID string) *response.TokenBalanceResponse {
+		rec, err := serveVLogRequest(tokenServer.GetTokens, http.MethodGet, "/api/user/tokens", "", userID, "")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res response.TokenBalanceResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return &res
+	}
+
+	t.Run("サブスクリプションを契約していない場合は次回のトークン付与日時がnull", func(t *testing.T) {
+		res := getTokens(t, "free-user")
+		assert.Equal(t, 30, res.Balance)
+		assert.Equal(t, constant.UserPlanFree, res.Plan)
+		assert.Nil(t, res.NextRefill)
+	})
+
+	t.Run("年額プランは契約期間内の次の月次付与日時を返す", func(t *testing.T) {
+		res := getTokens(t, "premium-user")
+		assert.Equal(t, 230, res.Balance)
+		assert.Equal(t, domain.SubscriptionPlanYearly, res.Plan)
+		require.NotNil(t, res.NextRefill)
+		assert.Equal(t, date.Format(periodEnd.AddDate(0, -3, 0)), *res.NextRefill)
+	})
+}
+
+func TestTokenServer_Estimate(t *testing.T) {
+	userRepo := &fakeUserRepo{users: map[string]*domain.User{
+		"owner": {BaseModel: domain.BaseModel{ID: "owner"}, Plan: constant.UserPlanFree, TokenBalance: sql.NullInt64{Int64: 300, Valid: true}},
+	}}
+	mediaRepo := &fakeMediaRepo{media: map[string]*domain.Media{
+		"video": {BaseModel: domain.BaseModel{ID: "video", CreateUserID: ptr.StringToPtr("owner")}, ContentType: "video/mp4", Duration: 95},
+		"photo": {BaseModel: domain.BaseModel{ID: "photo", CreateUserID: ptr.StringToPtr("owner")}, ContentType: "image/jpeg"},
+		"other": {BaseModel: domain.BaseModel{ID: "other", CreateUserID: ptr.StringToPtr("other-user")}, ContentType: "video/mp4", Duration: 5},
+	}}
+	analyticsRepo := &fakeMediaAnalyticsRepo{analyzed: map[string]bool{"photo": true}}
+	tokenServer := handler.NewTokenServer(userRepo, nil, nil, mediaRepo, analyticsRepo)
+
+	estimate := func(body string) (*httptest.ResponseRecorder, error) {
+		return serveVLogRequest(tokenServer.Estimate, http.MethodPost, "/api/user/tokens/estimate", body, "owner", "")
+	}
+
+	t.Run("動画の長さ・分析済みかどうかはアップロード済みのメディアから求める", func(t *testing.T) {
+		rec, err := estimate(`{"media_ids":["video","photo"],"duration":60}`)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res response.TokenCostEstimateResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, 10*constant.TokenCostVideoAnalysisPer10Sec, res.Breakdown.VideoAnalysis)
+		assert.Zero(t, res.Breakdown.ImageAnalysis)
+		assert.Equal(t, 10*constant.TokenCostVideoAnalysisPer10Sec+constant.TokenCostScriptGeneration+constant.TokenCostVideoGenerationPerMinute, res.Required)
+		assert.Equal(t, 300, res.Available)
+	})
+
+	t.Run("クライアントが申告した動画の長さは使用しない", func(t *testing.T) {
+		_, err := estimate(`{"media_items":[{"type":"video","duration":1}]}`)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeInValidArgument, errors.GetCode(err))
+	})
+
+	t.Run("他のユーザーのメディアは見積もりに使用できない", func(t *testing.T) {
+		_, err := estimate(`{"media_ids":["other"]}`)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeForbidden, errors.GetCode(err))
+	})
+
+	t.Run("存在しないメディアは見つからないエラー", func(t *testing.T) {
+		_, err := estimate(`{"media_ids":["missing"]}`)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeNotFound, errors.GetCode(err))
+	})
+}
@@ -1,32 +1,67 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/subtitle"
+	"gorm.io/gorm"
 )
 
 type IVLogServer interface {
 	List(ctx echo.Context) error
 	GetByID(ctx echo.Context) error
+	Update(ctx echo.Context) error
+	Subtitles(ctx echo.Context) error
 	Delete(ctx echo.Context) error
 	StreamStatus(ctx echo.Context) error
+	Retry(ctx echo.Context) error
+	Cancel(ctx echo.Context) error
+	Timeline(ctx echo.Context) error
+	Storyboard(ctx echo.Context) error
+	UpdateStoryboard(ctx echo.Context) error
+	Render(ctx echo.Context) error
+	Draft(ctx echo.Context) error
+	UpdateDraft(ctx echo.Context) error
+	UpdateDraftScene(ctx echo.Context) error
 }
 
 type VLogServer struct {
-	vlogRepo domain.IVLogRepository
+	vlogRepo          domain.IVLogRepository
+	vlogStepRepo      domain.IVlogStepRepository
+	deadLetterService service.IDeadLetterService
+	tokenLedger       service.ITokenLedger
+	txManager         domain.ITransactionManager
+	progressBus       progress.IBus
+	storyboardRepo    domain.IVlogStoryboardRepository
+	taskQueue         queue.IQueue
 }
 
-func NewVLogServer(vlogRepo domain.IVLogRepository) *VLogServer {
+func NewVLogServer(vlogRepo domain.IVLogRepository, vlogStepRepo domain.IVlogStepRepository, deadLetterService service.IDeadLetterService, tokenLedger service.ITokenLedger, txManager domain.ITransactionManager, progressBus progress.IBus, storyboardRepo domain.IVlogStoryboardRepository, taskQueue queue.IQueue) *VLogServer {
 	return &VLogServer{
-		vlogRepo: vlogRepo,
+		vlogRepo:          vlogRepo,
+		vlogStepRepo:      vlogStepRepo,
+		deadLetterService: deadLetterService,
+		tokenLedger:       tokenLedger,
+		txManager:         txManager,
+		progressBus:       progressBus,
+		storyboardRepo:    storyboardRepo,
+		taskQueue:         taskQueue,
 	}
 }
 
@@ -79,10 +114,102 @@ func (s *VLogServer) GetByID(c echo.Context) error {
 	if err != nil {
 		return errors.Wrap(ctx, err)
 	}
+	// 他のユーザーは共有リンク（パスワード・有効期限を確認する）からのみ閲覧できる
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogを閲覧する権限がありません")
+	}
 	res := response.ToVLogGetByIDResponse(vlog)
 	return c.JSON(http.StatusOK, res)
 }
 
+// Update AIが生成したタイトル・説明文・字幕を編集する
+func (s *VLogServer) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	var vlog *domain.Vlog
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		vlog, err = s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+			return errors.MakeForbiddenError(ctx, "このVLogを編集する権限がありません")
+		}
+		if vlog.Status != domain.VlogStatusCompleted {
+			return errors.MakeConflictError(ctx, "生成が完了したVLogのみ編集できます")
+		}
+
+		if req.Title != nil {
+			vlog.Title = *req.Title
+		}
+		if req.Description != nil {
+			vlog.Description = *req.Description
+		}
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.MakeConflictError(ctx, "VLogが更新されました。再度お試しください")
+			}
+			return errors.Wrap(ctx, err)
+		}
+		if req.Subtitles == nil {
+			return nil
+		}
+		vlog.Subtitles = make([]domain.SubtitleSegment, 0, len(req.Subtitles))
+		for i, subtitle := range req.Subtitles {
+			vlog.Subtitles = append(vlog.Subtitles, domain.NewSubtitleSegment(i+1, subtitle.StartTime, subtitle.EndTime, subtitle.Text))
+		}
+		return s.vlogRepo.ReplaceSubtitles(ctx, vlog)
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToVLogGetByIDResponse(vlog))
+}
+
+// Subtitles VLogの字幕をSRT・WebVTT・JSONのファイルで返す
+func (s *VLogServer) Subtitles(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogSubtitlesRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	format, ok := subtitle.ParseFormat(req.Format)
+	if !ok {
+		return errors.MakeInvalidArgumentError(ctx, "字幕の形式はsrt・vtt・jsonのいずれかを指定してください")
+	}
+
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogの字幕を取得する権限がありません")
+	}
+
+	cues := make([]subtitle.Cue, 0, len(vlog.Subtitles))
+	for _, segment := range vlog.Subtitles {
+		cues = append(cues, segment.Cue())
+	}
+	data, err := subtitle.Encode(format, cues)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.%s"`, vlog.ID, format))
+	return c.Blob(http.StatusOK, format.ContentType(), data)
+}
+
 func (s *VLogServer) Delete(c echo.Context) error {
 	ctx := c.Request().Context()
 	var req request.VLogDeleteRequest
@@ -104,9 +231,11 @@ func (s *VLogServer) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
-func (s *VLogServer) StreamStatus(c echo.Context) error {
+// Retry 失敗したVLog生成を保存済みの入力で再実行する
+// 再試行回数を使い切ってデッドレターに移動したVLogのみ再実行できる
+func (s *VLogServer) Retry(c echo.Context) error {
 	ctx := c.Request().Context()
-	var req request.VLogGetByIDRequest
+	var req request.VLogRetryRequest
 	if err := c.Bind(&req); err != nil {
 		return errors.Wrap(ctx, err)
 	}
@@ -114,50 +243,361 @@ func (s *VLogServer) StreamStatus(c echo.Context) error {
 		return errors.Wrap(ctx, err)
 	}
 
-	// SSEヘッダー設定
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().WriteHeader(http.StatusOK)
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogを再実行する権限がありません")
+	}
+
+	if _, err := s.deadLetterService.RequeueTask(ctx, queue.TaskTypeProcessVLog, vlog.ID); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusAccepted, response.CreateVLogResponse{
+		VlogID: vlog.ID,
+		Status: string(domain.VlogStatusPending),
+	})
+}
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+// Cancel 生成待ち・生成中・絵コンテの確認待ちのVLogをキャンセルし、仮引きしたトークンを返却する
+// 生成中のワーカーは次の確認時点でキャンセルを検知して処理を中断する
+func (s *VLogServer) Cancel(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogCancelRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
 
-	// 最初に現在の状態を送信
-	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{
-		BaseModel: domain.BaseModel{ID: req.ID},
+	var vlog *domain.Vlog
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		vlog, err = s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+			return errors.MakeForbiddenError(ctx, "このVLogをキャンセルする権限がありません")
+		}
+		if !vlog.Status.IsCancellable() {
+			return errors.MakeConflictError(ctx, "生成待ち・生成中・絵コンテの確認待ちのVLogのみキャンセルできます")
+		}
+
+		now := time.Now()
+		vlog.Status = domain.VlogStatusCancelled
+		vlog.CancelledAt = &now
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.MakeConflictError(ctx, "VLogの状態が更新されました。再度お試しください")
+			}
+			return errors.Wrap(ctx, err)
+		}
+		return s.tokenLedger.Rollback(ctx, vlog.ReservationReferenceID())
 	})
-	if err == nil {
-		res := response.ToVLogGetByIDResponse(vlog)
-		data, _ := json.Marshal(res)
-		fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-		c.Response().Flush()
+	if err != nil {
+		return errors.Wrap(ctx, err)
 	}
+	publishVLogEvent(ctx, s.progressBus, vlog, nil, nil)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			// VLogステータスを取得
-			vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{
-				BaseModel: domain.BaseModel{ID: req.ID},
+	return c.JSON(http.StatusOK, response.ToVLogGetByIDResponse(vlog))
+}
+
+// Storyboard VLogの絵コンテを返す
+func (s *VLogServer) Storyboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogStoryboardRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogの絵コンテを取得する権限がありません")
+	}
+	storyboard, err := s.storyboardRepo.FindByVlogID(ctx, vlog.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.MakeNotFoundError(ctx, "絵コンテが見つかりません")
+		}
+		return errors.Wrap(ctx, err)
+	}
+	return c.JSON(http.StatusOK, response.ToVLogStoryboardResponse(storyboard))
+}
+
+// UpdateStoryboard 絵コンテの確認待ちのVLogの絵コンテを編集する
+// 動画の生成に必要なトークンは生成開始時に編集後の絵コンテから見積もる
+func (s *VLogServer) UpdateStoryboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogStoryboardUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	var storyboard *domain.VlogStoryboard
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		_, storyboard, err = s.reviewingStoryboard(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		input, err := storyboardInput(ctx, storyboard)
+		if err != nil {
+			return err
+		}
+
+		storyboard.Scenes = make([]domain.StoryboardScene, 0, len(req.Scenes))
+		for _, scene := range req.Scenes {
+			if err := validateSceneMediaIDs(ctx, input.MediaItems, scene.MediaIDs); err != nil {
+				return err
+			}
+			storyboard.Scenes = append(storyboard.Scenes, domain.StoryboardScene{
+				MediaIDs:        scene.MediaIDs,
+				CameraDirection: scene.CameraDirection,
+				VeoPrompt:       scene.VeoPrompt,
+				Caption:         scene.Caption,
+				DurationSeconds: scene.DurationSeconds,
 			})
-			if err != nil {
-				// エラー時は継続を試みるが、あまりにひどい場合は終了
-				continue
+		}
+		if err := s.storyboardRepo.Update(ctx, storyboard); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.MakeConflictError(ctx, "絵コンテが更新されました。再度お試しください")
 			}
+			return errors.Wrap(ctx, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
 
-			// SSEフォーマットでデータ送信
-			res := response.ToVLogGetByIDResponse(vlog)
-			data, _ := json.Marshal(res)
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			c.Response().Flush()
+	return c.JSON(http.StatusOK, response.ToVLogStoryboardResponse(storyboard))
+}
 
-			// 完了または失敗時は最後に1回送信して終了
-			if vlog.Status == domain.VlogStatusCompleted || vlog.Status == domain.VlogStatusFailed {
-				return nil
+// validateSceneMediaIDs はシーンに割り当てるメディアがVLogに使用するメディアに含まれるかを確認する
+func validateSceneMediaIDs(ctx context.Context, items []agent.MediaItem, mediaIDs []string) error {
+	for _, id := range mediaIDs {
+		if !slices.ContainsFunc(items, func(item agent.MediaItem) bool { return item.FileID == id }) {
+			return errors.MakeInvalidArgumentError(ctx, "VLogに使用するメディアのみ指定できます")
+		}
+	}
+	return nil
+}
+
+// Render 確認した絵コンテでVLogの動画の生成を開始する
+// 分析結果とタイトルは絵コンテ作成時のチェックポイントと編集後の下書きを使用し、動画の生成に必要なトークンのみを仮引きする
+func (s *VLogServer) Render(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogRenderRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	var task *queue.Task
+	var vlog *domain.Vlog
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var storyboard *domain.VlogStoryboard
+		var err error
+		vlog, storyboard, err = s.reviewingStoryboard(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		input, err := storyboardInput(ctx, storyboard)
+		if err != nil {
+			return err
+		}
+		input.Storyboard = toAgentStoryboard(storyboard.Scenes)
+		input.Title = vlog.Title
+		input.Description = vlog.Description
+
+		now := time.Now()
+		storyboard.ApprovedAt = &now
+		if err := s.storyboardRepo.Update(ctx, storyboard); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		// 絵コンテを編集し直して再度生成する場合に備え、生成ごとに仮引きの参照IDを分ける
+		vlog.TokenReferenceID = renderTokenReferenceID(vlog.ID, vlog.Version)
+		vlog.Status = domain.VlogStatusPending
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.MakeConflictError(ctx, "VLogの状態が更新されました。再度お試しください")
 			}
+			return errors.Wrap(ctx, err)
+		}
+		estimate := service.EstimateVlogTokenCost(input)
+		if err := s.tokenLedger.Reserve(ctx, input.UserID, vlog.TokenReferenceID, estimate.Total, "VLog動画生成"); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+
+		// 更新後のVLogのバージョンで登録する
+		task, err = queue.NewTask(queue.TaskTypeProcessVLog, vlog.ID, vlog.Version, input, domain.MediaStatusPending.String())
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	// タスクはコミット後に登録する（プロセス内キューがトランザクションを引き継がないようにする）
+	if err := s.taskQueue.Enqueue(ctx, task); err != nil {
+		if abortErr := s.abortRender(ctx, vlog.ID); abortErr != nil {
+			logger.Error(ctx, "failed to abort render", "vlog_id", vlog.ID, "error", abortErr)
 		}
+		return errors.Wrap(ctx, err)
+	}
+	publishVLogEvent(ctx, s.progressBus, vlog, nil, nil)
+
+	return c.JSON(http.StatusAccepted, response.CreateVLogResponse{
+		VlogID: vlog.ID,
+		Status: string(domain.VlogStatusPending),
+	})
+}
+
+// reviewingStoryboard は絵コンテの確認待ちのVLogとその絵コンテを取得する
+func (s *VLogServer) reviewingStoryboard(ctx context.Context, vlogID string) (*domain.Vlog, *domain.VlogStoryboard, error) {
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogID}})
+	if err != nil {
+		return nil, nil, errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return nil, nil, errors.MakeForbiddenError(ctx, "このVLogの絵コンテを編集する権限がありません")
+	}
+	if vlog.Status != domain.VlogStatusStoryboardReady {
+		return nil, nil, errors.MakeConflictError(ctx, "絵コンテの確認待ちのVLogのみ編集できます")
 	}
+	storyboard, err := s.storyboardRepo.FindByVlogID(ctx, vlog.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.MakeNotFoundError(ctx, "絵コンテが見つかりません")
+		}
+		return nil, nil, errors.Wrap(ctx, err)
+	}
+	return vlog, storyboard, nil
+}
+
+// renderTokenReferenceID は動画の生成開始時に仮引きするトークンの参照IDを返す
+func renderTokenReferenceID(vlogID string, version int) string {
+	return fmt.Sprintf("%s:render:%d", vlogID, version)
+}
+
+// abortRender はタスクの登録に失敗した場合にVLogを絵コンテの確認待ちに戻し、動画の生成分として仮引きしたトークンを返却する
+func (s *VLogServer) abortRender(ctx context.Context, vlogID string) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: vlogID}})
+		if err != nil {
+			return err
+		}
+		if err := s.tokenLedger.Rollback(ctx, vlog.ReservationReferenceID()); err != nil {
+			return err
+		}
+		storyboard, err := s.storyboardRepo.FindByVlogID(ctx, vlogID)
+		if err != nil {
+			return err
+		}
+		vlog.Status = domain.VlogStatusStoryboardReady
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			return err
+		}
+		storyboard.ApprovedAt = nil
+		return s.storyboardRepo.Update(ctx, storyboard)
+	})
+}
+
+// Timeline VLog生成の各ステップの実行記録（開始・終了日時、ステータス、再実行回数、エラー）を返す
+func (s *VLogServer) Timeline(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogTimelineRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogの実行記録を取得する権限がありません")
+	}
+	steps, err := s.vlogStepRepo.ListByVlogID(ctx, vlog.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, response.ToVLogTimelineResponse(vlog, steps))
+}
+
+// StreamStatus VLogの生成状況をSSEで配信する
+// 進捗イベントを購読し、再接続時はLast-Event-ID以降のイベントを再送する
+func (s *VLogServer) StreamStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogGetByIDRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	// 購読してから現在の状態を取得し、取得までの間のイベントを取りこぼさないようにする
+	afterID := lastEventID(c)
+	events, err := s.progressBus.Subscribe(ctx, []string{progress.VLogTopic(req.ID)}, afterID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{
+		BaseModel: domain.BaseModel{ID: req.ID},
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogの実行記録を取得する権限がありません")
+	}
+	steps, err := s.vlogStepRepo.ListByVlogID(ctx, vlog.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	startSSE(c)
+
+	// 初回接続時と、再接続時に既に終了している場合は現在の状態を送信する
+	terminal := vlog.Status == domain.VlogStatusCompleted || vlog.Status == domain.VlogStatusFailed || vlog.Status == domain.VlogStatusCancelled || vlog.Status == domain.VlogStatusStoryboardReady
+	if afterID == 0 || terminal {
+		data, _ := json.Marshal(response.ToVLogStreamEvent(vlog, nil, steps))
+		name := ""
+		if vlog.Status == domain.VlogStatusCancelled {
+			name = progress.EventCancelled
+		}
+		if err := writeSSE(c, 0, name, data); err != nil {
+			return nil
+		}
+		if terminal {
+			return nil
+		}
+	}
+
+	return streamSSE(c, events, func(event *domain.ProgressEvent) (bool, error) {
+		if err := writeSSE(c, event.ID, event.Name, []byte(event.Data)); err != nil {
+			return true, nil
+		}
+		// 完了・失敗・キャンセル・絵コンテの確認待ちの時は最後に1回送信して終了
+		return event.Final, nil
+	})
 }
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/request"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler/response"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Draft VLogの下書き（タイトル・スタイル・メディアの並び順・絵コンテ）を返す
+func (s *VLogServer) Draft(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogDraftRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: req.ID}})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if vlog.CreateUserID == nil || *vlog.CreateUserID != Ctx.GetCtxFromUser(ctx) {
+		return errors.MakeForbiddenError(ctx, "このVLogの下書きを取得する権限がありません")
+	}
+	storyboard, err := s.storyboardRepo.FindByVlogID(ctx, vlog.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.MakeNotFoundError(ctx, "下書きが見つかりません")
+		}
+		return errors.Wrap(ctx, err)
+	}
+	input, err := storyboardInput(ctx, storyboard)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return c.JSON(http.StatusOK, draftResponse(vlog, storyboard, input))
+}
+
+// UpdateDraft 下書きのタイトル・説明文・メディアの並び順・スタイルを編集する
+// 使用しないメディアは各シーンの割り当てからも外す
+func (s *VLogServer) UpdateDraft(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogDraftUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	var vlog *domain.Vlog
+	var storyboard *domain.VlogStoryboard
+	var input *agent.VlogInput
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		vlog, storyboard, err = s.reviewingStoryboard(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		input, err = storyboardInput(ctx, storyboard)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+
+		if req.Title != nil {
+			vlog.Title = *req.Title
+		}
+		if req.Description != nil {
+			vlog.Description = *req.Description
+		}
+		if len(req.MediaIDs) > 0 {
+			items, err := reorderMediaItems(ctx, input.MediaItems, req.MediaIDs)
+			if err != nil {
+				return err
+			}
+			input.MediaItems = items
+			for i, scene := range storyboard.Scenes {
+				storyboard.Scenes[i].MediaIDs = slices.DeleteFunc(scene.MediaIDs, func(id string) bool {
+					return !slices.Contains(req.MediaIDs, id)
+				})
+			}
+		}
+		if req.Theme != nil {
+			input.Style.Theme = *req.Theme
+		}
+		if req.MusicMood != nil {
+			input.Style.MusicMood = *req.MusicMood
+		}
+		if req.Transition != nil {
+			input.Style.Transition = *req.Transition
+		}
+		if req.ReferenceMode != nil {
+			input.Style.ReferenceMode = *req.ReferenceMode
+		}
+		if req.SubtitleMode != nil {
+			input.Style.SubtitleMode = *req.SubtitleMode
+		}
+		input.Style.Narration = updateNarrationStyle(input.Style.Narration, &req)
+
+		if err := s.saveDraft(ctx, storyboard, input); err != nil {
+			return err
+		}
+		if err := s.vlogRepo.Update(ctx, vlog); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.MakeConflictError(ctx, "VLogの状態が更新されました。再度お試しください")
+			}
+			return errors.Wrap(ctx, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, draftResponse(vlog, storyboard, input))
+}
+
+// UpdateDraftScene 下書きの1シーンを編集する
+func (s *VLogServer) UpdateDraftScene(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.VLogDraftSceneUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	index, err := strconv.Atoi(req.Index)
+	if err != nil {
+		return errors.MakeInvalidArgumentError(ctx, "シーン番号が正しくありません")
+	}
+
+	var vlog *domain.Vlog
+	var storyboard *domain.VlogStoryboard
+	var input *agent.VlogInput
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		vlog, storyboard, err = s.reviewingStoryboard(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		input, err = storyboardInput(ctx, storyboard)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if index < 1 || index > len(storyboard.Scenes) {
+			return errors.MakeNotFoundError(ctx, "シーンが見つかりません")
+		}
+
+		scene := &storyboard.Scenes[index-1]
+		if req.MediaIDs != nil {
+			if err := validateSceneMediaIDs(ctx, input.MediaItems, req.MediaIDs); err != nil {
+				return err
+			}
+			scene.MediaIDs = req.MediaIDs
+		}
+		if req.CameraDirection != nil {
+			scene.CameraDirection = *req.CameraDirection
+		}
+		if req.VeoPrompt != nil {
+			if strings.TrimSpace(*req.VeoPrompt) == "" {
+				return errors.MakeInvalidArgumentError(ctx, "シーンのプロンプトを入力してください")
+			}
+			scene.VeoPrompt = *req.VeoPrompt
+		}
+		if req.Caption != nil {
+			scene.Caption = *req.Caption
+		}
+		if req.DurationSeconds != nil {
+			scene.DurationSeconds = *req.DurationSeconds
+		}
+		return s.saveDraft(ctx, storyboard, input)
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	return c.JSON(http.StatusOK, draftResponse(vlog, storyboard, input))
+}
+
+// saveDraft は編集した絵コンテとVLog生成の入力を保存する
+func (s *VLogServer) saveDraft(ctx context.Context, storyboard *domain.VlogStoryboard, input *agent.VlogInput) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	storyboard.Input = string(data)
+	if err := s.storyboardRepo.Update(ctx, storyboard); err != nil {
+		if errors.Is(err, errors.ErrOptimisticLock) {
+			return errors.MakeConflictError(ctx, "下書きが更新されました。再度お試しください")
+		}
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// storyboardInput は絵コンテと合わせて保存したVLog生成の入力を返す
+func storyboardInput(ctx context.Context, storyboard *domain.VlogStoryboard) (*agent.VlogInput, error) {
+	var input agent.VlogInput
+	if err := json.Unmarshal([]byte(storyboard.Input), &input); err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &input, nil
+}
+
+// draftResponse は下書きと、下書きの絵コンテで動画を生成する場合に必要なトークン数を返す
+func draftResponse(vlog *domain.Vlog, storyboard *domain.VlogStoryboard, input *agent.VlogInput) response.VLogDraftResponse {
+	render := *input
+	render.Storyboard = toAgentStoryboard(storyboard.Scenes)
+	return response.ToVLogDraftResponse(vlog, storyboard, input, service.EstimateVlogTokenCost(&render).Total)
+}
+
+// reorderMediaItems は指定したメディアIDの順にメディアを並べ替える（指定しなかったメディアは取り除く）
+func reorderMediaItems(ctx context.Context, items []agent.MediaItem, mediaIDs []string) ([]agent.MediaItem, error) {
+	reordered := make([]agent.MediaItem, 0, len(mediaIDs))
+	for _, id := range mediaIDs {
+		i := slices.IndexFunc(items, func(item agent.MediaItem) bool { return item.FileID == id })
+		if i < 0 {
+			return nil, errors.MakeInvalidArgumentError(ctx, "下書きに使用するメディアのみ指定できます")
+		}
+		if slices.ContainsFunc(reordered, func(item agent.MediaItem) bool { return item.FileID == id }) {
+			return nil, errors.MakeInvalidArgumentError(ctx, "同じメディアが複数回指定されています")
+		}
+		item := items[i]
+		item.Order = len(reordered) + 1
+		reordered = append(reordered, item)
+	}
+	return reordered, nil
+}
+
+// updateNarrationStyle はリクエストで指定した項目でナレーションの設定を更新する（ナレーションを付けない場合はnil）
+func updateNarrationStyle(narration *agent.NarrationStyle, req *request.VLogDraftUpdateRequest) *agent.NarrationStyle {
+	if req.Narration != nil {
+		if !*req.Narration {
+			return nil
+		}
+		if narration == nil {
+			narration = &agent.NarrationStyle{SpeakerID: constant.DefaultNarrationSpeakerID}
+		}
+	}
+	if narration == nil {
+		return nil
+	}
+	if req.NarrationSpeaker != nil {
+		narration.SpeakerID = *req.NarrationSpeaker
+	}
+	if req.NarrationSpeed != nil {
+		narration.SpeedScale = *req.NarrationSpeed
+	}
+	return narration
+}
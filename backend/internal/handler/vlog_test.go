@@ -0,0 +1,342 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/handler"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/server"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeVLogRepo struct {
+	domain.IVLogRepository
+	vlogs map[string]*domain.Vlog
+	// beforeUpdate は更新の直前に呼ばれ、読み取りから更新までの間の別リクエストによる更新を再現する
+	beforeUpdate func(vlog *domain.Vlog)
+}
+
+func (r *fakeVLogRepo) GetByID(ctx context.Context, model *domain.Vlog) (*domain.Vlog, error) {
+	vlog, ok := r.vlogs[model.ID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *vlog
+	return &copied, nil
+}
+
+// Update は楽観ロックを再現し、読み取り後に別のリクエストで更新されていた場合はErrOptimisticLockを返す
+func (r *fakeVLogRepo) Update(ctx context.Context, vlog *domain.Vlog) error {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate(vlog)
+	}
+	if stored, ok := r.vlogs[vlog.ID]; ok && stored.Version != vlog.Version {
+		return errors.ErrOptimisticLock
+	}
+	vlog.Version++
+	copied := *vlog
+	r.vlogs[vlog.ID] = &copied
+	return nil
+}
+
+func (r *fakeVLogRepo) ReplaceSubtitles(ctx context.Context, vlog *domain.Vlog) error {
+	r.vlogs[vlog.ID].Subtitles = slices.Clone(vlog.Subtitles)
+	return nil
+}
+
+type fakeVlogStoryboardRepo struct {
+	storyboards map[string]*domain.VlogStoryboard
+}
+
+func (r *fakeVlogStoryboardRepo) FindByVlogID(ctx context.Context, vlogID string) (*domain.VlogStoryboard, error) {
+	storyboard, ok := r.storyboards[vlogID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *storyboard
+	copied.Scenes = slices.Clone(storyboard.Scenes)
+	return &copied, nil
+}
+
+func (r *fakeVlogStoryboardRepo) Create(ctx context.Context, storyboard *domain.VlogStoryboard) error {
+	r.storyboards[storyboard.VlogID] = storyboard
+	return nil
+}
+
+func (r *fakeVlogStoryboardRepo) Update(ctx context.Context, storyboard *domain.VlogStoryboard) error {
+	copied := *storyboard
+	r.storyboards[storyboard.VlogID] = &copied
+	return nil
+}
+
+type fakeTransactionManager struct{}
+
+func (fakeTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeVlogStepRepo struct {
+	domain.IVlogStepRepository
+	steps []*domain.VlogStep
+}
+
+func (r *fakeVlogStepRepo) ListByVlogID(ctx context.Context, vlogID string) ([]*domain.VlogStep, error) {
+	var steps []*domain.VlogStep
+	for _, step := range r.steps {
+		if step.VlogID == vlogID {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+// fakeTokenLedger は確定・取り消しの呼び出し回数をreferenceIDごとに記録する
+type fakeTokenLedger struct {
+	service.ITokenLedger
+	confirms  map[string]int
+	rollbacks map[string]int
+}
+
+func newFakeTokenLedger() *fakeTokenLedger {
+	return &fakeTokenLedger{confirms: map[string]int{}, rollbacks: map[string]int{}}
+}
+
+func (l *fakeTokenLedger) Confirm(ctx context.Context, referenceID string) error {
+	l.confirms[referenceID]++
+	return nil
+}
+
+func (l *fakeTokenLedger) Rollback(ctx context.Context, referenceID string) error {
+	l.rollbacks[referenceID]++
+	return nil
+}
+
+// serveVLogRequest は指定したユーザーとしてVLogのハンドラーを呼び出す
+func serveVLogRequest(h echo.HandlerFunc, method, target, body, userID string, vlogID string) (*httptest.ResponseRecorder, error) {
+	e := echo.New()
+	e.Validator = server.NewValidator()
+	e.Binder = server.NewCustomBinder()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	req = req.WithContext(Ctx.SetCtxFromUser(req.Context(), userID))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(vlogID)
+	return rec, h(c)
+}
+
+func TestVLogServer_GetByID(t *testing.T) {
+	const vlogID = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+	vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+		vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: domain.VlogStatusCompleted, VideoURL: "https://r2.example.com/vlog.mp4"},
+	}}
+	vlogServer := handler.NewVLogServer(vlogRepo, nil, nil, nil, nil, nil, nil, nil)
+
+	t.Run("作成したユーザーは動画のURLを含めて取得できる", func(t *testing.T) {
+		rec, err := serveVLogRequest(vlogServer.GetByID, http.MethodGet, "/api/vlogs/"+vlogID, "", "owner", vlogID)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "https://r2.example.com/vlog.mp4")
+	})
+
+	t.Run("他のユーザーは取得できない", func(t *testing.T) {
+		rec, err := serveVLogRequest(vlogServer.GetByID, http.MethodGet, "/api/vlogs/"+vlogID, "", "other", vlogID)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeForbidden, errors.GetCode(err))
+		assert.NotContains(t, rec.Body.String(), "https://r2.example.com/vlog.mp4")
+	})
+}
+
+func TestVLogServer_Timeline(t *testing.T) {
+	const vlogID = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+	vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+		vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: domain.VlogStatusFailed},
+	}}
+	stepRepo := &fakeVlogStepRepo{steps: []*domain.VlogStep{
+		{VlogID: vlogID, Name: "generate", Status: domain.VlogStepStatusFailed, ErrorMessage: "veo quota exceeded"},
+	}}
+	vlogServer := handler.NewVLogServer(vlogRepo, stepRepo, nil, nil, nil, nil, nil, nil)
+
+	serve := func(userID string) (*httptest.ResponseRecorder, error) {
+		return serveVLogRequest(vlogServer.Timeline, http.MethodGet, "/api/vlogs/"+vlogID+"/timeline", "", userID, vlogID)
+	}
+
+	t.Run("作成したユーザーは実行記録を取得できる", func(t *testing.T) {
+		rec, err := serve("owner")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "veo quota exceeded")
+	})
+
+	t.Run("他のユーザーは実行記録を取得できない", func(t *testing.T) {
+		rec, err := serve("other")
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeForbidden, errors.GetCode(err))
+		assert.NotContains(t, rec.Body.String(), "veo quota exceeded")
+	})
+}
+
+func TestVLogServer_UpdateStoryboard(t *testing.T) {
+	const (
+		vlogID       = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+		ownMediaID   = "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+		otherMediaID = "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"
+	)
+	vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+		vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: domain.VlogStatusStoryboardReady},
+	}}
+	storyboardRepo := &fakeVlogStoryboardRepo{storyboards: map[string]*domain.VlogStoryboard{
+		vlogID: {
+			VlogID: vlogID,
+			Scenes: []domain.StoryboardScene{{MediaIDs: []string{ownMediaID}, VeoPrompt: "海辺を歩く", DurationSeconds: 8}},
+			Input:  `{"mediaItems":[{"fileId":"` + ownMediaID + `","url":"https://r2.example.com/1.jpg","type":"image"}]}`,
+		},
+	}}
+	vlogServer := handler.NewVLogServer(vlogRepo, nil, nil, nil, fakeTransactionManager{}, nil, storyboardRepo, nil)
+
+	serve := func(mediaID string) error {
+		body := `{"scenes":[{"media_ids":["` + mediaID + `"],"veo_prompt":"夕焼けの海","duration_seconds":6}]}`
+		_, err := serveVLogRequest(vlogServer.UpdateStoryboard, http.MethodPut, "/api/vlogs/"+vlogID+"/storyboard", body, "owner", vlogID)
+		return err
+	}
+
+	t.Run("VLogのメディアを割り当てたシーンは保存される", func(t *testing.T) {
+		require.NoError(t, serve(ownMediaID))
+		assert.Equal(t, "夕焼けの海", storyboardRepo.storyboards[vlogID].Scenes[0].VeoPrompt)
+	})
+
+	t.Run("VLogに使用しないメディアは割り当てられない", func(t *testing.T) {
+		err := serve(otherMediaID)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeInValidArgument, errors.GetCode(err))
+		assert.Equal(t, []string{ownMediaID}, storyboardRepo.storyboards[vlogID].Scenes[0].MediaIDs)
+	})
+}
+
+func TestVLogServer_Update(t *testing.T) {
+	const vlogID = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+	setup := func() (*handler.VLogServer, *fakeVLogRepo) {
+		vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+			vlogID: {
+				BaseModel:   domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")},
+				Status:      domain.VlogStatusCompleted,
+				Title:       "京都の旅",
+				Description: "紅葉の京都を巡りました",
+				Subtitles:   []domain.SubtitleSegment{domain.NewSubtitleSegment(1, 0, 2, "清水寺")},
+			},
+		}}
+		return handler.NewVLogServer(vlogRepo, nil, nil, nil, fakeTransactionManager{}, nil, nil, nil), vlogRepo
+	}
+	serve := func(vlogServer *handler.VLogServer, body, userID string) (*httptest.ResponseRecorder, error) {
+		return serveVLogRequest(vlogServer.Update, http.MethodPatch, "/api/vlogs/"+vlogID, body, userID, vlogID)
+	}
+
+	t.Run("タイトル・説明文・字幕を編集できる", func(t *testing.T) {
+		vlogServer, vlogRepo := setup()
+		rec, err := serve(vlogServer, `{"title":"秋の京都","description":"","subtitles":[{"start_time":0,"end_time":3,"text":"金閣寺"},{"start_time":3,"end_time":5,"text":"嵐山"}]}`, "owner")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		saved := vlogRepo.vlogs[vlogID]
+		assert.Equal(t, "秋の京都", saved.Title)
+		assert.Empty(t, saved.Description)
+		require.Len(t, saved.Subtitles, 2)
+		assert.Equal(t, "00:00:03,000", saved.Subtitles[1].Start)
+		assert.Equal(t, "嵐山", saved.Subtitles[1].Text)
+	})
+
+	t.Run("指定しなかった項目は変更しない", func(t *testing.T) {
+		vlogServer, vlogRepo := setup()
+		_, err := serve(vlogServer, `{"title":"秋の京都"}`, "owner")
+		require.NoError(t, err)
+
+		saved := vlogRepo.vlogs[vlogID]
+		assert.Equal(t, "紅葉の京都を巡りました", saved.Description)
+		assert.Len(t, saved.Subtitles, 1)
+	})
+
+	t.Run("タイトルを空にすることはできない", func(t *testing.T) {
+		vlogServer, vlogRepo := setup()
+		_, err := serve(vlogServer, `{"title":""}`, "owner")
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeInValidArgument, errors.GetCode(err))
+		assert.Equal(t, "京都の旅", vlogRepo.vlogs[vlogID].Title)
+	})
+
+	t.Run("他のユーザーは編集できない", func(t *testing.T) {
+		vlogServer, vlogRepo := setup()
+		_, err := serve(vlogServer, `{"title":"秋の京都"}`, "other")
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeForbidden, errors.GetCode(err))
+		assert.Equal(t, "京都の旅", vlogRepo.vlogs[vlogID].Title)
+	})
+}
+
+func TestVLogServer_Cancel(t *testing.T) {
+	const vlogID = "0b9c6a0e-7d7c-4d0e-9a55-0f1f5c1f6a01"
+	tests := []struct {
+		name   string
+		status domain.VlogStatus
+	}{
+		{name: "生成待ち", status: domain.VlogStatusPending},
+		{name: "生成中", status: domain.VlogStatusProcessing},
+		{name: "絵コンテの確認待ち", status: domain.VlogStatusStoryboardReady},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"のVLogをキャンセルすると仮引きを一度だけ返却する", func(t *testing.T) {
+			vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+				vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: tt.status},
+			}}
+			ledger := newFakeTokenLedger()
+			vlogServer := handler.NewVLogServer(vlogRepo, nil, nil, ledger, fakeTransactionManager{}, progress.NewMemoryBus(), nil, nil)
+			cancel := func() (*httptest.ResponseRecorder, error) {
+				return serveVLogRequest(vlogServer.Cancel, http.MethodPost, "/api/vlogs/"+vlogID+"/cancel", "", "owner", vlogID)
+			}
+
+			rec, err := cancel()
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, domain.VlogStatusCancelled, vlogRepo.vlogs[vlogID].Status)
+			assert.NotNil(t, vlogRepo.vlogs[vlogID].CancelledAt)
+
+			// キャンセル済みのVLogは再度キャンセルできず、トークンも返却しない
+			_, err = cancel()
+			require.Error(t, err)
+			assert.Equal(t, errors.ErrCodeConflict, errors.GetCode(err))
+
+			assert.Equal(t, map[string]int{vlogID: 1}, ledger.rollbacks)
+			assert.Empty(t, ledger.confirms)
+		})
+	}
+
+	t.Run("完了したVLogはキャンセルできない", func(t *testing.T) {
+		vlogRepo := &fakeVLogRepo{vlogs: map[string]*domain.Vlog{
+			vlogID: {BaseModel: domain.BaseModel{ID: vlogID, CreateUserID: ptr.StringToPtr("owner")}, Status: domain.VlogStatusCompleted},
+		}}
+		ledger := newFakeTokenLedger()
+		vlogServer := handler.NewVLogServer(vlogRepo, nil, nil, ledger, fakeTransactionManager{}, progress.NewMemoryBus(), nil, nil)
+
+		_, err := serveVLogRequest(vlogServer.Cancel, http.MethodPost, "/api/vlogs/"+vlogID+"/cancel", "", "owner", vlogID)
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeConflict, errors.GetCode(err))
+		assert.Equal(t, domain.VlogStatusCompleted, vlogRepo.vlogs[vlogID].Status)
+		assert.Empty(t, ledger.rollbacks)
+	})
+}
@@ -19,9 +19,10 @@ type CloudTaskClient struct {
 	queue               string
 	serviceAccountEmail string
 	baseURL             string
+	registry            *queue.Registry
 }
 
-func NewClient(ctx context.Context) (*CloudTaskClient, error) {
+func NewClient(ctx context.Context, registry *queue.Registry) (*CloudTaskClient, error) {
 	env := config.GetCtxEnv(ctx)
 	client, err := cloudtasks.NewClient(ctx)
 	if err != nil {
@@ -35,24 +36,20 @@ func NewClient(ctx context.Context) (*CloudTaskClient, error) {
 		queue:               env.CLOUD_TASKS_QUEUE_NAME,
 		serviceAccountEmail: env.SERVICE_ACCOUNT_EMAIL,
 		baseURL:             env.BASE_URL,
+		registry:            registry,
 	}, nil
 }
 
 func (c *CloudTaskClient) Enqueue(ctx context.Context, task *queue.Task) error {
-	payloadBytes, err := json.Marshal(task)
+	// タスクタイプに応じてエンドポイントを決定
+	def, err := c.registry.Lookup(task.Type)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return err
 	}
 
-	// タスクタイプに応じてエンドポイントを決定
-	var endpoint string
-	switch task.Type {
-	case "ProcessVLogTask":
-		endpoint = "/internal/tasks/create-vlog"
-	case "ProcessMediaAnalysisTask":
-		endpoint = "/internal/tasks/analyze-media"
-	default:
-		return fmt.Errorf("unknown task type: %s", task.Type)
+	payloadBytes, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	queuePath := fmt.Sprintf("projects/%s/locations/%s/queues/%s", c.projectID, c.location, c.queue)
@@ -63,7 +60,7 @@ func (c *CloudTaskClient) Enqueue(ctx context.Context, task *queue.Task) error {
 			MessageType: &cloudtaskspb.Task_HttpRequest{
 				HttpRequest: &cloudtaskspb.HttpRequest{
 					HttpMethod: cloudtaskspb.HttpMethod_POST,
-					Url:        fmt.Sprintf("%s%s", c.baseURL, endpoint),
+					Url:        fmt.Sprintf("%s%s", c.baseURL, def.Endpoint()),
 					Body:       payloadBytes,
 					Headers: map[string]string{
 						"Content-Type": "application/json",
@@ -71,6 +68,8 @@ func (c *CloudTaskClient) Enqueue(ctx context.Context, task *queue.Task) error {
 					AuthorizationHeader: &cloudtaskspb.HttpRequest_OidcToken{
 						OidcToken: &cloudtaskspb.OidcToken{
 							ServiceAccountEmail: c.serviceAccountEmail,
+							// 受信側でBASE_URLをaudienceとして検証する
+							Audience: c.baseURL,
 						},
 					},
 				},
@@ -90,3 +89,8 @@ func (c *CloudTaskClient) Enqueue(ctx context.Context, task *queue.Task) error {
 
 	return nil
 }
+
+// Shutdown はCloud Tasksクライアントを閉じる（タスクはCloud Tasks側で保持されるため待機は不要）
+func (c *CloudTaskClient) Shutdown(ctx context.Context) error {
+	return c.client.Close()
+}
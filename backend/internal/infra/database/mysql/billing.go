@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type CheckoutSessionRepository struct{}
+
+// Create - Checkoutセッションを記録
+func (r *CheckoutSessionRepository) Create(ctx context.Context, session *domain.CheckoutSession) error {
+	if err := Ctx.GetDB(ctx).Create(session).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - Checkoutセッションを更新
+func (r *CheckoutSessionRepository) Update(ctx context.Context, session *domain.CheckoutSession) error {
+	if err := Ctx.GetDB(ctx).Updates(session).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByClientRequestID - クライアントリクエストIDで検索
+func (r *CheckoutSessionRepository) FindByClientRequestID(ctx context.Context, userID, clientRequestID string) (*domain.CheckoutSession, error) {
+	var session domain.CheckoutSession
+	if err := Ctx.GetDB(ctx).Where("user_id = ? AND client_request_id = ?", userID, clientRequestID).First(&session).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &session, nil
+}
+
+// FindByStripeSessionID - StripeのCheckout Session IDで検索
+func (r *CheckoutSessionRepository) FindByStripeSessionID(ctx context.Context, stripeSessionID string) (*domain.CheckoutSession, error) {
+	var session domain.CheckoutSession
+	if err := Ctx.GetDB(ctx).Where("stripe_session_id = ?", stripeSessionID).First(&session).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &session, nil
+}
+
+type StripeWebhookEventRepository struct{}
+
+// Create - 処理済みイベントを記録
+func (r *StripeWebhookEventRepository) Create(ctx context.Context, event *domain.StripeWebhookEvent) error {
+	if err := Ctx.GetDB(ctx).Create(event).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Exists - イベントが処理済みかどうか
+func (r *StripeWebhookEventRepository) Exists(ctx context.Context, eventID string) (bool, error) {
+	var count int64
+	if err := Ctx.GetDB(ctx).Model(&domain.StripeWebhookEvent{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+		return false, errors.Wrap(ctx, err)
+	}
+	return count > 0, nil
+}
@@ -36,6 +36,8 @@ func Connect(ctx context.Context) (*gorm.DB, error) {
 				SingularTable: false,
 			},
 			Logger: logger,
+			// UNIQUE制約違反をgorm.ErrDuplicatedKeyとして返す
+			TranslateError: true,
 		})
 		if err != nil {
 			if i == maxRetries-1 {
@@ -60,20 +62,40 @@ func Connect(ctx context.Context) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(100)          // 最大接続数
 	sqlDB.SetConnMaxLifetime(time.Hour) // 接続の最大生存期間
 
+	if err := usePlugins(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// usePlugins はゼロ値のomit・楽観ロック・UUID付与のプラグインを登録する
+func usePlugins(db *gorm.DB) error {
 	// ゼロ値自動omitプラグインを登録（他のプラグインより先に登録）
 	if err := db.Use(database.NewZeroValueOmitPlugin()); err != nil {
-		return nil, fmt.Errorf("failed to register zero value omit plugin: %w", err)
+		return fmt.Errorf("failed to register zero value omit plugin: %w", err)
 	}
 
 	// 楽観ロックプラグインを登録（UUIDプラグインより先に登録）
 	if err := db.Use(database.NewOptimisticLockPlugin()); err != nil {
-		return nil, fmt.Errorf("failed to register optimistic lock plugin: %w", err)
+		return fmt.Errorf("failed to register optimistic lock plugin: %w", err)
 	}
 
 	// UUID自動付与プラグインを登録
 	if err := db.Use(database.NewUUIDPlugin()); err != nil {
-		return nil, fmt.Errorf("failed to register UUID plugin: %w", err)
+		return fmt.Errorf("failed to register UUID plugin: %w", err)
 	}
 
-	return db, nil
+	return nil
+}
+
+// updateColumns は指定したカラムをゼロ値も含めてそのまま書き込む
+// ZeroValueOmitPluginは構造体の更新でゼロ値のカラムを省略し、Select("*")でも上書きできないため、
+// テーブル名とmapで更新してプラグインを通さない（楽観ロックは事前の構造体の更新で確認する）
+func updateColumns(tx *gorm.DB, model interface{}, id string, columns map[string]interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	return tx.Table(stmt.Schema.Table).Where("id = ?", id).Updates(columns).Error
 }
@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestContext は本番と同じプラグインを登録したsqliteのDBを作成し、DBとユーザーIDを設定したコンテキストを返す
+func newTestContext(t *testing.T, models ...interface{}) context.Context {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	require.NoError(t, err)
+	require.NoError(t, usePlugins(db))
+	require.NoError(t, db.AutoMigrate(models...))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	ctx := Ctx.SetCtxFromUser(context.Background(), "user-1")
+	return Ctx.SetDB(ctx, db)
+}
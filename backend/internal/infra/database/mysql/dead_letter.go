@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type DeadLetterTaskRepository struct{}
+
+// Create - デッドレターを作成
+func (r *DeadLetterTaskRepository) Create(ctx context.Context, task *domain.DeadLetterTask) error {
+	if err := Ctx.GetDB(ctx).Create(task).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - デッドレターを更新
+func (r *DeadLetterTaskRepository) Update(ctx context.Context, task *domain.DeadLetterTask) error {
+	if err := Ctx.GetDB(ctx).Updates(task).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByID - IDで検索
+func (r *DeadLetterTaskRepository) FindByID(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	var task domain.DeadLetterTask
+	if err := Ctx.GetDB(ctx).Where("id = ?", id).First(&task).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &task, nil
+}
+
+// FindPendingByTask - タスクの再実行待ちのデッドレターを取得
+func (r *DeadLetterTaskRepository) FindPendingByTask(ctx context.Context, taskType, taskID string) (*domain.DeadLetterTask, error) {
+	var task domain.DeadLetterTask
+	if err := Ctx.GetDB(ctx).
+		Where("task_type = ? AND task_id = ? AND status = ?", taskType, taskID, domain.DeadLetterStatusPending).
+		Order("task_version DESC").
+		First(&task).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &task, nil
+}
+
+// List - デッドレター一覧を取得（新しい順）
+func (r *DeadLetterTaskRepository) List(ctx context.Context, status string, opts *domain.ListOpts) ([]*domain.DeadLetterTask, error) {
+	var tasks []*domain.DeadLetterTask
+	query := filterDeadLetterStatus(Ctx.GetDB(ctx), status).Order("created_at DESC")
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return tasks, nil
+}
+
+// Count - デッドレターの件数を取得
+func (r *DeadLetterTaskRepository) Count(ctx context.Context, status string) (int64, error) {
+	var count int64
+	if err := filterDeadLetterStatus(Ctx.GetDB(ctx).Model(&domain.DeadLetterTask{}), status).Count(&count).Error; err != nil {
+		return 0, errors.Wrap(ctx, err)
+	}
+	return count, nil
+}
+
+func filterDeadLetterStatus(db *gorm.DB, status string) *gorm.DB {
+	if status == "" {
+		return db
+	}
+	return db.Where("status = ?", status)
+}
@@ -0,0 +1,40 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type MusicTrackRepository struct{}
+
+// Create - BGMを登録
+func (r *MusicTrackRepository) Create(ctx context.Context, track *domain.MusicTrack) error {
+	if err := Ctx.GetDB(ctx).Create(track).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByID - BGMをIDで取得
+func (r *MusicTrackRepository) FindByID(ctx context.Context, id string) (*domain.MusicTrack, error) {
+	var track domain.MusicTrack
+	if err := Ctx.GetDB(ctx).Where("id = ?", id).First(&track).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &track, nil
+}
+
+// FindLibraryByMood - 雰囲気が一致するライブラリの曲を取得
+func (r *MusicTrackRepository) FindLibraryByMood(ctx context.Context, mood string) ([]*domain.MusicTrack, error) {
+	var tracks []*domain.MusicTrack
+	if err := Ctx.GetDB(ctx).
+		Where("user_id IS NULL AND mood = ?", mood).
+		Order("created_at ASC").
+		Find(&tracks).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return tracks, nil
+}
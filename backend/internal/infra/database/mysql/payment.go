@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type PaymentRepository struct{}
+
+// Create - 決済を記録
+func (r *PaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	if err := Ctx.GetDB(ctx).Create(payment).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
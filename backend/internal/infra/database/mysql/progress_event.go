@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type ProgressEventRepository struct{}
+
+// Create - 進捗イベントを作成
+func (r *ProgressEventRepository) Create(ctx context.Context, event *domain.ProgressEvent) error {
+	if err := Ctx.GetDB(ctx).Create(event).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// ListAfter - afterIDより後のイベントをID順に取得
+func (r *ProgressEventRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.ProgressEvent, error) {
+	var events []*domain.ProgressEvent
+	if err := Ctx.GetDB(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return events, nil
+}
+
+// ListByTopicsAfter - 指定したトピックのafterIDより後のイベントをID順に取得
+func (r *ProgressEventRepository) ListByTopicsAfter(ctx context.Context, topics []string, afterID int64, limit int) ([]*domain.ProgressEvent, error) {
+	var events []*domain.ProgressEvent
+	if err := Ctx.GetDB(ctx).
+		Where("topic IN ? AND id > ?", topics, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return events, nil
+}
+
+// LatestID - 最新のイベントIDを取得
+func (r *ProgressEventRepository) LatestID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := Ctx.GetDB(ctx).Model(&domain.ProgressEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, errors.Wrap(ctx, err)
+	}
+	return id, nil
+}
+
+// DeleteBefore - 指定日時より前のイベントを削除
+func (r *ProgressEventRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	if err := Ctx.GetDB(ctx).Where("created_at < ?", before).Delete(&domain.ProgressEvent{}).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type SubscriptionRepository struct{}
+
+// Create - サブスクリプションを作成
+func (r *SubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) error {
+	if err := Ctx.GetDB(ctx).Create(subscription).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - サブスクリプションを更新
+func (r *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription) error {
+	if err := Ctx.GetDB(ctx).Updates(subscription).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByStripeSubscriptionID - StripeのサブスクリプションIDで検索
+func (r *SubscriptionRepository) FindByStripeSubscriptionID(ctx context.Context, stripeSubID string) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	if err := Ctx.GetDB(ctx).Where("stripe_subscription_id = ?", stripeSubID).First(&subscription).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &subscription, nil
+}
+
+// FindActiveByUserID - ユーザーの有効なサブスクリプションを取得（複数ある場合は契約期間終了日時が最も遅いもの）
+func (r *SubscriptionRepository) FindActiveByUserID(ctx context.Context, userID string) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	if err := Ctx.GetDB(ctx).
+		Where("user_id = ?", userID).
+		Where("status = ?", domain.SubscriptionStatusActive).
+		Order("current_period_end DESC").
+		First(&subscription).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &subscription, nil
+}
+
+// FindDueForRefill - トークン付与対象のサブスクリプションを取得
+func (r *SubscriptionRepository) FindDueForRefill(ctx context.Context, now time.Time) ([]*domain.Subscription, error) {
+	var subscriptions []*domain.Subscription
+	if err := Ctx.GetDB(ctx).
+		Where("status = ?", domain.SubscriptionStatusActive).
+		Where("current_period_end > ?", now).
+		Where("last_refilled_period_end IS NULL OR last_refilled_period_end <= ?", now).
+		Find(&subscriptions).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return subscriptions, nil
+}
+
+// FindLapsed - 期間が更新されていない有効なサブスクリプションを取得
+func (r *SubscriptionRepository) FindLapsed(ctx context.Context, before time.Time) ([]*domain.Subscription, error) {
+	var subscriptions []*domain.Subscription
+	if err := Ctx.GetDB(ctx).
+		Where("status = ?", domain.SubscriptionStatusActive).
+		Where("current_period_end < ?", before).
+		Find(&subscriptions).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return subscriptions, nil
+}
+
+// FindUserIDsToDowngrade - 解約・期限切れのサブスクリプションのみを持つプレミアムユーザーのIDを取得
+func (r *SubscriptionRepository) FindUserIDsToDowngrade(ctx context.Context) ([]string, error) {
+	var userIDs []string
+	if err := Ctx.GetDB(ctx).
+		Model(&domain.User{}).
+		Where("users.plan = ?", constant.UserPlanPremium).
+		Where("EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = users.id AND s.status IN ? AND s.deleted_at IS NULL)", []string{domain.SubscriptionStatusCancelled, domain.SubscriptionStatusExpired}).
+		Where("NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = users.id AND s.status = ? AND s.deleted_at IS NULL)", domain.SubscriptionStatusActive).
+		Pluck("users.id", &userIDs).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return userIDs, nil
+}
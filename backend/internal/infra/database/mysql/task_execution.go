@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type TaskExecutionRepository struct{}
+
+// Create - タスク実行記録を作成
+func (r *TaskExecutionRepository) Create(ctx context.Context, execution *domain.TaskExecution) error {
+	if err := Ctx.GetDB(ctx).Create(execution).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - タスク実行記録を更新
+func (r *TaskExecutionRepository) Update(ctx context.Context, execution *domain.TaskExecution) error {
+	if err := Ctx.GetDB(ctx).Updates(execution).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByTask - タスクタイプ・ID・バージョンで検索
+func (r *TaskExecutionRepository) FindByTask(ctx context.Context, taskType, taskID string, taskVersion int) (*domain.TaskExecution, error) {
+	var execution domain.TaskExecution
+	if err := Ctx.GetDB(ctx).
+		Where("task_type = ? AND task_id = ? AND task_version = ?", taskType, taskID, taskVersion).
+		First(&execution).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &execution, nil
+}
+
+// FindLatestVersion - タスクIDの最大バージョンを取得
+func (r *TaskExecutionRepository) FindLatestVersion(ctx context.Context, taskType, taskID string) (int, error) {
+	var version int
+	if err := Ctx.GetDB(ctx).
+		Model(&domain.TaskExecution{}).
+		Where("task_type = ? AND task_id = ?", taskType, taskID).
+		Select("COALESCE(MAX(task_version), 0)").
+		Scan(&version).Error; err != nil {
+		return 0, errors.Wrap(ctx, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staleTokenTransactionRepo は並行して記録された取引がまだ見えていない読み取りを再現する
+type staleTokenTransactionRepo struct {
+	*TokenTransactionRepository
+	hidden []string // 読み取り結果から除く取引の種別
+}
+
+func (r *staleTokenTransactionRepo) FindByReferenceID(ctx context.Context, referenceID string) ([]*domain.TokenTransaction, error) {
+	transactions, err := r.TokenTransactionRepository.FindByReferenceID(ctx, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(transactions, func(tx *domain.TokenTransaction) bool {
+		return slices.Contains(r.hidden, tx.Type)
+	}), nil
+}
+
+func TestTokenLedger_Concurrency(t *testing.T) {
+	ctx := newTestContext(t, &domain.User{}, &domain.TokenTransaction{})
+	require.NoError(t, Ctx.GetDB(ctx).Exec("CREATE UNIQUE INDEX uc_token_transactions_reference ON token_transactions (reference_id, type)").Error)
+
+	userRepo := &UserRepository{}
+	txRepo := &TokenTransactionRepository{}
+	txManager := NewTransactionManager()
+	ledger := service.NewTokenLedger(userRepo, txRepo, txManager)
+
+	user := &domain.User{Name: "traveler", TokenBalance: sql.NullInt64{Int64: 100, Valid: true}}
+	require.NoError(t, userRepo.Create(ctx, user))
+	balance := func(t *testing.T) int64 {
+		saved, err := userRepo.FindByID(ctx, &domain.User{BaseModel: domain.BaseModel{ID: user.ID}})
+		require.NoError(t, err)
+		return saved.TokenBalance.Int64
+	}
+	countByType := func(t *testing.T, referenceID, txType string) int {
+		transactions, err := txRepo.FindByReferenceID(ctx, referenceID)
+		require.NoError(t, err)
+		return len(slices.DeleteFunc(transactions, func(tx *domain.TokenTransaction) bool { return tx.Type != txType }))
+	}
+
+	t.Run("残高不足の場合はErrInsufficientTokensを返し、残高を変更しない", func(t *testing.T) {
+		_, err := userRepo.AddTokenBalance(ctx, user.ID, -101)
+		assert.ErrorIs(t, err, errors.ErrInsufficientTokens)
+		assert.EqualValues(t, 100, balance(t))
+	})
+
+	t.Run("並行した仮引きがUNIQUE制約で弾かれた場合は残高を戻して処理済みとする", func(t *testing.T) {
+		require.NoError(t, ledger.Reserve(ctx, user.ID, "vlog-1", 30, "動画生成"))
+
+		// 仮引きの記録が見えていない別のリクエスト
+		stale := service.NewTokenLedger(userRepo, &staleTokenTransactionRepo{TokenTransactionRepository: txRepo, hidden: []string{domain.TokenTransactionTypeReserve}}, txManager)
+		require.NoError(t, stale.Reserve(ctx, user.ID, "vlog-1", 30, "動画生成"))
+
+		assert.EqualValues(t, 70, balance(t))
+		assert.Equal(t, 1, countByType(t, "vlog-1", domain.TokenTransactionTypeReserve))
+	})
+
+	t.Run("並行した取り消しがUNIQUE制約で弾かれた場合は二重に返却しない", func(t *testing.T) {
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+
+		// 取り消しの記録が見えていない別のリクエスト
+		stale := service.NewTokenLedger(userRepo, &staleTokenTransactionRepo{TokenTransactionRepository: txRepo, hidden: []string{domain.TokenTransactionTypeRollback}}, txManager)
+		require.NoError(t, stale.Rollback(ctx, "vlog-1"))
+
+		assert.EqualValues(t, 100, balance(t))
+		assert.Equal(t, 1, countByType(t, "vlog-1", domain.TokenTransactionTypeRollback))
+	})
+}
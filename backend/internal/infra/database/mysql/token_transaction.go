@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type TokenTransactionRepository struct{}
+
+// Create - トークン取引を記録
+func (r *TokenTransactionRepository) Create(ctx context.Context, transaction *domain.TokenTransaction) error {
+	if err := Ctx.GetDB(ctx).Create(transaction).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - トークン取引を更新
+func (r *TokenTransactionRepository) Update(ctx context.Context, transaction *domain.TokenTransaction) error {
+	if err := Ctx.GetDB(ctx).Updates(transaction).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByReferenceID - べき等キーに紐づくトークン取引を取得（作成順）
+func (r *TokenTransactionRepository) FindByReferenceID(ctx context.Context, referenceID string) ([]*domain.TokenTransaction, error) {
+	var transactions []*domain.TokenTransaction
+	if err := Ctx.GetDB(ctx).
+		Where("reference_id = ?", referenceID).
+		Order("created_at ASC").
+		Find(&transactions).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return transactions, nil
+}
+
+// FindByUserID - ユーザーのトークン取引履歴を取得（新しい順）
+func (r *TokenTransactionRepository) FindByUserID(ctx context.Context, userID string, opts *domain.ListOpts) ([]*domain.TokenTransaction, error) {
+	var transactions []*domain.TokenTransaction
+	query := Ctx.GetDB(ctx).Where("user_id = ?", userID).Order("created_at DESC")
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return transactions, nil
+}
+
+// CountByUserID - ユーザーのトークン取引件数を取得
+func (r *TokenTransactionRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	if err := Ctx.GetDB(ctx).Model(&domain.TokenTransaction{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, errors.Wrap(ctx, err)
+	}
+	return count, nil
+}
@@ -89,3 +89,29 @@ func (r *UserRepository) Delete(ctx context.Context, cond *domain.User) error {
 	}
 	return nil
 }
+
+// AddTokenBalance トークン残高を加減算し、更新後の残高を返す
+// 楽観ロックを介さずに残高を原子的に更新するため、UPDATE文を直接発行する
+func (r *UserRepository) AddTokenBalance(ctx context.Context, id string, delta int) (int, error) {
+	db := Ctx.GetDB(ctx)
+	result := db.Exec(
+		"UPDATE users SET token_balance = COALESCE(token_balance, 0) + ? WHERE id = ? AND deleted_at IS NULL AND COALESCE(token_balance, 0) + ? >= 0",
+		delta, id, delta,
+	)
+	if result.Error != nil {
+		return 0, errors.Wrap(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// ユーザーが存在しないのか残高不足なのかを判別する
+		if _, err := r.FindByID(ctx, &domain.User{BaseModel: domain.BaseModel{ID: id}}); err != nil {
+			return 0, err
+		}
+		return 0, errors.ErrInsufficientTokens
+	}
+
+	var balance int
+	if err := db.Model(&domain.User{}).Select("COALESCE(token_balance, 0)").Where("id = ?", id).Scan(&balance).Error; err != nil {
+		return 0, errors.Wrap(ctx, err)
+	}
+	return balance, nil
+}
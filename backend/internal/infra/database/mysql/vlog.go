@@ -5,6 +5,8 @@ import (
 
 	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
 	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VLogRepository struct{}
@@ -20,7 +22,12 @@ func (r *VLogRepository) List(ctx context.Context, opts *domain.ListOptions) ([]
 
 func (r *VLogRepository) GetByID(ctx context.Context, model *domain.Vlog) (*domain.Vlog, error) {
 	var vlog *domain.Vlog
-	if err := Ctx.GetDB(ctx).Where("id = ?", model.ID).First(&vlog).Error; err != nil {
+	if err := Ctx.GetDB(ctx).
+		Preload("Subtitles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("`index`")
+		}).
+		Where("id = ?", model.ID).
+		First(&vlog).Error; err != nil {
 		return nil, err
 	}
 	return vlog, nil
@@ -33,22 +40,58 @@ func (r *VLogRepository) Delete(ctx context.Context, model *domain.Vlog) error {
 	return nil
 }
 
+// Create はVLogを作成する（字幕はReplaceSubtitlesで保存する）
 func (r *VLogRepository) Create(ctx context.Context, vlog *domain.Vlog) error {
-	if err := Ctx.GetDB(ctx).Create(vlog).Error; err != nil {
+	if err := Ctx.GetDB(ctx).Omit(clause.Associations).Create(vlog).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// Update はVLogを更新する（字幕はReplaceSubtitlesで保存する）
+// 構造体の更新ではゼロ値のカラムが省略されるため、クリアしうるカラムはupdateColumnsで書き込む
 func (r *VLogRepository) Update(ctx context.Context, vlog *domain.Vlog) error {
-	if err := Ctx.GetDB(ctx).Save(vlog).Error; err != nil {
+	return Ctx.GetDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(vlog).Error; err != nil {
+			return err
+		}
+		return updateColumns(tx, &domain.Vlog{}, vlog.ID, vlogClearableColumns(vlog))
+	})
+}
+
+// vlogClearableColumns は説明文の削除や再実行、共有リンクの無効化などでゼロ値に戻すことがあるカラムを返す
+func vlogClearableColumns(vlog *domain.Vlog) map[string]interface{} {
+	return map[string]interface{}{
+		"description":   vlog.Description,
+		"share_url":     vlog.ShareURL,
+		"error_message": vlog.ErrorMessage,
+		"progress":      vlog.Progress,
+		"started_at":    vlog.StartedAt,
+		"completed_at":  vlog.CompletedAt,
+	}
+}
+
+func (r *VLogRepository) UpdateStatus(ctx context.Context, vlog *domain.Vlog) error {
+	if err := Ctx.GetDB(ctx).Omit(clause.Associations).Updates(vlog).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *VLogRepository) UpdateStatus(ctx context.Context, vlog *domain.Vlog) error {
-	if err := Ctx.GetDB(ctx).Updates(vlog).Error; err != nil {
+func (r *VLogRepository) ReplaceSubtitles(ctx context.Context, vlog *domain.Vlog) error {
+	db := Ctx.GetDB(ctx)
+	if err := db.Where("vlog_id = ?", vlog.ID).Delete(&domain.SubtitleSegment{}).Error; err != nil {
+		return err
+	}
+	if len(vlog.Subtitles) == 0 {
+		return nil
+	}
+	// 論理削除した字幕とIDが重複しないよう、新しいIDで作成する
+	for i := range vlog.Subtitles {
+		vlog.Subtitles[i].ID = ""
+		vlog.Subtitles[i].VlogID = vlog.ID
+	}
+	if err := db.Create(&vlog.Subtitles).Error; err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+type VlogShareRepository struct{}
+
+// Create - 共有リンクを作成
+func (r *VlogShareRepository) Create(ctx context.Context, share *domain.VlogShare) error {
+	if err := Ctx.GetDB(ctx).Create(share).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// FindByCode - 無効化されていない共有リンクを共有コードで取得
+func (r *VlogShareRepository) FindByCode(ctx context.Context, code string) (*domain.VlogShare, error) {
+	var share domain.VlogShare
+	if err := Ctx.GetDB(ctx).
+		Where("code = ? AND revoked_at IS NULL", code).
+		First(&share).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &share, nil
+}
+
+// FindActiveByVlogID - VLogの無効化されていない共有リンクを取得
+func (r *VlogShareRepository) FindActiveByVlogID(ctx context.Context, vlogID string) (*domain.VlogShare, error) {
+	var share domain.VlogShare
+	if err := Ctx.GetDB(ctx).
+		Where("vlog_id = ? AND revoked_at IS NULL", vlogID).
+		Order("created_at DESC").
+		First(&share).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &share, nil
+}
+
+// RevokeByVlogID - VLogの共有リンクをすべて無効化
+// 対象が複数件・0件の場合があり楽観ロックを介せないため、UPDATE文を直接発行する
+func (r *VlogShareRepository) RevokeByVlogID(ctx context.Context, vlogID string, revokedAt time.Time) error {
+	if err := Ctx.GetDB(ctx).Exec(
+		"UPDATE vlog_shares SET revoked_at = ?, updated_at = ? WHERE vlog_id = ? AND revoked_at IS NULL AND deleted_at IS NULL",
+		revokedAt, revokedAt, vlogID,
+	).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// IncrementViewCount - 閲覧数を加算（同時アクセスで取りこぼさないようSQLで加算する）
+func (r *VlogShareRepository) IncrementViewCount(ctx context.Context, id string) error {
+	if err := Ctx.GetDB(ctx).Exec(
+		"UPDATE vlog_shares SET view_count = view_count + 1 WHERE id = ? AND deleted_at IS NULL",
+		id,
+	).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
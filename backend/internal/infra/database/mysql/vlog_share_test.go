@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVlogShareRepository(t *testing.T) {
+	ctx := newTestContext(t, &domain.Vlog{}, &domain.SubtitleSegment{}, &domain.VlogShare{})
+	vlogRepo := &VLogRepository{}
+	shareRepo := &VlogShareRepository{}
+	svc := service.NewVlogShareService(shareRepo, vlogRepo, NewTransactionManager(), "https://tavinikkiy.example.com")
+
+	vlog := &domain.Vlog{Status: domain.VlogStatusCompleted, VideoURL: "https://r2.example.com/vlog.mp4"}
+	require.NoError(t, vlogRepo.Create(ctx, vlog))
+
+	t.Run("共有リンクを発行すると共有URLが保存される", func(t *testing.T) {
+		_, err := svc.Issue(ctx, vlog, service.ShareOptions{Code: "code1"})
+		require.NoError(t, err)
+
+		saved, err := vlogRepo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Equal(t, "https://tavinikkiy.example.com/share/code1", saved.ShareURL)
+	})
+
+	t.Run("閲覧数を加算できる", func(t *testing.T) {
+		share, err := shareRepo.FindByCode(ctx, "code1")
+		require.NoError(t, err)
+		require.NoError(t, shareRepo.IncrementViewCount(ctx, share.ID))
+		require.NoError(t, shareRepo.IncrementViewCount(ctx, share.ID))
+
+		share, err = shareRepo.FindByCode(ctx, "code1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, share.ViewCount)
+	})
+
+	t.Run("再発行すると以前の共有コードは無効になる", func(t *testing.T) {
+		_, err := svc.Issue(ctx, vlog, service.ShareOptions{Code: "code2"})
+		require.NoError(t, err)
+
+		_, err = shareRepo.FindByCode(ctx, "code1")
+		assert.Error(t, err)
+		current, err := shareRepo.FindActiveByVlogID(ctx, vlog.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "code2", current.Code)
+	})
+
+	t.Run("無効にすると保存された共有URLもクリアされる", func(t *testing.T) {
+		require.NoError(t, svc.Revoke(ctx, vlog))
+
+		saved, err := vlogRepo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Empty(t, saved.ShareURL)
+		_, err = shareRepo.FindActiveByVlogID(ctx, vlog.ID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type VlogStepRepository struct{}
+
+// Create - VLog生成ステップを作成
+func (r *VlogStepRepository) Create(ctx context.Context, step *domain.VlogStep) error {
+	if err := Ctx.GetDB(ctx).Create(step).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - VLog生成ステップを更新（再実行時の終了日時・エラーのクリアはupdateColumnsで書き込む）
+func (r *VlogStepRepository) Update(ctx context.Context, step *domain.VlogStep) error {
+	err := Ctx.GetDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(step).Error; err != nil {
+			return err
+		}
+		return updateColumns(tx, &domain.VlogStep{}, step.ID, map[string]interface{}{
+			"ended_at":      step.EndedAt,
+			"error_message": step.ErrorMessage,
+		})
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// ListByVlogID - VLogのステップを開始日時順に取得
+func (r *VlogStepRepository) ListByVlogID(ctx context.Context, vlogID string) ([]*domain.VlogStep, error) {
+	var steps []*domain.VlogStep
+	if err := Ctx.GetDB(ctx).
+		Where("vlog_id = ?", vlogID).
+		Order("started_at ASC").
+		Order("item_index ASC").
+		Find(&steps).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return steps, nil
+}
@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVlogStepRepository_Update(t *testing.T) {
+	ctx := newTestContext(t, &domain.VlogStep{})
+	repo := &VlogStepRepository{}
+
+	startedAt := time.Now().Add(-time.Minute)
+	endedAt := time.Now()
+	step := &domain.VlogStep{
+		VlogID:       "vlog-1",
+		Name:         "generate_video",
+		Status:       domain.VlogStepStatusFailed,
+		StartedAt:    startedAt,
+		EndedAt:      &endedAt,
+		ErrorMessage: "veo quota exceeded",
+	}
+	require.NoError(t, repo.Create(ctx, step))
+
+	// 再実行でステップを開始すると、前回の終了日時とエラーがクリアされる
+	step.Status = domain.VlogStepStatusRunning
+	step.StartedAt = time.Now()
+	step.EndedAt = nil
+	step.ErrorMessage = ""
+	step.RetryCount++
+	require.NoError(t, repo.Update(ctx, step))
+
+	steps, err := repo.ListByVlogID(ctx, "vlog-1")
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, domain.VlogStepStatusRunning, steps[0].Status)
+	assert.Nil(t, steps[0].EndedAt)
+	assert.Empty(t, steps[0].ErrorMessage)
+	assert.Equal(t, 1, steps[0].RetryCount)
+
+	// 完了時は前回のエラーが残らない
+	finishedAt := time.Now()
+	step.Status = domain.VlogStepStatusCompleted
+	step.EndedAt = &finishedAt
+	require.NoError(t, repo.Update(ctx, step))
+
+	steps, err = repo.ListByVlogID(ctx, "vlog-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VlogStepStatusCompleted, steps[0].Status)
+	assert.NotNil(t, steps[0].EndedAt)
+	assert.Empty(t, steps[0].ErrorMessage)
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type VlogStoryboardRepository struct{}
+
+// FindByVlogID - VLogの絵コンテを取得
+func (r *VlogStoryboardRepository) FindByVlogID(ctx context.Context, vlogID string) (*domain.VlogStoryboard, error) {
+	var storyboard domain.VlogStoryboard
+	if err := Ctx.GetDB(ctx).Where("vlog_id = ?", vlogID).First(&storyboard).Error; err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return &storyboard, nil
+}
+
+// Create - 絵コンテを作成
+func (r *VlogStoryboardRepository) Create(ctx context.Context, storyboard *domain.VlogStoryboard) error {
+	if err := Ctx.GetDB(ctx).Create(storyboard).Error; err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Update - 絵コンテを更新（生成の登録に失敗した場合の確認日時のクリアはupdateColumnsで書き込む）
+func (r *VlogStoryboardRepository) Update(ctx context.Context, storyboard *domain.VlogStoryboard) error {
+	err := Ctx.GetDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(storyboard).Error; err != nil {
+			return err
+		}
+		return updateColumns(tx, &domain.VlogStoryboard{}, storyboard.ID, map[string]interface{}{
+			"approved_at": storyboard.ApprovedAt,
+		})
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVlogStoryboardRepository_Update(t *testing.T) {
+	ctx := newTestContext(t, &domain.VlogStoryboard{})
+	repo := &VlogStoryboardRepository{}
+
+	storyboard := &domain.VlogStoryboard{
+		VlogID: "vlog-1",
+		Scenes: []domain.StoryboardScene{{MediaIDs: []string{"media-1"}, VeoPrompt: "海辺を歩く", DurationSeconds: 8}},
+		Input:  "{}",
+	}
+	require.NoError(t, repo.Create(ctx, storyboard))
+
+	approvedAt := time.Now()
+	storyboard.ApprovedAt = &approvedAt
+	require.NoError(t, repo.Update(ctx, storyboard))
+	saved, err := repo.FindByVlogID(ctx, "vlog-1")
+	require.NoError(t, err)
+	require.NotNil(t, saved.ApprovedAt)
+
+	// 動画の生成の登録に失敗した場合は確認待ちに戻す
+	saved.ApprovedAt = nil
+	require.NoError(t, repo.Update(ctx, saved))
+	saved, err = repo.FindByVlogID(ctx, "vlog-1")
+	require.NoError(t, err)
+	assert.Nil(t, saved.ApprovedAt)
+	assert.Equal(t, "海辺を歩く", saved.Scenes[0].VeoPrompt)
+}
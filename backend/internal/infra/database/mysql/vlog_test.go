@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVLogRepository_Update(t *testing.T) {
+	ctx := newTestContext(t, &domain.Vlog{}, &domain.SubtitleSegment{})
+	repo := &VLogRepository{}
+
+	t.Run("再実行時にクリアしたエラー・進捗・日時が保存される", func(t *testing.T) {
+		startedAt := time.Now().Add(-time.Hour)
+		vlog := &domain.Vlog{
+			Status:       domain.VlogStatusFailed,
+			ErrorMessage: "veo quota exceeded",
+			Progress:     0.4,
+			StartedAt:    &startedAt,
+			CompletedAt:  &startedAt,
+		}
+		require.NoError(t, repo.Create(ctx, vlog))
+
+		loaded, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		loaded.Status = domain.VlogStatusPending
+		loaded.ErrorMessage = ""
+		loaded.Progress = 0
+		loaded.StartedAt = nil
+		loaded.CompletedAt = nil
+		require.NoError(t, repo.Update(ctx, loaded))
+
+		saved, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Equal(t, domain.VlogStatusPending, saved.Status)
+		assert.Empty(t, saved.ErrorMessage)
+		assert.Zero(t, saved.Progress)
+		assert.Nil(t, saved.StartedAt)
+		assert.Nil(t, saved.CompletedAt)
+		assert.Equal(t, loaded.Version, saved.Version)
+	})
+
+	t.Run("古いバージョンでの更新は楽観ロックエラーになり、クリアも反映されない", func(t *testing.T) {
+		vlog := &domain.Vlog{Status: domain.VlogStatusProcessing, ErrorMessage: "attempt 1 failed"}
+		require.NoError(t, repo.Create(ctx, vlog))
+
+		stale, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		latest, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		latest.Status = domain.VlogStatusCancelled
+		require.NoError(t, repo.Update(ctx, latest))
+
+		stale.ErrorMessage = ""
+		assert.Error(t, repo.Update(ctx, stale))
+
+		saved, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Equal(t, domain.VlogStatusCancelled, saved.Status)
+		assert.Equal(t, "attempt 1 failed", saved.ErrorMessage)
+	})
+}
+
+func TestVLogRepository_EditContents(t *testing.T) {
+	ctx := newTestContext(t, &domain.Vlog{}, &domain.SubtitleSegment{})
+	repo := &VLogRepository{}
+
+	vlog := &domain.Vlog{Status: domain.VlogStatusCompleted, Title: "京都の旅", Description: "紅葉の京都を巡りました"}
+	require.NoError(t, repo.Create(ctx, vlog))
+	vlog.Subtitles = []domain.SubtitleSegment{
+		domain.NewSubtitleSegment(1, 0, 2, "清水寺"),
+		domain.NewSubtitleSegment(2, 2, 4, "金閣寺"),
+	}
+	require.NoError(t, repo.ReplaceSubtitles(ctx, vlog))
+
+	t.Run("説明文を空にした編集が保存される", func(t *testing.T) {
+		loaded, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		loaded.Title = "秋の京都"
+		loaded.Description = ""
+		require.NoError(t, repo.Update(ctx, loaded))
+
+		saved, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Equal(t, "秋の京都", saved.Title)
+		assert.Empty(t, saved.Description)
+	})
+
+	t.Run("字幕を置き換えると表示順に取得できる", func(t *testing.T) {
+		vlog.Subtitles = []domain.SubtitleSegment{
+			domain.NewSubtitleSegment(1, 0, 3, "嵐山"),
+			domain.NewSubtitleSegment(2, 3, 5, "渡月橋"),
+			domain.NewSubtitleSegment(3, 5, 8, "竹林の小径"),
+		}
+		require.NoError(t, repo.ReplaceSubtitles(ctx, vlog))
+
+		saved, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		require.Len(t, saved.Subtitles, 3)
+		for i, text := range []string{"嵐山", "渡月橋", "竹林の小径"} {
+			assert.Equal(t, i+1, saved.Subtitles[i].Index)
+			assert.Equal(t, text, saved.Subtitles[i].Text)
+			assert.Equal(t, vlog.ID, saved.Subtitles[i].VlogID)
+		}
+	})
+
+	t.Run("空の字幕で置き換えると字幕を削除する", func(t *testing.T) {
+		vlog.Subtitles = nil
+		require.NoError(t, repo.ReplaceSubtitles(ctx, vlog))
+
+		saved, err := repo.GetByID(ctx, vlog)
+		require.NoError(t, err)
+		assert.Empty(t, saved.Subtitles)
+	})
+}
@@ -0,0 +1,53 @@
+package fakevideo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+)
+
+// シーン番号ごとのクリップの色
+var palette = []string{"red", "orange", "yellow", "green", "blue", "indigo", "violet"}
+
+// Generator はシーン番号ごとに決まった色の単色クリップを生成するagent.IVideoGeneratorの実装
+// ネットワークに接続せずにVLog生成フローを実行するために使用する（ffmpegが必要）
+type Generator struct {
+	tools video.Tools
+}
+
+// New は新しいGeneratorを作成する
+func New(tools video.Tools) *Generator {
+	return &Generator{tools: tools}
+}
+
+// GenerateClip はシーンの長さ・縦横比に合わせた単色のクリップを生成する
+func (g *Generator) GenerateClip(ctx context.Context, req agent.VideoClipRequest, onProgress func(fraction float64)) (*agent.VideoClip, error) {
+	duration := req.DurationSeconds
+	if duration <= 0 {
+		duration = constant.VeoClipMaxSeconds
+	}
+	width, height := 640, 360
+	if req.AspectRatio == "9:16" {
+		width, height = height, width
+	}
+
+	color := palette[max(req.SceneIndex-1, 0)%len(palette)]
+	data, err := g.tools.RenderSolidClip(ctx, color, time.Duration(duration)*time.Second, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fake clip: %w", err)
+	}
+	onProgress(1)
+
+	return &agent.VideoClip{
+		SceneIndex:      req.SceneIndex,
+		Data:            data,
+		DurationSeconds: float64(duration),
+	}, nil
+}
+
+// ReleaseClip は一時ファイルを作成しないため何もしない
+func (g *Generator) ReleaseClip(ctx context.Context, clip *agent.VideoClip) {}
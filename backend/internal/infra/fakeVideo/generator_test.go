@@ -0,0 +1,50 @@
+package fakevideo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateClip(t *testing.T) {
+	tools := video.DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+	gen := New(tools)
+
+	t.Run("シーンの長さ・縦横比のクリップを生成する", func(t *testing.T) {
+		var progress float64
+		clip, err := gen.GenerateClip(ctx, agent.VideoClipRequest{SceneIndex: 2, DurationSeconds: 4, AspectRatio: "9:16"}, func(fraction float64) {
+			progress = fraction
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, clip.SceneIndex)
+		assert.InDelta(t, 4, clip.DurationSeconds, 0.001)
+		assert.InDelta(t, 1, progress, 0.001)
+
+		path := filepath.Join(t.TempDir(), "clip.mp4")
+		require.NoError(t, os.WriteFile(path, clip.Data, 0o600))
+		info, err := tools.Probe(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, 360, info.Width)
+		assert.Equal(t, 640, info.Height)
+		assert.InDelta(t, 4, info.Duration, 0.2)
+	})
+
+	t.Run("同じリクエストからは同じクリップを生成する", func(t *testing.T) {
+		req := agent.VideoClipRequest{SceneIndex: 1, DurationSeconds: 4}
+		first, err := gen.GenerateClip(ctx, req, func(float64) {})
+		require.NoError(t, err)
+		second, err := gen.GenerateClip(ctx, req, func(float64) {})
+		require.NoError(t, err)
+		assert.Equal(t, first.Data, second.Data)
+	})
+}
@@ -5,10 +5,8 @@ import (
 	"fmt"
 	"sync"
 
-	"cloud.google.com/go/storage"
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
-	"google.golang.org/genai"
 
 	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
@@ -72,17 +70,40 @@ func WithAgentVlogRepository(repo domain.IVLogRepository) GenkitAgentOption {
 	}
 }
 
-// WithAgentGCSClient はGCSClientを設定するオプション
-func WithAgentGCSClient(client *storage.Client) GenkitAgentOption {
+// WithAgentMusicTrackRepository はMusicTrackRepositoryを設定するオプション
+func WithAgentMusicTrackRepository(repo domain.IMusicTrackRepository) GenkitAgentOption {
 	return func(ga *GenkitAgent) {
-		ga.flowContext.GCSClient = client
+		ga.flowContext.MusicTrackRepo = repo
 	}
 }
 
-// WithAgentGenAIClient はGenAIClientを設定するオプション
-func WithAgentGenAIClient(client *genai.Client) GenkitAgentOption {
+// WithAgentVideoGenerator はVideoGeneratorを設定するオプション
+func WithAgentVideoGenerator(generator agent.IVideoGenerator) GenkitAgentOption {
 	return func(ga *GenkitAgent) {
-		ga.flowContext.GenAI = client
+		ga.flowContext.VideoGenerator = generator
+	}
+}
+
+// WithAgentSpeechSynthesizer はナレーションの音声合成を設定するオプション
+func WithAgentSpeechSynthesizer(synthesizer agent.ISpeechSynthesizer) GenkitAgentOption {
+	return func(ga *GenkitAgent) {
+		ga.flowContext.SpeechSynthesizer = synthesizer
+	}
+}
+
+// WithAgentThumbnailPreview はサムネイルと合わせて作成するアニメーションプレビューの形式（gif/webp）を設定するオプション
+func WithAgentThumbnailPreview(format string) GenkitAgentOption {
+	return func(ga *GenkitAgent) {
+		ga.flowContext.Config.ThumbnailPreviewFormat = format
+	}
+}
+
+// WithAgentSubtitleFont は字幕を動画に焼き込むときのフォントを設定するオプション（空の場合はデフォルトのフォント）
+func WithAgentSubtitleFont(name string) GenkitAgentOption {
+	return func(ga *GenkitAgent) {
+		if name != "" {
+			ga.flowContext.Config.SubtitleFontName = name
+		}
 	}
 }
 
@@ -135,7 +156,11 @@ func (ga *GenkitAgent) CreateVlog(ctx context.Context, input *agent.VlogInput) (
 // CreateVlogWithProgress はメディアからVLogを生成し、進捗をコールバックで通知する
 func (ga *GenkitAgent) CreateVlogWithProgress(ctx context.Context, input *agent.VlogInput, onProgress func(agent.FlowProgress)) (*agent.VlogOutput, error) {
 	// FlowContextをコンテキストに設定
+	// フローの各ステップの進捗はコンテキスト経由でコールバックに通知される
 	ctx = WithFlowContext(ctx, ga.flowContext)
+	if onProgress != nil {
+		ctx = agent.WithProgressCallback(ctx, onProgress)
+	}
 
 	// 初期化中を通知
 	if onProgress != nil {
@@ -146,16 +171,6 @@ func (ga *GenkitAgent) CreateVlogWithProgress(ctx context.Context, input *agent.
 		})
 	}
 
-	// 分析中を通知
-	if onProgress != nil {
-		onProgress(agent.FlowProgress{
-			Step:       string(agent.StepAnalyzing),
-			Progress:   10,
-			Message:    "メディアを分析しています...",
-			TotalItems: len(input.MediaItems),
-		})
-	}
-
 	// フローを実行
 	output, err := ga.vlogFlow.Run(ctx, input)
 	if err != nil {
@@ -3,10 +3,9 @@ package genkit
 import (
 	"context"
 
-	"cloud.google.com/go/storage"
 	"github.com/firebase/genkit/go/genkit"
-	"google.golang.org/genai"
 
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
 )
@@ -19,11 +18,12 @@ import (
 type FlowContext struct {
 	Genkit    *genkit.Genkit
 	Storage   domain.IImageStorage
-	GCSClient *storage.Client // GCSクライアント（Veo一時保存用）
-	GenAI     *genai.Client   // Google Gen AIクライアント（Veo用）
+	VideoGenerator     agent.IVideoGenerator // 動画生成バックエンド（Veo・テスト用の単色クリップ）
+	SpeechSynthesizer  agent.ISpeechSynthesizer // ナレーションの音声合成（未設定の場合はナレーションを付けない）
 	MediaRepo          domain.IMediaRepository
 	MediaAnalyticsRepo domain.IMediaAnalyticsRepository
 	VlogRepo           domain.IVLogRepository
+	MusicTrackRepo     domain.IMusicTrackRepository // BGMの曲（未設定の場合はBGMを付けない）
 	Config             *FlowConfig
 }
 
@@ -34,12 +34,27 @@ type FlowConfig struct {
 	DefaultVideoDuration int
 	ThumbnailWidth       int
 	ThumbnailHeight      int
+	// アニメーションプレビュー設定
+	ThumbnailPreviewFormat  string  // プレビューの形式（gif/webp、空の場合は作成しない）
+	ThumbnailPreviewWidth   int     // プレビューの幅
+	ThumbnailPreviewSeconds float64 // プレビューの長さ（秒）
+	// 焼き込み字幕の設定
+	SubtitleFontName string // 字幕のフォント（日本語を表示できるフォントを指定する）
+	SubtitleFontSize int    // 字幕の文字サイズ
+	// ナレーションの設定
+	NarrationBackgroundVolume float64 // ナレーション中の元の動画の音声の音量
+	// BGMの設定
+	MusicVolume         float64 // BGMの音量
+	MusicFadeOutSeconds float64 // 動画の終わりでBGMをフェードアウトする長さ（秒）
 	// Veo設定
 	VeoModel           string // Veoモデル名
 	GCSTempBucket      string // GCS一時保存バケット
 	GCSProjectID       string // GCPプロジェクトID
 	VeoPollingInterval int    // ポーリング間隔（秒）
 	VeoMaxWaitTime     int    // 最大待機時間（秒）
+	VeoMaxConcurrency  int    // シーンのクリップを並行して生成する最大数
+	// シーン間のトランジションの長さ（秒）
+	SceneTransitionSeconds float64
 }
 
 // DefaultFlowConfig はデフォルトのFlowConfigを返す
@@ -50,12 +65,26 @@ func DefaultFlowConfig() *FlowConfig {
 		DefaultVideoDuration: 8, // Veoは8秒が標準
 		ThumbnailWidth:       1280,
 		ThumbnailHeight:      720,
+		// アニメーションプレビュー設定
+		ThumbnailPreviewWidth:   480,
+		ThumbnailPreviewSeconds: 3,
+		// 焼き込み字幕の設定
+		SubtitleFontName: "Noto Sans CJK JP",
+		SubtitleFontSize: 24,
+		// ナレーションの設定
+		NarrationBackgroundVolume: 0.3,
+		// BGMの設定
+		MusicVolume:         0.25,
+		MusicFadeOutSeconds: 2,
 		// Veo設定
 		VeoModel:           "veo-3.1-fast-generate-001",
 		GCSTempBucket:      "tavinikkiy-temp",
 		GCSProjectID:       "tavinikkiy",
 		VeoPollingInterval: 5,
 		VeoMaxWaitTime:     120,
+		VeoMaxConcurrency:  3,
+		// シーン間のトランジション
+		SceneTransitionSeconds: 0.5,
 	}
 }
 
@@ -94,17 +123,24 @@ func WithVlogRepository(repo domain.IVLogRepository) FlowContextOption {
 	}
 }
 
-// WithGCSClient はGCSClientを設定するオプション
-func WithGCSClient(client *storage.Client) FlowContextOption {
+// WithMusicTrackRepository はMusicTrackRepositoryを設定するオプション
+func WithMusicTrackRepository(repo domain.IMusicTrackRepository) FlowContextOption {
 	return func(fc *FlowContext) {
-		fc.GCSClient = client
+		fc.MusicTrackRepo = repo
 	}
 }
 
-// WithGenAIClient はGenAIClientを設定するオプション
-func WithGenAIClient(client *genai.Client) FlowContextOption {
+// WithVideoGenerator はVideoGeneratorを設定するオプション
+func WithVideoGenerator(generator agent.IVideoGenerator) FlowContextOption {
 	return func(fc *FlowContext) {
-		fc.GenAI = client
+		fc.VideoGenerator = generator
+	}
+}
+
+// WithSpeechSynthesizer はSpeechSynthesizerを設定するオプション
+func WithSpeechSynthesizer(synthesizer agent.ISpeechSynthesizer) FlowContextOption {
+	return func(fc *FlowContext) {
+		fc.SpeechSynthesizer = synthesizer
 	}
 }
 
@@ -17,6 +17,17 @@ import (
 // VLog生成Flow
 // ============================================================
 
+// 各ステップの開始時点の進捗率（0-100）
+const (
+	progressAnalyzingStart  = 10
+	progressAnalyzingEnd    = 40
+	progressStoryboarding   = 40
+	progressGeneratingVideo = 45
+	progressVideoPollingEnd = 75
+	progressUploadingVideo  = 80
+	progressFinalizing      = 90
+)
+
 // VlogFlow はVLog生成フローの型エイリアス
 type VlogFlow = *core.Flow[*agent.VlogInput, *agent.VlogOutput, struct{}]
 
@@ -40,31 +51,60 @@ func RegisterVlogFlow(g *genkit.Genkit, registeredTools *RegisteredTools) VlogFl
 			return nil, fmt.Errorf("media analysis failed: %w", err)
 		}
 
-		// Step 2: VLog動画生成
-		videoResult, err := generateVlog(ctx, fc.Genkit, input, analysisResults, registeredTools)
+		// Step 2: 絵コンテ生成（確認済みの絵コンテが指定された場合はそのまま使用する）
+		agent.ReportProgress(ctx, agent.FlowProgress{
+			Step:     string(agent.StepStoryboarding),
+			Progress: progressStoryboarding,
+			Message:  "絵コンテを作成しています...",
+		})
+		storyboard := normalizeStoryboard(input.Storyboard, input.MediaItems, analysisResults, input.Style)
+		if storyboard == nil {
+			storyboard = generateStoryboard(ctx, fc.Genkit, input, analysisResults)
+		}
+		if input.ReviewStoryboard && input.Storyboard == nil {
+			// ユーザーが下書き（タイトル・絵コンテ）を確認・編集するまで動画は生成しない
+			title, description := resolveTitle(ctx, fc.Genkit, input.Title, input.Description, analysisResults)
+			return &agent.VlogOutput{
+				Title:       title,
+				Description: description,
+				Analytics:   buildAnalyticsSummary(analysisResults, len(input.MediaItems)),
+				Storyboard:  storyboard,
+			}, nil
+		}
+
+		// Step 3: VLog動画生成
+		agent.ReportProgress(ctx, agent.FlowProgress{
+			Step:     string(agent.StepGeneratingVideo),
+			Progress: progressGeneratingVideo,
+			Message:  "動画を生成しています...",
+		})
+		videoResult, err := generateVlog(ctx, fc.Genkit, input, analysisResults, storyboard, registeredTools)
 		if err != nil {
 			return nil, fmt.Errorf("video generation failed: %w", err)
 		}
 
-		// Step 3: サムネイル生成
-		thumbnailRaw, err := registeredTools.GenerateThumbnail.RunRaw(ctx, GenerateThumbnailInput{
-			VideoURL: videoResult.VideoURL,
-			VideoID:  videoResult.VideoID,
+		// Step 4: サムネイル生成
+		agent.ReportProgress(ctx, agent.FlowProgress{
+			Step:     string(agent.StepFinalizing),
+			Progress: progressFinalizing,
+			Message:  "仕上げ処理をしています...",
 		})
-		var thumbnailResult GenerateThumbnailOutput
+		thumbnailResult, err := generateVlogThumbnail(ctx, input, analysisResults, videoResult, registeredTools)
 		if err != nil {
 			// サムネイル生成失敗は致命的ではない
-			thumbnailResult = GenerateThumbnailOutput{
-				ThumbnailURL: "",
-			}
-		} else {
-			thumbnailResult, _ = generics.ConvertToStruct[GenerateThumbnailOutput](thumbnailRaw)
+			logger.Warn(ctx, fmt.Sprintf("thumbnail generation failed: %v", err))
+			thumbnailResult = &GenerateThumbnailOutput{}
 		}
 
-		// Step 4: 共有URL生成
-		shareRaw, err := registeredTools.GenerateShareURL.RunRaw(ctx, GenerateShareURLInput{
-			VideoID: videoResult.VideoID,
-			UserID:  input.UserID,
+		// Step 5: 共有URL生成
+		var shareRaw any
+		err = agent.RunStep(ctx, agent.StepNameShareURL, 0, func() error {
+			var err error
+			shareRaw, err = registeredTools.GenerateShareURL.RunRaw(ctx, GenerateShareURLInput{
+				VideoID: videoResult.VideoID,
+				UserID:  input.UserID,
+			})
+			return err
 		})
 		if err != nil {
 			return nil, fmt.Errorf("share URL generation failed: %w", err)
@@ -81,12 +121,16 @@ func RegisterVlogFlow(g *genkit.Genkit, registeredTools *RegisteredTools) VlogFl
 			VideoID:      videoResult.VideoID,
 			VideoURL:     videoResult.VideoURL,
 			ShareURL:     shareResult.ShareURL,
+			ShareCode:    shareResult.ShareCode,
 			ThumbnailURL: thumbnailResult.ThumbnailURL,
+			PreviewURL:   thumbnailResult.PreviewURL,
 			Duration:     videoResult.Duration,
 			Title:        videoResult.Title,
 			Description:  videoResult.Description,
 			Subtitles:    videoResult.Subtitles,
 			Analytics:    analytics,
+			MusicTrackID: videoResult.MusicTrackID,
+			Storyboard:   storyboard,
 		}, nil
 	})
 }
@@ -108,34 +152,75 @@ func validateVlogInput(input *agent.VlogInput, config *FlowConfig) error {
 	return nil
 }
 
+// loadAnalysisCheckpoint は前回の実行で保存したメディアの分析結果をチェックポイントから読み込む
+// 下書きの編集でメディアの並び順が変わった場合に備え、同じ位置にない場合は他の位置のチェックポイントから探す
+func loadAnalysisCheckpoint(ctx context.Context, fileID string, index, maxItems int) (agent.MediaAnalysisOutput, bool) {
+	var result agent.MediaAnalysisOutput
+	if agent.LoadCheckpoint(ctx, agent.StepNameMediaAnalysis, index, &result) && result.FileID == fileID {
+		return result, true
+	}
+	for i := 1; i <= maxItems; i++ {
+		var result agent.MediaAnalysisOutput
+		if i != index && agent.LoadCheckpoint(ctx, agent.StepNameMediaAnalysis, i, &result) && result.FileID == fileID {
+			return result, true
+		}
+	}
+	return agent.MediaAnalysisOutput{}, false
+}
+
 // analyzeAllMedia は全メディアを分析する
 func analyzeAllMedia(ctx context.Context, items []agent.MediaItem, registeredTools *RegisteredTools) ([]agent.MediaAnalysisOutput, error) {
 	fc := GetFlowContext(ctx)
 	results := make([]agent.MediaAnalysisOutput, 0, len(items))
 	var allErrors []error
 
+	// 下書きの編集で除外したメディアのチェックポイントも探せるよう、メディア数の上限までの位置を探す
+	maxItems := len(items)
+	if fc != nil {
+		maxItems = max(maxItems, fc.Config.MaxMediaItems)
+	}
+
 	var isAnalyzedCount int
-	for _, item := range items {
-		fmt.Println(item.IsAnalyzed)
+	for i, item := range items {
+		agent.ReportProgress(ctx, agent.FlowProgress{
+			Step:        string(agent.StepAnalyzing),
+			Progress:    progressAnalyzingStart + (progressAnalyzingEnd-progressAnalyzingStart)*float64(i)/float64(len(items)),
+			Message:     fmt.Sprintf("メディアを分析しています（%d/%d）...", i+1, len(items)),
+			CurrentItem: i + 1,
+			TotalItems:  len(items),
+		})
 		if item.IsAnalyzed {
 			isAnalyzedCount++
+			agent.SkipStep(ctx, agent.StepNameMediaAnalysis, i+1)
 			continue
 		}
-		resultRaw, analyzeErr := registeredTools.AnalyzeMedia.RunRaw(ctx, agent.MediaAnalysisInput{
-			FileID:      item.FileID,
-			URL:         item.URL,
-			Type:        item.Type,
-			ContentType: item.ContentType,
-		})
-		if analyzeErr != nil {
-			allErrors = append(allErrors, analyzeErr)
+		// 前回の実行で分析済みの場合はチェックポイントから再開する
+		result, ok := loadAnalysisCheckpoint(ctx, item.FileID, i+1, maxItems)
+		if ok {
+			results = append(results, result)
 			continue
 		}
+		err := agent.RunStep(ctx, agent.StepNameMediaAnalysis, i+1, func() error {
+			resultRaw, err := registeredTools.AnalyzeMedia.RunRaw(ctx, agent.MediaAnalysisInput{
+				FileID:      item.FileID,
+				URL:         item.URL,
+				Type:        item.Type,
+				ContentType: item.ContentType,
+			})
+			if err != nil {
+				return err
+			}
 
-		// RunRawはmap[string]interface{}を返すのでJSONを経由して変換
-		result, err := generics.ConvertToStruct[agent.MediaAnalysisOutput](resultRaw)
+			// RunRawはmap[string]interface{}を返すのでJSONを経由して変換
+			result, err = generics.ConvertToStruct[agent.MediaAnalysisOutput](resultRaw)
+			if err != nil {
+				return fmt.Errorf("failed to convert result: %w", err)
+			}
+			agent.SaveCheckpoint(ctx, agent.StepNameMediaAnalysis, i+1, result)
+			return nil
+		})
 		if err != nil {
-			allErrors = append(allErrors, fmt.Errorf("failed to convert result: %w", err))
+			allErrors = append(allErrors, err)
 			continue
 		}
 		results = append(results, result)
@@ -178,14 +263,16 @@ func analyzeAllMedia(ctx context.Context, items []agent.MediaItem, registeredToo
 }
 
 // generateVlog はAIモデルを使用してVLogを生成する
-func generateVlog(ctx context.Context, g *genkit.Genkit, input *agent.VlogInput, analysisResults []agent.MediaAnalysisOutput, registeredTools *RegisteredTools) (*GenerateVlogVideoOutput, error) {
+func generateVlog(ctx context.Context, g *genkit.Genkit, input *agent.VlogInput, analysisResults []agent.MediaAnalysisOutput, storyboard *agent.Storyboard, registeredTools *RegisteredTools) (*GenerateVlogVideoOutput, error) {
 	// 直接ツールを呼び出してVeo3で動画生成
 	resultRaw, err := registeredTools.GenerateVlogVideo.RunRaw(ctx, GenerateVlogVideoInput{
 		AnalysisResults: analysisResults,
 		Style:           input.Style,
 		Title:           input.Title,
+		Description:     input.Description,
 		MediaItems:      input.MediaItems,
 		UserID:          input.UserID,
+		Storyboard:      storyboard,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("vlog generation failed: %w", err)
@@ -199,6 +286,46 @@ func generateVlog(ctx context.Context, g *genkit.Genkit, input *agent.VlogInput,
 	return &result, nil
 }
 
+// generateVlogThumbnail は生成した動画からサムネイルを作成する
+// 動画から切り出せない場合に備えて、最も評価の高い写真を代わりに渡す
+func generateVlogThumbnail(ctx context.Context, input *agent.VlogInput, analysisResults []agent.MediaAnalysisOutput, videoResult *GenerateVlogVideoOutput, registeredTools *RegisteredTools) (*GenerateThumbnailOutput, error) {
+	// 前回の実行で作成済みの場合はチェックポイントから再開する
+	var result GenerateThumbnailOutput
+	if agent.LoadCheckpoint(ctx, agent.StepNameThumbnail, 0, &result) && result.ThumbnailURL != "" {
+		return &result, nil
+	}
+
+	thumbnailInput := GenerateThumbnailInput{
+		VideoURL: videoResult.VideoURL,
+		VideoID:  videoResult.VideoID,
+		UserID:   input.UserID,
+	}
+	fallback := selectReferenceImage(input.MediaItems, analysisResults)
+	if fallback == nil {
+		fallback = firstImage(input.MediaItems)
+	}
+	if fallback != nil {
+		thumbnailInput.FallbackImageURL = fallback.URL
+	}
+
+	err := agent.RunStep(ctx, agent.StepNameThumbnail, 0, func() error {
+		resultRaw, err := registeredTools.GenerateThumbnail.RunRaw(ctx, thumbnailInput)
+		if err != nil {
+			return err
+		}
+		result, err = generics.ConvertToStruct[GenerateThumbnailOutput](resultRaw)
+		if err != nil {
+			return fmt.Errorf("failed to convert result: %w", err)
+		}
+		agent.SaveCheckpoint(ctx, agent.StepNameThumbnail, 0, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // buildAnalyticsSummary は分析結果からサマリーを構築する
 func buildAnalyticsSummary(results []agent.MediaAnalysisOutput, mediaCount int) agent.VlogAnalytics {
 	locationsMap := make(map[string]struct{})
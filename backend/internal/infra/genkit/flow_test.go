@@ -0,0 +1,306 @@
+package genkit
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	fakevideo "github.com/o-ga09/zenn-hackthon-2026/internal/infra/fakeVideo"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage はアップロードされたファイルを保持するインメモリのストレージ
+type memoryStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: map[string][]byte{}}
+}
+
+func (s *memoryStorage) Upload(ctx context.Context, key string, base64Data string) (string, error) {
+	return s.UploadFile(ctx, key, []byte(base64Data), "")
+}
+
+func (s *memoryStorage) UploadFile(ctx context.Context, key string, file []byte, contentType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = file
+	return key, nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return string(s.files[key]), nil
+}
+
+func (s *memoryStorage) List(ctx context.Context, prefix string) (map[string]string, error) {
+	return nil, nil
+}
+
+// runTestVlogFlow はネットワークに接続せずにVLog生成フローを実行する
+// メディアは分析済み、タイトルは指定済みとしてGeminiを呼び出さない
+// ストレージのファイルはテスト用のHTTPサーバーから公開する
+func runTestVlogFlow(t *testing.T, generator agent.IVideoGenerator, style agent.VlogStyle, opts ...FlowContextOption) (*agent.VlogOutput, *memoryStorage, string) {
+	t.Helper()
+	return runTestVlogFlowInput(t, generator, func(input *agent.VlogInput) { input.Style = style }, opts...)
+}
+
+// runTestVlogFlowInput は入力を変更してVLog生成フローを実行する
+func runTestVlogFlowInput(t *testing.T, generator agent.IVideoGenerator, modify func(input *agent.VlogInput), opts ...FlowContextOption) (*agent.VlogOutput, *memoryStorage, string) {
+	t.Helper()
+	storage := newMemoryStorage()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storage.mu.Lock()
+		data, ok := storage.files[strings.TrimPrefix(r.URL.Path, "/")]
+		storage.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	var photo bytes.Buffer
+	require.NoError(t, jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 400, 300)), nil))
+	storage.files["media/1.jpg"] = photo.Bytes()
+
+	ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{CLOUDFLARE_R2_PUBLIC_URL: server.URL})
+	g := genkit.Init(ctx)
+	flow := RegisterVlogFlow(g, RegisterAllTools(g, "https://tavinikkiy.example.com"))
+	fc := NewFlowContext(append([]FlowContextOption{WithGenkit(g), WithStorage(storage), WithVideoGenerator(generator)}, opts...)...)
+
+	input := &agent.VlogInput{
+		UserID: "user-1",
+		Title:  "京都旅行",
+		MediaItems: []agent.MediaItem{
+			{FileID: "media-1", Type: "image", URL: server.URL + "/media/1.jpg", IsAnalyzed: true},
+			{FileID: "media-2", Type: "image", URL: server.URL + "/media/2.jpg", IsAnalyzed: true},
+		},
+	}
+	modify(input)
+	output, err := flow.Run(WithFlowContext(ctx, fc), input)
+	require.NoError(t, err)
+	return output, storage, server.URL
+}
+
+func TestVlogFlow(t *testing.T) {
+	t.Run("生成したクリップをR2にアップロードしてVLogを返す", func(t *testing.T) {
+		generator := &stubVideoGenerator{}
+		output, storage, publicURL := runTestVlogFlow(t, generator, agent.VlogStyle{Duration: 6})
+
+		assert.Equal(t, "京都旅行", output.Title)
+		assert.InDelta(t, 6, output.Duration, 0.001)
+		key := "users/user-1/vlogs/" + output.VideoID + ".mp4"
+		assert.Equal(t, publicURL+"/"+key, output.VideoURL)
+		assert.Equal(t, []byte("clip-1"), storage.files[key])
+		assert.Equal(t, []int{1}, generator.released)
+		assert.NotEmpty(t, output.ShareURL)
+
+		// 動画からフレームを切り出せないため、写真からサムネイルを作成する
+		thumbnailKey := "users/user-1/vlogs/" + output.VideoID + "/thumb.jpg"
+		assert.Equal(t, publicURL+"/"+thumbnailKey, output.ThumbnailURL)
+		_, err := jpeg.DecodeConfig(bytes.NewReader(storage.files[thumbnailKey]))
+		assert.NoError(t, err)
+		assert.Empty(t, output.PreviewURL)
+	})
+
+	t.Run("単色クリップをシーンごとに生成し、つなげた動画をR2にアップロードする", func(t *testing.T) {
+		tools := video.DefaultTools
+		if !tools.Available() {
+			t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+		}
+		flowConfig := DefaultFlowConfig()
+		flowConfig.ThumbnailPreviewFormat = "gif"
+		output, storage, publicURL := runTestVlogFlow(t, fakevideo.New(tools), agent.VlogStyle{Duration: 12, Transition: "zoom"}, WithFlowConfig(flowConfig))
+
+		// 3シーン×4秒を0.5秒のトランジションでつなげる
+		assert.InDelta(t, 11, output.Duration, 0.001)
+		data := storage.files["users/user-1/vlogs/"+output.VideoID+".mp4"]
+		require.NotEmpty(t, data)
+
+		path := filepath.Join(t.TempDir(), "vlog.mp4")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		info, err := tools.Probe(context.Background(), path)
+		require.NoError(t, err)
+		assert.InDelta(t, output.Duration, info.Duration, 0.2)
+
+		// 動画の代表フレームからサムネイルとプレビューを作成する
+		thumbnailKey := "users/user-1/vlogs/" + output.VideoID + "/thumb.jpg"
+		assert.Equal(t, publicURL+"/"+thumbnailKey, output.ThumbnailURL)
+		thumbnail, err := jpeg.DecodeConfig(bytes.NewReader(storage.files[thumbnailKey]))
+		require.NoError(t, err)
+		assert.Equal(t, flowConfig.ThumbnailWidth, thumbnail.Width)
+		assert.Equal(t, flowConfig.ThumbnailHeight, thumbnail.Height)
+		previewKey := "users/user-1/vlogs/" + output.VideoID + "/preview.gif"
+		assert.Equal(t, publicURL+"/"+previewKey, output.PreviewURL)
+		assert.True(t, bytes.HasPrefix(storage.files[previewKey], []byte("GIF89a")))
+	})
+}
+
+func TestVlogFlow_Storyboard(t *testing.T) {
+	t.Run("絵コンテの確認を指定した場合は動画を生成せずにタイトルと絵コンテを返す", func(t *testing.T) {
+		generator := &stubVideoGenerator{}
+		output, storage, _ := runTestVlogFlowInput(t, generator, func(input *agent.VlogInput) {
+			input.Style = agent.VlogStyle{Duration: 24}
+			input.ReviewStoryboard = true
+		})
+
+		assert.Equal(t, "京都旅行", output.Title)
+		require.NotNil(t, output.Storyboard)
+		assert.Len(t, output.Storyboard.Scenes, 3)
+		assert.Empty(t, output.VideoURL)
+		assert.Empty(t, generator.released)
+		assert.Len(t, storage.files, 1) // テスト用の写真のみ
+	})
+
+	t.Run("確認済みの絵コンテのシーンと字幕で動画を生成する", func(t *testing.T) {
+		generator := &stubVideoGenerator{}
+		output, _, _ := runTestVlogFlowInput(t, generator, func(input *agent.VlogInput) {
+			input.ReviewStoryboard = true
+			input.Storyboard = &agent.Storyboard{Scenes: []agent.StoryboardScene{
+				{MediaIDs: []string{"media-2"}, VeoPrompt: "A quiet temple garden.", Caption: "静かな庭園", DurationSeconds: 6},
+			}}
+		})
+
+		assert.NotEmpty(t, output.VideoURL)
+		assert.Equal(t, []int{1}, generator.released)
+		require.Len(t, output.Subtitles, 1)
+		assert.Equal(t, "静かな庭園", output.Subtitles[0].Text)
+		require.NotNil(t, output.Storyboard)
+		assert.Equal(t, []string{"media-2"}, output.Storyboard.Scenes[0].MediaIDs)
+	})
+}
+
+func TestGenerateVideo_BurnedSubtitles(t *testing.T) {
+	t.Run("字幕の焼き込みに失敗した場合は字幕なしの動画をアップロードする", func(t *testing.T) {
+		storage := newMemoryStorage()
+		ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{CLOUDFLARE_R2_PUBLIC_URL: "https://r2.example.com"})
+		fc := NewFlowContext(WithStorage(storage), WithVideoGenerator(&stubVideoGenerator{}))
+
+		result, err := GenerateVideo(ctx, fc, VideoGenerateConfig{
+			UserID:          "user-1",
+			Scenes:          []Scene{{Index: 1, Prompt: "清水寺", DurationSeconds: 6}},
+			BurnedSubtitles: []agent.SubtitleEntry{{StartTime: 0, EndTime: 3, Text: "清水寺に到着"}},
+		})
+		require.NoError(t, err)
+		// stubのクリップは動画として読み込めないため、焼き込みに失敗する
+		assert.Equal(t, []byte("clip-1"), storage.files["users/user-1/vlogs/"+result.VideoID+".mp4"])
+	})
+}
+
+// stubSpeechSynthesizer は文字数×0.2秒の音声を返す音声合成のスタブ
+type stubSpeechSynthesizer struct {
+	requests []agent.SpeechRequest
+}
+
+func (s *stubSpeechSynthesizer) Synthesize(_ context.Context, req agent.SpeechRequest) (*agent.SpeechClip, error) {
+	s.requests = append(s.requests, req)
+	return &agent.SpeechClip{Data: []byte("wav"), DurationSeconds: float64(len([]rune(req.Text))) * 0.2}, nil
+}
+
+func TestGenerateVideo_Narration(t *testing.T) {
+	t.Run("ナレーションのミックスに失敗した場合はナレーションなしの動画をアップロードする", func(t *testing.T) {
+		storage := newMemoryStorage()
+		synthesizer := &stubSpeechSynthesizer{}
+		ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{CLOUDFLARE_R2_PUBLIC_URL: "https://r2.example.com"})
+		fc := NewFlowContext(WithStorage(storage), WithVideoGenerator(&stubVideoGenerator{}), WithSpeechSynthesizer(synthesizer))
+
+		result, err := GenerateVideo(ctx, fc, VideoGenerateConfig{
+			UserID:         "user-1",
+			Scenes:         []Scene{{Index: 1, Prompt: "清水寺", DurationSeconds: 6}},
+			Narration:      []agent.SubtitleEntry{{StartTime: 0, EndTime: 3, Text: "清水寺に到着"}},
+			NarrationStyle: agent.NarrationStyle{SpeakerID: 3},
+		})
+		require.NoError(t, err)
+		require.Len(t, synthesizer.requests, 1)
+		assert.Equal(t, 3, synthesizer.requests[0].SpeakerID)
+		// stubのクリップは動画として読み込めないため、ミックスに失敗する
+		assert.Equal(t, []byte("clip-1"), storage.files["users/user-1/vlogs/"+result.VideoID+".mp4"])
+	})
+}
+
+func TestGenerateVideo_BackgroundMusic(t *testing.T) {
+	t.Run("BGMのミックスに失敗した場合はBGMなしの動画をアップロードする", func(t *testing.T) {
+		storage := newMemoryStorage()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("music"))
+		}))
+		t.Cleanup(server.Close)
+		ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{CLOUDFLARE_R2_PUBLIC_URL: server.URL})
+		fc := NewFlowContext(WithStorage(storage), WithVideoGenerator(&stubVideoGenerator{}))
+
+		result, err := GenerateVideo(ctx, fc, VideoGenerateConfig{
+			UserID: "user-1",
+			Scenes: []Scene{{Index: 1, Prompt: "清水寺", DurationSeconds: 6}},
+			Music:  newTestMusicTrack("happy", "楽しい", 60),
+		})
+		require.NoError(t, err)
+		// stubのクリップは動画として読み込めないため、ミックスに失敗する
+		assert.Equal(t, []byte("clip-1"), storage.files["users/user-1/vlogs/"+result.VideoID+".mp4"])
+		assert.Empty(t, result.MusicTrackID)
+	})
+}
+
+// memoryCheckpointer はチェックポイントを保持するインメモリのCheckpointer
+type memoryCheckpointer struct {
+	data map[int][]byte
+}
+
+func (c *memoryCheckpointer) LoadCheckpoint(ctx context.Context, name agent.StepName, item int) ([]byte, bool) {
+	data, ok := c.data[item]
+	return data, ok
+}
+
+func (c *memoryCheckpointer) SaveCheckpoint(ctx context.Context, name agent.StepName, item int, data []byte) error {
+	c.data[item] = data
+	return nil
+}
+
+func TestLoadAnalysisCheckpoint(t *testing.T) {
+	ctx := agent.WithCheckpointer(context.Background(), &memoryCheckpointer{data: map[int][]byte{
+		1: []byte(`{"fileId":"media-1","description":"金閣寺"}`),
+		2: []byte(`{"fileId":"media-2","description":"清水寺"}`),
+	}})
+
+	t.Run("同じ位置のチェックポイントから分析結果を読み込む", func(t *testing.T) {
+		result, ok := loadAnalysisCheckpoint(ctx, "media-1", 1, 50)
+		require.True(t, ok)
+		assert.Equal(t, "金閣寺", result.Description)
+	})
+
+	t.Run("並び順が変わった場合は他の位置のチェックポイントから分析結果を読み込む", func(t *testing.T) {
+		result, ok := loadAnalysisCheckpoint(ctx, "media-2", 1, 50)
+		require.True(t, ok)
+		assert.Equal(t, "media-2", result.FileID)
+		assert.Equal(t, "清水寺", result.Description)
+	})
+
+	t.Run("分析していないメディアの場合はfalseを返す", func(t *testing.T) {
+		_, ok := loadAnalysisCheckpoint(ctx, "media-3", 3, 50)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,57 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+// selectMusicTrack はBGMに使用する曲を選ぶ（BGMを付けない場合はnil）
+// ユーザーがアップロードした曲を優先し、ない場合はMusicMood・分析結果で最も多い雰囲気の順に一致するライブラリの曲を選ぶ
+func selectMusicTrack(ctx context.Context, repo domain.IMusicTrackRepository, style agent.VlogStyle, results []agent.MediaAnalysisOutput, duration float64) *domain.MusicTrack {
+	if repo == nil {
+		return nil
+	}
+
+	if style.MusicTrackID != "" {
+		track, err := repo.FindByID(ctx, style.MusicTrackID)
+		if err == nil {
+			return track
+		}
+		logger.Warn(ctx, fmt.Sprintf("failed to find uploaded music track %s: %v", style.MusicTrackID, err))
+	}
+
+	moods := []string{strings.TrimSpace(style.MusicMood), buildAnalyticsSummary(results, len(results)).Mood}
+	for i, mood := range moods {
+		if mood == "" || (i > 0 && mood == moods[0]) {
+			continue
+		}
+		tracks, err := repo.FindLibraryByMood(ctx, mood)
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to find music tracks for mood %s: %v", mood, err))
+			continue
+		}
+		if track := pickMusicTrack(tracks, duration); track != nil {
+			return track
+		}
+	}
+	return nil
+}
+
+// pickMusicTrack はループせずに動画の長さを満たす最初の曲を返す（ない場合は最も長い曲）
+func pickMusicTrack(tracks []*domain.MusicTrack, duration float64) *domain.MusicTrack {
+	var longest *domain.MusicTrack
+	for _, track := range tracks {
+		if track.DurationSeconds >= duration {
+			return track
+		}
+		if longest == nil || track.DurationSeconds > longest.DurationSeconds {
+			longest = track
+		}
+	}
+	return longest
+}
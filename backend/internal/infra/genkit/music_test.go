@@ -0,0 +1,90 @@
+package genkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubMusicTrackRepository はメモリ上の曲を返すBGMリポジトリのスタブ
+type stubMusicTrackRepository struct {
+	tracks []*domain.MusicTrack
+}
+
+func (r *stubMusicTrackRepository) Create(_ context.Context, track *domain.MusicTrack) error {
+	r.tracks = append(r.tracks, track)
+	return nil
+}
+
+func (r *stubMusicTrackRepository) FindByID(_ context.Context, id string) (*domain.MusicTrack, error) {
+	for _, track := range r.tracks {
+		if track.ID == id {
+			return track, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubMusicTrackRepository) FindLibraryByMood(_ context.Context, mood string) ([]*domain.MusicTrack, error) {
+	var tracks []*domain.MusicTrack
+	for _, track := range r.tracks {
+		if track.UserID == nil && track.Mood == mood {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks, nil
+}
+
+func newTestMusicTrack(id, mood string, duration float64) *domain.MusicTrack {
+	return &domain.MusicTrack{BaseModel: domain.BaseModel{ID: id}, Mood: mood, DurationSeconds: duration}
+}
+
+func TestSelectMusicTrack(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-1"
+	uploaded := &domain.MusicTrack{BaseModel: domain.BaseModel{ID: "uploaded"}, UserID: &userID, Mood: "楽しい"}
+	repo := &stubMusicTrackRepository{tracks: []*domain.MusicTrack{
+		uploaded,
+		newTestMusicTrack("happy", "楽しい", 60),
+		newTestMusicTrack("calm", "穏やか", 60),
+	}}
+	results := []agent.MediaAnalysisOutput{{Mood: "穏やか"}, {Mood: "穏やか"}, {Mood: "楽しい"}}
+
+	t.Run("アップロードされた曲を優先する", func(t *testing.T) {
+		track := selectMusicTrack(ctx, repo, agent.VlogStyle{MusicTrackID: "uploaded", MusicMood: "楽しい"}, results, 30)
+		assert.Equal(t, "uploaded", track.ID)
+	})
+
+	t.Run("アップロードされた曲が見つからない場合はライブラリの曲を使用する", func(t *testing.T) {
+		track := selectMusicTrack(ctx, repo, agent.VlogStyle{MusicTrackID: "deleted", MusicMood: "楽しい"}, results, 30)
+		assert.Equal(t, "happy", track.ID)
+	})
+
+	t.Run("MusicMoodに合う曲がない場合は分析結果で最も多い雰囲気の曲を使用する", func(t *testing.T) {
+		track := selectMusicTrack(ctx, repo, agent.VlogStyle{MusicMood: "ロマンチック"}, results, 30)
+		assert.Equal(t, "calm", track.ID)
+	})
+
+	t.Run("雰囲気に合う曲がない場合はBGMを付けない", func(t *testing.T) {
+		assert.Nil(t, selectMusicTrack(ctx, repo, agent.VlogStyle{MusicMood: "ロマンチック"}, nil, 30))
+		assert.Nil(t, selectMusicTrack(ctx, nil, agent.VlogStyle{MusicMood: "楽しい"}, results, 30))
+	})
+}
+
+func TestPickMusicTrack(t *testing.T) {
+	tracks := []*domain.MusicTrack{
+		newTestMusicTrack("short", "楽しい", 20),
+		newTestMusicTrack("long", "楽しい", 90),
+		newTestMusicTrack("middle", "楽しい", 45),
+	}
+	// ループせずに動画の長さを満たす最初の曲を選ぶ
+	assert.Equal(t, "long", pickMusicTrack(tracks, 40).ID)
+	assert.Equal(t, "short", pickMusicTrack(tracks, 15).ID)
+	// 動画の長さを満たす曲がない場合は最も長い曲をループする
+	assert.Equal(t, "long", pickMusicTrack(tracks, 120).ID)
+	assert.Nil(t, pickMusicTrack(nil, 40))
+}
@@ -0,0 +1,73 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+)
+
+// buildNarrationScript はナレーションの台本を作成する
+// 字幕がある場合は字幕をそのまま読み上げ、ない場合は分析結果のハイライトを動画の長さに均等に割り当てる
+func buildNarrationScript(subtitles []agent.SubtitleEntry, results []agent.MediaAnalysisOutput, duration float64) []agent.SubtitleEntry {
+	script := make([]agent.SubtitleEntry, 0, len(subtitles))
+	for _, entry := range subtitles {
+		if strings.TrimSpace(entry.Text) != "" {
+			script = append(script, entry)
+		}
+	}
+	if len(script) > 0 {
+		return script
+	}
+
+	highlights := buildAnalyticsSummary(results, len(results)).Highlights
+	if len(highlights) == 0 || duration <= 0 {
+		return script
+	}
+	timePerLine := duration / float64(len(highlights))
+	for i, text := range highlights {
+		script = append(script, agent.SubtitleEntry{
+			StartTime: float64(i) * timePerLine,
+			EndTime:   float64(i+1) * timePerLine,
+			Text:      text,
+		})
+	}
+	return script
+}
+
+// synthesizeNarration は台本の1行ごとに音声を合成し、字幕のタイミングに合わせて配置する
+// 各行は次の行が始まるまで（最後の行は動画の終わりまで）に収める
+func synthesizeNarration(ctx context.Context, synthesizer agent.ISpeechSynthesizer, script []agent.SubtitleEntry, style agent.NarrationStyle, duration float64) ([]video.NarrationClip, error) {
+	clips := make([]video.NarrationClip, 0, len(script))
+	for i, line := range script {
+		if line.StartTime >= duration {
+			break
+		}
+		end := duration
+		if i+1 < len(script) {
+			end = min(script[i+1].StartTime, duration)
+		}
+		if end <= line.StartTime {
+			continue
+		}
+
+		speech, err := synthesizer.Synthesize(ctx, agent.SpeechRequest{
+			Text:       line.Text,
+			SpeakerID:  style.SpeakerID,
+			SpeedScale: style.SpeedScale,
+			PitchScale: style.PitchScale,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize narration %d: %w", i+1, err)
+		}
+		clips = append(clips, video.NarrationClip{
+			Data:        speech.Data,
+			Start:       line.StartTime,
+			Duration:    speech.DurationSeconds,
+			MaxDuration: end - line.StartTime,
+		})
+	}
+	return clips, nil
+}
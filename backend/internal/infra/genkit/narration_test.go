@@ -0,0 +1,57 @@
+package genkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildNarrationScript(t *testing.T) {
+	t.Run("字幕がある場合は空の字幕を除いて読み上げる", func(t *testing.T) {
+		subtitles := []agent.SubtitleEntry{
+			{StartTime: 0, EndTime: 3, Text: "清水寺に到着"},
+			{StartTime: 4, EndTime: 7, Text: " "},
+			{StartTime: 8, EndTime: 11, Text: "抹茶パフェ"},
+		}
+		script := buildNarrationScript(subtitles, nil, 12)
+		assert.Equal(t, []agent.SubtitleEntry{subtitles[0], subtitles[2]}, script)
+	})
+
+	t.Run("字幕がない場合はハイライトを動画の長さに均等に割り当てる", func(t *testing.T) {
+		results := []agent.MediaAnalysisOutput{
+			{SuggestedCaption: "清水寺に到着"},
+			{SuggestedCaption: ""},
+			{SuggestedCaption: "抹茶パフェ"},
+		}
+		script := buildNarrationScript(nil, results, 10)
+		assert.Equal(t, []agent.SubtitleEntry{
+			{StartTime: 0, EndTime: 5, Text: "清水寺に到着"},
+			{StartTime: 5, EndTime: 10, Text: "抹茶パフェ"},
+		}, script)
+	})
+}
+
+func TestSynthesizeNarration(t *testing.T) {
+	synthesizer := &stubSpeechSynthesizer{}
+	script := []agent.SubtitleEntry{
+		{StartTime: 0.5, EndTime: 3, Text: "清水寺に到着"},
+		{StartTime: 4, EndTime: 7, Text: "抹茶パフェ"},
+		{StartTime: 9, EndTime: 12, Text: "動画の後"},
+	}
+
+	clips, err := synthesizeNarration(context.Background(), synthesizer, script, agent.NarrationStyle{SpeakerID: 3, SpeedScale: 1.2}, 8)
+	require.NoError(t, err)
+	// 動画の終わりより後に始まる行は読み上げない
+	require.Len(t, clips, 2)
+	assert.Equal(t, 0.5, clips[0].Start)
+	assert.InDelta(t, 1.2, clips[0].Duration, 0.001)
+	// 次の行が始まるまで、最後の行は動画の終わりまでに収める
+	assert.InDelta(t, 3.5, clips[0].MaxDuration, 0.001)
+	assert.InDelta(t, 4, clips[1].MaxDuration, 0.001)
+
+	require.Len(t, synthesizer.requests, 2)
+	assert.Equal(t, agent.SpeechRequest{Text: "清水寺に到着", SpeakerID: 3, SpeedScale: 1.2}, synthesizer.requests[0])
+}
@@ -0,0 +1,178 @@
+package genkit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+)
+
+// veoClipDurations はVeoが生成できるクリップの長さ（秒）
+var veoClipDurations = []int32{4, 6, 8}
+
+// Scene はVeoで1クリップとして生成するVLogのシーン
+type Scene struct {
+	Index           int                   // 1始まりのシーン番号
+	Prompt          string                // Veo用プロンプト
+	DurationSeconds int32                 // クリップの長さ（4, 6, 8秒のいずれか）
+	ReferenceImage  *agent.ReferenceImage // Veoに渡すユーザーの写真（写真がない場合はnil）
+}
+
+// planScenes は目標再生時間と分析結果からVLogのシーンを組み立てる
+// 分析結果を各シーンに順に割り当てた絵コンテを作成し、そこからシーンを組み立てる
+func planScenes(items []agent.MediaItem, results []agent.MediaAnalysisOutput, style agent.VlogStyle) []Scene {
+	return scenesFromStoryboard(planStoryboard(results, style), items, results, style)
+}
+
+// sceneLayout は目標再生時間からシーン数と1シーンの長さを決める
+// 1クリップに収まる場合は1シーン、それ以外は最小・最大シーン数の範囲で分割する
+func sceneLayout(style agent.VlogStyle) (int, int32) {
+	target := style.Duration
+	if target <= 0 {
+		target = constant.DefaultVLogDurationSeconds
+	}
+	target = min(target, constant.MaxVLogDurationSeconds)
+
+	count := 1
+	if target > constant.VeoClipMaxSeconds {
+		count = ceilDiv(target, constant.VeoClipMaxSeconds)
+		count = max(constant.MinVLogScenes, min(count, constant.MaxVLogScenes))
+	}
+	return count, veoClipDuration(ceilDiv(target, count))
+}
+
+// scenesFromStoryboard は絵コンテの各シーンをVeoで生成するシーンに変換する
+// 各シーンにはシーンの元にするメディアの中から最も評価の高い写真（分析結果がない場合は最初の写真）を参考画像として設定する
+func scenesFromStoryboard(storyboard *agent.Storyboard, items []agent.MediaItem, results []agent.MediaAnalysisOutput, style agent.VlogStyle) []Scene {
+	// 元にするメディアに写真がないシーンは全体で最も評価の高い写真（分析結果がない場合は最初の写真）を使用する
+	fallback := selectReferenceImage(items, results)
+	if fallback == nil {
+		fallback = firstImage(items)
+	}
+	count := len(storyboard.Scenes)
+	scenes := make([]Scene, 0, count)
+	for i, s := range storyboard.Scenes {
+		assigned := sceneItems(items, s.MediaIDs)
+		reference := selectReferenceImage(assigned, results)
+		if reference == nil {
+			reference = firstImage(assigned)
+		}
+		if reference == nil {
+			reference = fallback
+		}
+
+		prompt := s.VeoPrompt
+		if s.CameraDirection != "" {
+			prompt += fmt.Sprintf(" Camera: %s.", strings.TrimSuffix(s.CameraDirection, "."))
+		}
+		if reference != nil {
+			prompt = referencePrompt(style.ReferenceMode) + prompt
+		}
+		if count > 1 {
+			prompt += fmt.Sprintf(" This is scene %d of %d of the vlog, so keep the look consistent with the other scenes.", i+1, count)
+		}
+		scenes = append(scenes, Scene{
+			Index:           i + 1,
+			Prompt:          prompt,
+			DurationSeconds: int32(s.DurationSeconds),
+			ReferenceImage:  reference,
+		})
+	}
+	return scenes
+}
+
+// sceneItems はシーンの元にするメディアを返す
+func sceneItems(items []agent.MediaItem, mediaIDs []string) []agent.MediaItem {
+	var selected []agent.MediaItem
+	for _, item := range items {
+		if slices.Contains(mediaIDs, item.FileID) {
+			selected = append(selected, item)
+		}
+	}
+	return selected
+}
+
+// selectReferenceImage は分析結果のある写真の中から最も評価の高い写真を返す（同点の場合は先の写真）
+func selectReferenceImage(items []agent.MediaItem, results []agent.MediaAnalysisOutput) *agent.ReferenceImage {
+	scores := make(map[string]int, len(results))
+	for _, r := range results {
+		scores[r.FileID] = referenceScore(r)
+	}
+
+	var best *agent.ReferenceImage
+	bestScore := -1
+	for _, item := range items {
+		score, ok := scores[item.FileID]
+		if item.Type != "image" || !ok || score <= bestScore {
+			continue
+		}
+		best = &agent.ReferenceImage{FileID: item.FileID, URL: item.URL, ContentType: item.ContentType}
+		bestScore = score
+	}
+	return best
+}
+
+// firstImage は最初の写真を返す（写真がない場合はnil）
+func firstImage(items []agent.MediaItem) *agent.ReferenceImage {
+	for _, item := range items {
+		if item.Type == "image" {
+			return &agent.ReferenceImage{FileID: item.FileID, URL: item.URL, ContentType: item.ContentType}
+		}
+	}
+	return nil
+}
+
+// referenceScore は写真の評価（写っているランドマーク・アクティビティが多いほど高い）
+func referenceScore(result agent.MediaAnalysisOutput) int {
+	return 2*len(result.Landmarks) + len(result.Activities)
+}
+
+// referencePrompt は写真の使い方に応じたプロンプトの指示を返す
+func referencePrompt(mode string) string {
+	if mode == agent.ReferenceModeAnimate {
+		return "Animate the provided photo: keep its composition and subjects, and bring it to life with natural motion and a gentle camera move. "
+	}
+	return "Use the reference photo as inspiration for the scenery, subjects and colors. "
+}
+
+// sceneResults はindex番目のシーンに割り当てる分析結果を返す
+// 分析結果がシーン数より少ない場合は先頭から繰り返して割り当てる
+func sceneResults(results []agent.MediaAnalysisOutput, index, count int) []agent.MediaAnalysisOutput {
+	if len(results) == 0 {
+		return nil
+	}
+	if len(results) < count {
+		return results[index%len(results) : index%len(results)+1]
+	}
+	return results[index*len(results)/count : (index+1)*len(results)/count]
+}
+
+// summarizeAnalysis は分析結果からプロンプト用サマリーを構築する
+func summarizeAnalysis(results []agent.MediaAnalysisOutput) []MediaAnalysisSummary {
+	summaries := make([]MediaAnalysisSummary, 0, len(results))
+	for _, r := range results {
+		summaries = append(summaries, MediaAnalysisSummary{
+			Description: r.Description,
+			Landmarks:   r.Landmarks,
+			Activities:  r.Activities,
+			Mood:        r.Mood,
+		})
+	}
+	return summaries
+}
+
+// veoClipDuration は指定秒数以上でVeoが生成できる最短のクリップの長さを返す
+func veoClipDuration(seconds int) int32 {
+	for _, d := range veoClipDurations {
+		if int32(seconds) <= d {
+			return d
+		}
+	}
+	return veoClipDurations[len(veoClipDurations)-1]
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
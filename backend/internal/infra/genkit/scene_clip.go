@@ -0,0 +1,102 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+)
+
+// generateSceneClips はシーンごとのクリップを最大concurrency件ずつ並行して生成し、シーン順に返す
+// いずれかのシーンが失敗した場合は残りの生成を中断する。生成済みのクリップはチェックポイントから再開できるよう削除しない
+func generateSceneClips(ctx context.Context, gen agent.IVideoGenerator, requests []agent.VideoClipRequest, concurrency int) ([]*agent.VideoClip, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		firstErr  error
+		clips     = make([]*agent.VideoClip, len(requests))
+		fractions = make([]float64, len(requests))
+		sem       = make(chan struct{}, max(concurrency, 1))
+	)
+	// 全シーンの進み具合の平均を動画生成の進捗として通知する（muを保持して呼び出す）
+	report := func() {
+		total, done := 0.0, 0
+		for i, f := range fractions {
+			total += f
+			if clips[i] != nil {
+				done++
+			}
+		}
+		agent.ReportProgress(ctx, agent.FlowProgress{
+			Step:     string(agent.StepGeneratingVideo),
+			Progress: progressGeneratingVideo + (progressVideoPollingEnd-progressGeneratingVideo)*total/float64(len(requests)),
+			Message:  fmt.Sprintf("動画を生成しています（%d/%dシーン完了）...", done, len(requests)),
+		})
+	}
+
+	for i, req := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			clip, err := gen.GenerateClip(ctx, req, func(fraction float64) {
+				mu.Lock()
+				defer mu.Unlock()
+				fractions[i] = min(fraction, 1)
+				report()
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("scene %d: %w", req.SceneIndex, err)
+					cancel(firstErr)
+				}
+				return
+			}
+			clips[i] = clip
+			fractions[i] = 1
+			report()
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, context.Cause(ctx)
+	}
+	return clips, nil
+}
+
+// concatSceneClips はクリップをトランジションでつなげて1つの動画にし、動画の長さ（秒）とともに返す
+func concatSceneClips(ctx context.Context, tools video.Tools, clips []*agent.VideoClip, transition video.Transition, transitionDuration time.Duration) ([]byte, float64, error) {
+	data := make([][]byte, 0, len(clips))
+	durations := make([]float64, 0, len(clips))
+	for _, clip := range clips {
+		data = append(data, clip.Data)
+		durations = append(durations, clip.DurationSeconds)
+	}
+	output, err := tools.Concat(ctx, data, video.ConcatOptions{
+		Transition:         transition,
+		TransitionDuration: transitionDuration,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to concatenate scene clips: %w", err)
+	}
+	return output, video.TotalDuration(durations, transitionDuration), nil
+}
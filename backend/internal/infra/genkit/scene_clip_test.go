@@ -0,0 +1,116 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	fakevideo "github.com/o-ga09/zenn-hackthon-2026/internal/infra/fakeVideo"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubVideoGenerator は生成中のクリップ数を記録し、固定のデータを返すジェネレーター
+type stubVideoGenerator struct {
+	mu        sync.Mutex
+	running   int
+	maxActive int
+	released  []int
+	delay     time.Duration
+	failAt    int // 失敗させるシーン番号（0の場合は失敗しない）
+}
+
+func (g *stubVideoGenerator) GenerateClip(ctx context.Context, req agent.VideoClipRequest, onProgress func(fraction float64)) (*agent.VideoClip, error) {
+	g.mu.Lock()
+	g.running++
+	g.maxActive = max(g.maxActive, g.running)
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		g.running--
+		g.mu.Unlock()
+	}()
+
+	onProgress(0.5)
+	// 後のシーンほど早く終わるようにして、完了順とシーン順が異なる状況を再現する
+	select {
+	case <-time.After(g.delay / time.Duration(req.SceneIndex)):
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+	if req.SceneIndex == g.failAt {
+		return nil, fmt.Errorf("veo error")
+	}
+	return &agent.VideoClip{
+		SceneIndex:      req.SceneIndex,
+		Data:            []byte(fmt.Sprintf("clip-%d", req.SceneIndex)),
+		DurationSeconds: float64(req.DurationSeconds),
+		SourceURI:       fmt.Sprintf("stub://%d", req.SceneIndex),
+	}, nil
+}
+
+func (g *stubVideoGenerator) ReleaseClip(ctx context.Context, clip *agent.VideoClip) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.released = append(g.released, clip.SceneIndex)
+}
+
+func newTestClipRequests(count int, duration int32) []agent.VideoClipRequest {
+	requests := make([]agent.VideoClipRequest, 0, count)
+	for i := range count {
+		requests = append(requests, agent.VideoClipRequest{SceneIndex: i + 1, Prompt: fmt.Sprintf("scene %d", i+1), DurationSeconds: duration})
+	}
+	return requests
+}
+
+func TestGenerateSceneClips(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("同時実行数を制限して生成し、シーン順に返す", func(t *testing.T) {
+		gen := &stubVideoGenerator{delay: 40 * time.Millisecond}
+		clips, err := generateSceneClips(ctx, gen, newTestClipRequests(5, 8), 2)
+		require.NoError(t, err)
+
+		require.Len(t, clips, 5)
+		for i, clip := range clips {
+			assert.Equal(t, i+1, clip.SceneIndex)
+			assert.Equal(t, fmt.Sprintf("clip-%d", i+1), string(clip.Data))
+		}
+		assert.Equal(t, 2, gen.maxActive)
+	})
+
+	t.Run("いずれかのシーンが失敗した場合はエラーを返し、生成済みのクリップは削除しない", func(t *testing.T) {
+		gen := &stubVideoGenerator{delay: 40 * time.Millisecond, failAt: 2}
+		_, err := generateSceneClips(ctx, gen, newTestClipRequests(4, 8), 4)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "scene 2: veo error")
+		assert.Empty(t, gen.released)
+	})
+}
+
+func TestConcatSceneClips(t *testing.T) {
+	tools := video.DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	clips, err := generateSceneClips(ctx, fakevideo.New(tools), newTestClipRequests(3, 2), 3)
+	require.NoError(t, err)
+	output, duration, err := concatSceneClips(ctx, tools, clips, video.TransitionSlide, 500*time.Millisecond)
+	require.NoError(t, err)
+	assert.InDelta(t, 5, duration, 0.001)
+
+	path := filepath.Join(t.TempDir(), "vlog.mp4")
+	require.NoError(t, os.WriteFile(path, output, 0o600))
+	info, err := tools.Probe(ctx, path)
+	require.NoError(t, err)
+	assert.InDelta(t, duration, info.Duration, 0.2)
+	assert.Equal(t, 640, info.Width)
+}
@@ -0,0 +1,111 @@
+package genkit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanScenes(t *testing.T) {
+	results := make([]agent.MediaAnalysisOutput, 0, 6)
+	for i := range 6 {
+		results = append(results, agent.MediaAnalysisOutput{Landmarks: []string{fmt.Sprintf("landmark-%d", i)}})
+	}
+
+	t.Run("1クリップに収まる場合は目標以上の最短の長さの1シーンにする", func(t *testing.T) {
+		scenes := planScenes(nil, results, agent.VlogStyle{Duration: 5})
+		require.Len(t, scenes, 1)
+		assert.Equal(t, 1, scenes[0].Index)
+		assert.Equal(t, int32(6), scenes[0].DurationSeconds)
+		assert.NotContains(t, scenes[0].Prompt, "This is scene")
+	})
+
+	t.Run("目標再生時間が未指定の場合はデフォルト秒数の1シーンにする", func(t *testing.T) {
+		scenes := planScenes(nil, results, agent.VlogStyle{})
+		require.Len(t, scenes, 1)
+		assert.Equal(t, int32(constant.DefaultVLogDurationSeconds), scenes[0].DurationSeconds)
+	})
+
+	t.Run("長い動画はシーンに分割し、分析結果を順に割り当てる", func(t *testing.T) {
+		scenes := planScenes(nil, results, agent.VlogStyle{Duration: 30})
+		require.Len(t, scenes, 4)
+		for i, scene := range scenes {
+			assert.Equal(t, i+1, scene.Index)
+			assert.Equal(t, int32(8), scene.DurationSeconds)
+			assert.Contains(t, scene.Prompt, fmt.Sprintf("This is scene %d of 4", i+1))
+		}
+		assert.Contains(t, scenes[0].Prompt, "landmark-0")
+		assert.NotContains(t, scenes[0].Prompt, "landmark-2")
+		assert.Contains(t, scenes[3].Prompt, "landmark-5")
+	})
+
+	t.Run("シーン数は最小シーン数以上にする", func(t *testing.T) {
+		scenes := planScenes(nil, results, agent.VlogStyle{Duration: 10})
+		require.Len(t, scenes, constant.MinVLogScenes)
+		assert.Equal(t, int32(4), scenes[0].DurationSeconds)
+	})
+
+	t.Run("シーン数は最大シーン数までにする", func(t *testing.T) {
+		scenes := planScenes(nil, results, agent.VlogStyle{Duration: 600})
+		require.Len(t, scenes, constant.MaxVLogScenes)
+		assert.Equal(t, int32(8), scenes[0].DurationSeconds)
+	})
+
+	t.Run("分析結果がシーン数より少ない場合は繰り返して割り当てる", func(t *testing.T) {
+		scenes := planScenes(nil, results[:2], agent.VlogStyle{Duration: 24})
+		require.Len(t, scenes, 3)
+		assert.Contains(t, scenes[0].Prompt, "landmark-0")
+		assert.Contains(t, scenes[1].Prompt, "landmark-1")
+		assert.Contains(t, scenes[2].Prompt, "landmark-0")
+	})
+}
+
+func TestPlanScenes_ReferenceImage(t *testing.T) {
+	items := []agent.MediaItem{
+		{FileID: "video-1", Type: "video", URL: "https://example.com/video-1.mp4"},
+		{FileID: "image-1", Type: "image", URL: "https://example.com/image-1.jpg", ContentType: "image/jpeg"},
+		{FileID: "image-2", Type: "image", URL: "https://example.com/image-2.png", ContentType: "image/png"},
+		{FileID: "image-3", Type: "image", URL: "https://example.com/image-3.jpg", ContentType: "image/jpeg"},
+	}
+	results := []agent.MediaAnalysisOutput{
+		{FileID: "video-1", Landmarks: []string{"a", "b", "c"}},
+		{FileID: "image-1", Activities: []string{"hiking"}},
+		{FileID: "image-2", Landmarks: []string{"tower"}, Activities: []string{"walking"}},
+		{FileID: "image-3", Activities: []string{"eating"}},
+	}
+
+	t.Run("最も評価の高い写真を参考画像にする", func(t *testing.T) {
+		scenes := planScenes(items, results, agent.VlogStyle{Duration: 8})
+		require.Len(t, scenes, 1)
+		require.NotNil(t, scenes[0].ReferenceImage)
+		assert.Equal(t, "image-2", scenes[0].ReferenceImage.FileID)
+		assert.Equal(t, "image/png", scenes[0].ReferenceImage.ContentType)
+		assert.Contains(t, scenes[0].Prompt, "Use the reference photo as inspiration")
+	})
+
+	t.Run("シーンごとに割り当てた分析結果の写真を使い、写真がないシーンは全体で最も評価の高い写真を使う", func(t *testing.T) {
+		scenes := planScenes(items, results, agent.VlogStyle{Duration: 32, ReferenceMode: agent.ReferenceModeAnimate})
+		require.Len(t, scenes, 4)
+		assert.Equal(t, "image-2", scenes[0].ReferenceImage.FileID)
+		assert.Equal(t, "image-1", scenes[1].ReferenceImage.FileID)
+		assert.Equal(t, "image-2", scenes[2].ReferenceImage.FileID)
+		assert.Equal(t, "image-3", scenes[3].ReferenceImage.FileID)
+		assert.Contains(t, scenes[0].Prompt, "Animate the provided photo")
+	})
+
+	t.Run("分析結果がない場合は最初の写真を使う", func(t *testing.T) {
+		scenes := planScenes(items, nil, agent.VlogStyle{})
+		require.NotNil(t, scenes[0].ReferenceImage)
+		assert.Equal(t, "image-1", scenes[0].ReferenceImage.FileID)
+	})
+
+	t.Run("写真がない場合はテキストのみで生成する", func(t *testing.T) {
+		scenes := planScenes(items[:1], results[:1], agent.VlogStyle{})
+		assert.Nil(t, scenes[0].ReferenceImage)
+		assert.NotContains(t, scenes[0].Prompt, "photo")
+	})
+}
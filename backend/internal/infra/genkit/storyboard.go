@@ -0,0 +1,260 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+)
+
+// maxCaptionLength はメディアの説明文を字幕に使うときの最大文字数
+const maxCaptionLength = 50
+
+// StoryboardPromptInput はgenerate_storyboard.prompt用の入力
+type StoryboardPromptInput struct {
+	Destination   string            `json:"destination"`
+	TravelDate    string            `json:"travelDate"`
+	Theme         string            `json:"theme"`
+	MusicMood     string            `json:"musicMood"`
+	Transition    string            `json:"transition"`
+	SceneCount    int               `json:"sceneCount"`
+	SceneDuration int               `json:"sceneDuration"`
+	Media         []StoryboardMedia `json:"media"`
+}
+
+// StoryboardMedia は絵コンテの生成に渡すメディアの分析結果
+type StoryboardMedia struct {
+	FileID           string   `json:"fileId"`
+	Type             string   `json:"type"`
+	Description      string   `json:"description"`
+	Landmarks        []string `json:"landmarks"`
+	Activities       []string `json:"activities"`
+	Mood             string   `json:"mood"`
+	SuggestedCaption string   `json:"suggestedCaption"`
+	CapturedAt       string   `json:"capturedAt,omitempty"` // 撮影日時（RFC3339形式）
+	Location         string   `json:"location,omitempty"`   // 撮影地点（緯度,経度）
+}
+
+// generateStoryboard は分析結果から絵コンテを生成する
+// Geminiでの生成に失敗した場合は分析結果を各シーンに順に割り当てた絵コンテを使用する
+func generateStoryboard(ctx context.Context, g *genkit.Genkit, input *agent.VlogInput, results []agent.MediaAnalysisOutput) *agent.Storyboard {
+	// 前回の実行で生成済みの場合はチェックポイントから再開する
+	var storyboard *agent.Storyboard
+	if agent.LoadCheckpoint(ctx, agent.StepNameStoryboard, 0, &storyboard) && storyboard != nil {
+		return storyboard
+	}
+
+	err := agent.RunStep(ctx, agent.StepNameStoryboard, 0, func() error {
+		generated, err := promptStoryboard(ctx, g, input, results)
+		if err != nil {
+			return err
+		}
+		storyboard = normalizeStoryboard(generated, input.MediaItems, results, input.Style)
+		if storyboard == nil {
+			return fmt.Errorf("generated storyboard has no scenes")
+		}
+		agent.SaveCheckpoint(ctx, agent.StepNameStoryboard, 0, storyboard)
+		return nil
+	})
+	if err != nil {
+		logger.Warn(ctx, fmt.Sprintf("storyboard generation failed, using default storyboard: %v", err))
+		return planStoryboard(results, input.Style)
+	}
+	return storyboard
+}
+
+// promptStoryboard はgenerate_storyboard.promptで絵コンテを生成する
+func promptStoryboard(ctx context.Context, g *genkit.Genkit, input *agent.VlogInput, results []agent.MediaAnalysisOutput) (*agent.Storyboard, error) {
+	prompt := genkit.LookupPrompt(g, "tavinikkiy/generate_storyboard")
+	if prompt == nil {
+		return nil, fmt.Errorf("prompt 'tavinikkiy/generate_storyboard' not found")
+	}
+
+	count, duration := sceneLayout(input.Style)
+	promptInput := StoryboardPromptInput{
+		Destination:   input.Destination,
+		TravelDate:    input.TravelDate,
+		Theme:         input.Style.Theme,
+		MusicMood:     input.Style.MusicMood,
+		Transition:    input.Style.Transition,
+		SceneCount:    count,
+		SceneDuration: int(duration),
+		Media:         make([]StoryboardMedia, 0, len(results)),
+	}
+	items := make(map[string]agent.MediaItem, len(input.MediaItems))
+	for _, item := range input.MediaItems {
+		items[item.FileID] = item
+	}
+	for _, r := range results {
+		item := items[r.FileID]
+		media := StoryboardMedia{
+			FileID:           r.FileID,
+			Type:             item.Type,
+			Description:      r.Description,
+			Landmarks:        r.Landmarks,
+			Activities:       r.Activities,
+			Mood:             r.Mood,
+			SuggestedCaption: r.SuggestedCaption,
+			CapturedAt:       item.Timestamp,
+		}
+		if item.Location != nil {
+			media.Location = fmt.Sprintf("%.6f,%.6f", item.Location.Latitude, item.Location.Longitude)
+		}
+		promptInput.Media = append(promptInput.Media, media)
+	}
+
+	resp, err := prompt.Execute(ctx, ai.WithInput(promptInput))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute prompt: %w", err)
+	}
+
+	var result agent.Storyboard
+	if err := resp.Output(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+	return &result, nil
+}
+
+// planStoryboard は目標再生時間に合わせてシーンを分割し、分析結果を各シーンに順に割り当てた絵コンテを作成する
+func planStoryboard(results []agent.MediaAnalysisOutput, style agent.VlogStyle) *agent.Storyboard {
+	count, duration := sceneLayout(style)
+	storyboard := &agent.Storyboard{Scenes: make([]agent.StoryboardScene, 0, count)}
+	for i := range count {
+		assigned := sceneResults(results, i, count)
+		mediaIDs := make([]string, 0, len(assigned))
+		for _, r := range assigned {
+			if r.FileID != "" {
+				mediaIDs = append(mediaIDs, r.FileID)
+			}
+		}
+		storyboard.Scenes = append(storyboard.Scenes, agent.StoryboardScene{
+			MediaIDs:        mediaIDs,
+			VeoPrompt:       BuildVlogPrompt(summarizeAnalysis(assigned), styleConfig(style)),
+			Caption:         sceneCaption(assigned),
+			DurationSeconds: int(duration),
+		})
+	}
+	return storyboard
+}
+
+// normalizeStoryboard はGeminiやユーザーが作成した絵コンテをVeoで生成できる形に整える
+// シーン数を上限までに切り詰め、長さをVeoが生成できる長さに丸め、存在しないメディアのIDを取り除く
+// プロンプトが空のシーンは元にするメディアの分析結果からプロンプトを作成する（シーンがない場合はnil）
+func normalizeStoryboard(storyboard *agent.Storyboard, items []agent.MediaItem, results []agent.MediaAnalysisOutput, style agent.VlogStyle) *agent.Storyboard {
+	if storyboard == nil || len(storyboard.Scenes) == 0 {
+		return nil
+	}
+
+	_, defaultDuration := sceneLayout(style)
+	scenes := storyboard.Scenes[:min(len(storyboard.Scenes), constant.MaxVLogScenes)]
+	normalized := &agent.Storyboard{Scenes: make([]agent.StoryboardScene, 0, len(scenes))}
+	for _, s := range scenes {
+		mediaIDs := make([]string, 0, len(s.MediaIDs))
+		for _, item := range sceneItems(items, s.MediaIDs) {
+			mediaIDs = append(mediaIDs, item.FileID)
+		}
+		duration := defaultDuration
+		if s.DurationSeconds > 0 {
+			duration = veoClipDuration(s.DurationSeconds)
+		}
+		prompt := strings.TrimSpace(s.VeoPrompt)
+		if prompt == "" {
+			prompt = BuildVlogPrompt(summarizeAnalysis(mediaResults(results, mediaIDs)), styleConfig(style))
+		}
+		normalized.Scenes = append(normalized.Scenes, agent.StoryboardScene{
+			MediaIDs:        mediaIDs,
+			CameraDirection: strings.TrimSpace(s.CameraDirection),
+			VeoPrompt:       prompt,
+			Caption:         strings.TrimSpace(s.Caption),
+			DurationSeconds: int(duration),
+		})
+	}
+	return normalized
+}
+
+// subtitlesFromStoryboard は絵コンテのキャプションを各シーンの再生時間に合わせた字幕に変換する
+// シーンの開始時刻はクリップをつなげるときのトランジションの重なりを考慮し、字幕は次のシーンが始まる0.5秒前に消す
+func subtitlesFromStoryboard(storyboard *agent.Storyboard, transition time.Duration) []agent.SubtitleEntry {
+	subtitles := make([]agent.SubtitleEntry, 0, len(storyboard.Scenes))
+	durations := make([]float64, 0, len(storyboard.Scenes))
+	for _, s := range storyboard.Scenes {
+		durations = append(durations, float64(s.DurationSeconds))
+	}
+	overlap := 0.0
+	if len(durations) > 1 {
+		overlap = min(transition.Seconds(), slices.Min(durations)/2)
+	}
+
+	start := 0.0
+	for i, s := range storyboard.Scenes {
+		next := start + durations[i] - overlap
+		if i == len(durations)-1 {
+			next = start + durations[i]
+		}
+		if s.Caption != "" {
+			subtitles = append(subtitles, agent.SubtitleEntry{
+				StartTime: start,
+				EndTime:   max(start, next-0.5),
+				Text:      s.Caption,
+			})
+		}
+		start = next
+	}
+	return subtitles
+}
+
+// storyboardDuration はつなげた後の動画の長さ（秒）を返す
+func storyboardDuration(storyboard *agent.Storyboard, transition time.Duration) float64 {
+	durations := make([]float64, 0, len(storyboard.Scenes))
+	for _, s := range storyboard.Scenes {
+		durations = append(durations, float64(s.DurationSeconds))
+	}
+	return video.TotalDuration(durations, transition)
+}
+
+// sceneCaption はシーンに割り当てた分析結果から字幕を作成する
+// キャプション案がない場合は説明文を最大文字数までに切り詰めて使用する
+func sceneCaption(results []agent.MediaAnalysisOutput) string {
+	for _, r := range results {
+		if r.SuggestedCaption != "" {
+			return r.SuggestedCaption
+		}
+	}
+	for _, r := range results {
+		if caption := []rune(r.Description); len(caption) > maxCaptionLength {
+			return string(caption[:maxCaptionLength-3]) + "..."
+		} else if len(caption) > 0 {
+			return r.Description
+		}
+	}
+	return ""
+}
+
+// mediaResults は指定したメディアの分析結果を返す
+func mediaResults(results []agent.MediaAnalysisOutput, mediaIDs []string) []agent.MediaAnalysisOutput {
+	var selected []agent.MediaAnalysisOutput
+	for _, r := range results {
+		if slices.Contains(mediaIDs, r.FileID) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// styleConfig はVlogStyleをプロンプト用のスタイル設定に変換する
+func styleConfig(style agent.VlogStyle) VlogStyleConfig {
+	return VlogStyleConfig{
+		Theme:      style.Theme,
+		MusicMood:  style.MusicMood,
+		Duration:   style.Duration,
+		Transition: style.Transition,
+	}
+}
@@ -0,0 +1,128 @@
+package genkit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanStoryboard(t *testing.T) {
+	results := []agent.MediaAnalysisOutput{
+		{FileID: "media-1", Landmarks: []string{"金閣寺"}, SuggestedCaption: "金色に輝く金閣寺"},
+		{FileID: "media-2", Description: strings.Repeat("あ", 60)},
+		{FileID: "media-3", Activities: []string{"食べ歩き"}},
+	}
+
+	t.Run("分析結果を各シーンに順に割り当てる", func(t *testing.T) {
+		storyboard := planStoryboard(results, agent.VlogStyle{Duration: 24})
+		require.Len(t, storyboard.Scenes, 3)
+		assert.Equal(t, []string{"media-1"}, storyboard.Scenes[0].MediaIDs)
+		assert.Equal(t, []string{"media-3"}, storyboard.Scenes[2].MediaIDs)
+		assert.Contains(t, storyboard.Scenes[0].VeoPrompt, "金閣寺")
+		assert.Equal(t, 8, storyboard.Scenes[1].DurationSeconds)
+	})
+
+	t.Run("キャプション案がない場合は説明文を切り詰めて字幕にする", func(t *testing.T) {
+		storyboard := planStoryboard(results, agent.VlogStyle{Duration: 24})
+		assert.Equal(t, "金色に輝く金閣寺", storyboard.Scenes[0].Caption)
+		assert.Equal(t, strings.Repeat("あ", 47)+"...", storyboard.Scenes[1].Caption)
+		assert.Empty(t, storyboard.Scenes[2].Caption)
+	})
+}
+
+func TestNormalizeStoryboard(t *testing.T) {
+	items := []agent.MediaItem{
+		{FileID: "media-1", Type: "image"},
+		{FileID: "media-2", Type: "image"},
+	}
+	results := []agent.MediaAnalysisOutput{
+		{FileID: "media-2", Landmarks: []string{"清水寺"}},
+	}
+
+	t.Run("長さをVeoが生成できる長さに丸め、存在しないメディアを取り除く", func(t *testing.T) {
+		storyboard := normalizeStoryboard(&agent.Storyboard{Scenes: []agent.StoryboardScene{
+			{MediaIDs: []string{"media-1", "unknown"}, VeoPrompt: " A sunny street. ", DurationSeconds: 5},
+			{MediaIDs: []string{"media-2"}, VeoPrompt: "A temple.", DurationSeconds: 30},
+		}}, items, results, agent.VlogStyle{})
+		require.Len(t, storyboard.Scenes, 2)
+		assert.Equal(t, []string{"media-1"}, storyboard.Scenes[0].MediaIDs)
+		assert.Equal(t, "A sunny street.", storyboard.Scenes[0].VeoPrompt)
+		assert.Equal(t, 6, storyboard.Scenes[0].DurationSeconds)
+		assert.Equal(t, 8, storyboard.Scenes[1].DurationSeconds)
+	})
+
+	t.Run("プロンプトが空のシーンは元にするメディアの分析結果からプロンプトを作成する", func(t *testing.T) {
+		storyboard := normalizeStoryboard(&agent.Storyboard{Scenes: []agent.StoryboardScene{
+			{MediaIDs: []string{"media-2"}},
+		}}, items, results, agent.VlogStyle{})
+		require.Len(t, storyboard.Scenes, 1)
+		assert.Contains(t, storyboard.Scenes[0].VeoPrompt, "清水寺")
+		assert.Equal(t, 8, storyboard.Scenes[0].DurationSeconds)
+	})
+
+	t.Run("シーン数を上限までに切り詰める", func(t *testing.T) {
+		scenes := make([]agent.StoryboardScene, 25)
+		storyboard := normalizeStoryboard(&agent.Storyboard{Scenes: scenes}, items, results, agent.VlogStyle{})
+		assert.Len(t, storyboard.Scenes, 20)
+	})
+
+	t.Run("シーンがない場合はnilを返す", func(t *testing.T) {
+		assert.Nil(t, normalizeStoryboard(nil, items, results, agent.VlogStyle{}))
+		assert.Nil(t, normalizeStoryboard(&agent.Storyboard{}, items, results, agent.VlogStyle{}))
+	})
+}
+
+func TestSubtitlesFromStoryboard(t *testing.T) {
+	storyboard := &agent.Storyboard{Scenes: []agent.StoryboardScene{
+		{Caption: "到着", DurationSeconds: 4},
+		{DurationSeconds: 8},
+		{Caption: "夕暮れ", DurationSeconds: 6},
+	}}
+
+	t.Run("トランジションの重なりを考慮してシーンの開始時刻に字幕を配置する", func(t *testing.T) {
+		subtitles := subtitlesFromStoryboard(storyboard, 500*time.Millisecond)
+		require.Len(t, subtitles, 2)
+		assert.Equal(t, agent.SubtitleEntry{StartTime: 0, EndTime: 3, Text: "到着"}, subtitles[0])
+		assert.Equal(t, agent.SubtitleEntry{StartTime: 11, EndTime: 16.5, Text: "夕暮れ"}, subtitles[1])
+		assert.InDelta(t, 17, storyboardDuration(storyboard, 500*time.Millisecond), 0.001)
+	})
+
+	t.Run("トランジションがない場合はシーンの長さをそのまま使う", func(t *testing.T) {
+		subtitles := subtitlesFromStoryboard(storyboard, 0)
+		require.Len(t, subtitles, 2)
+		assert.Equal(t, agent.SubtitleEntry{StartTime: 12, EndTime: 17.5, Text: "夕暮れ"}, subtitles[1])
+	})
+}
+
+func TestScenesFromStoryboard(t *testing.T) {
+	items := []agent.MediaItem{
+		{FileID: "image-1", Type: "image", URL: "https://example.com/image-1.jpg"},
+		{FileID: "image-2", Type: "image", URL: "https://example.com/image-2.jpg"},
+	}
+	results := []agent.MediaAnalysisOutput{
+		{FileID: "image-1", Landmarks: []string{"tower"}},
+	}
+	storyboard := &agent.Storyboard{Scenes: []agent.StoryboardScene{
+		{MediaIDs: []string{"image-2"}, CameraDirection: "slow pan to the right.", VeoPrompt: "A river at dusk.", DurationSeconds: 4},
+		{VeoPrompt: "A city skyline.", DurationSeconds: 8},
+	}}
+
+	scenes := scenesFromStoryboard(storyboard, items, results, agent.VlogStyle{})
+	require.Len(t, scenes, 2)
+
+	t.Run("シーンの元にするメディアの写真を参考画像にする", func(t *testing.T) {
+		assert.Equal(t, "image-2", scenes[0].ReferenceImage.FileID)
+		assert.Equal(t, "image-1", scenes[1].ReferenceImage.FileID)
+	})
+
+	t.Run("絵コンテのプロンプトにカメラワークの指示を加える", func(t *testing.T) {
+		assert.Contains(t, scenes[0].Prompt, "A river at dusk. Camera: slow pan to the right.")
+		assert.Contains(t, scenes[0].Prompt, "This is scene 1 of 2")
+		assert.Equal(t, int32(4), scenes[0].DurationSeconds)
+		assert.Equal(t, int32(8), scenes[1].DurationSeconds)
+	})
+}
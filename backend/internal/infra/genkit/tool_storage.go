@@ -1,14 +1,19 @@
 package genkit
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/storage"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
 	pkgerrors "github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
-	"github.com/o-ga09/zenn-hackthon-2026/pkg/ulid"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/http"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/sharecode"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
 )
 
 // ============================================================
@@ -80,7 +85,7 @@ func DefineGenerateShareURLTool(g *genkit.Genkit, baseURL string) ai.Tool {
 	return genkit.DefineTool(g, "generateShareURL",
 		"VLogの共有URLを生成する",
 		func(ctx *ai.ToolContext, input GenerateShareURLInput) (GenerateShareURLOutput, error) {
-			shareCode, err := ulid.GenerateULID()
+			shareCode, err := sharecode.Generate()
 			if err != nil {
 				return GenerateShareURLOutput{}, fmt.Errorf("%w: failed to generate share code", pkgerrors.ErrToolExecutionFailed)
 			}
@@ -99,19 +104,30 @@ func DefineGenerateShareURLTool(g *genkit.Genkit, baseURL string) ai.Tool {
 // サムネイル生成ツール
 // ============================================================
 
+// サムネイルの生成元
+const (
+	ThumbnailSourceVideo = "video" // 生成した動画の代表フレーム
+	ThumbnailSourceImage = "image" // アップロードされた写真
+)
+
 // GenerateThumbnailInput はサムネイル生成ツールの入力
 type GenerateThumbnailInput struct {
 	VideoURL string `json:"videoUrl" jsonschema:"description=動画のURL"`
 	VideoID  string `json:"videoId" jsonschema:"description=動画ID"`
-	Width    int    `json:"width,omitempty" jsonschema:"description=サムネイルの幅"`
-	Height   int    `json:"height,omitempty" jsonschema:"description=サムネイルの高さ"`
+	UserID   string `json:"userId" jsonschema:"description=ユーザーID"`
+	// FallbackImageURL は動画からフレームを切り出せない場合に使用する写真のURL
+	FallbackImageURL string `json:"fallbackImageUrl,omitempty" jsonschema:"description=動画から切り出せない場合に使用する写真のURL"`
+	Width            int    `json:"width,omitempty" jsonschema:"description=サムネイルの幅"`
+	Height           int    `json:"height,omitempty" jsonschema:"description=サムネイルの高さ"`
 }
 
 // GenerateThumbnailOutput はサムネイル生成ツールの出力
 type GenerateThumbnailOutput struct {
 	ThumbnailURL string `json:"thumbnailUrl" jsonschema:"description=生成されたサムネイルのURL"`
+	PreviewURL   string `json:"previewUrl,omitempty" jsonschema:"description=アニメーションプレビュー（GIF・WebP）のURL"`
 	Width        int    `json:"width" jsonschema:"description=サムネイルの幅"`
 	Height       int    `json:"height" jsonschema:"description=サムネイルの高さ"`
+	Source       string `json:"source" jsonschema:"description=サムネイルの生成元（video/image）"`
 }
 
 // DefineGenerateThumbnailTool はサムネイル生成ツールを定義する
@@ -123,25 +139,99 @@ func DefineGenerateThumbnailTool(g *genkit.Genkit) ai.Tool {
 			if fc == nil {
 				return GenerateThumbnailOutput{}, pkgerrors.ErrFlowContextNotFound
 			}
-
-			width := input.Width
-			height := input.Height
-			if width == 0 {
-				width = fc.Config.ThumbnailWidth
-			}
-			if height == 0 {
-				height = fc.Config.ThumbnailHeight
+			if fc.Storage == nil {
+				return GenerateThumbnailOutput{}, pkgerrors.ErrStorageNotInitialized
 			}
-
-			// TODO: 実際のサムネイル生成処理を実装
-			thumbnailKey := fmt.Sprintf("thumbnails/%s.jpg", input.VideoID)
-			thumbnailURL := fmt.Sprintf("https://storage.example.com/%s", thumbnailKey)
-
-			return GenerateThumbnailOutput{
-				ThumbnailURL: thumbnailURL,
-				Width:        width,
-				Height:       height,
-			}, nil
+			return generateThumbnail(ctx, fc, video.DefaultTools, input)
 		},
 	)
 }
+
+// generateThumbnail は動画の代表フレームからサムネイルを作成してR2にアップロードする
+// 動画から切り出せない場合はアップロードされた写真から作成する
+func generateThumbnail(ctx context.Context, fc *FlowContext, tools video.Tools, input GenerateThumbnailInput) (GenerateThumbnailOutput, error) {
+	width := input.Width
+	height := input.Height
+	if width == 0 {
+		width = fc.Config.ThumbnailWidth
+	}
+	if height == 0 {
+		height = fc.Config.ThumbnailHeight
+	}
+
+	source := ThumbnailSourceVideo
+	videoData, _, err := http.FetchMediaData(input.VideoURL, "video/mp4")
+	var thumbnail []byte
+	if err == nil {
+		thumbnail, err = tools.ExtractThumbnail(ctx, videoData, width, height)
+	}
+	if err != nil {
+		if input.FallbackImageURL == "" {
+			return GenerateThumbnailOutput{}, fmt.Errorf("%w: failed to extract thumbnail: %v", pkgerrors.ErrToolExecutionFailed, err)
+		}
+		logger.Warn(ctx, fmt.Sprintf("failed to extract thumbnail from video %s, using uploaded image instead: %v", input.VideoID, err))
+		source = ThumbnailSourceImage
+		thumbnail, err = imageThumbnail(ctx, tools, input.FallbackImageURL, width, height)
+		if err != nil {
+			return GenerateThumbnailOutput{}, fmt.Errorf("%w: failed to create thumbnail from image: %v", pkgerrors.ErrToolExecutionFailed, err)
+		}
+	}
+
+	thumbnailKey := fmt.Sprintf("users/%s/vlogs/%s/thumb.jpg", input.UserID, input.VideoID)
+	objectKey, err := fc.Storage.UploadFile(ctx, thumbnailKey, thumbnail, "image/jpeg")
+	if err != nil {
+		return GenerateThumbnailOutput{}, fmt.Errorf("%w: failed to upload thumbnail: %v", pkgerrors.ErrToolExecutionFailed, err)
+	}
+	output := GenerateThumbnailOutput{
+		ThumbnailURL: publicObjectURL(ctx, objectKey),
+		Width:        width,
+		Height:       height,
+		Source:       source,
+	}
+
+	// アニメーションプレビューは設定されている場合のみ作成し、失敗しても続行する
+	format, ok := video.ParsePreviewFormat(fc.Config.ThumbnailPreviewFormat)
+	if ok && source == ThumbnailSourceVideo {
+		previewURL, err := uploadPreview(ctx, fc, tools, videoData, format, input)
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to create preview for video %s: %v", input.VideoID, err))
+		}
+		output.PreviewURL = previewURL
+	}
+	return output, nil
+}
+
+// imageThumbnail は写真をサムネイルのサイズに切り抜く
+// ffmpegで変換できない場合、JPEGの写真はそのまま使用する
+func imageThumbnail(ctx context.Context, tools video.Tools, url string, width, height int) ([]byte, error) {
+	data, contentType, err := http.FetchMediaData(url, "")
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := tools.ExtractThumbnail(ctx, data, width, height)
+	if err != nil {
+		if contentType == "image/jpeg" {
+			return data, nil
+		}
+		return nil, err
+	}
+	return thumbnail, nil
+}
+
+// uploadPreview はアニメーションプレビューを作成してR2にアップロードする
+func uploadPreview(ctx context.Context, fc *FlowContext, tools video.Tools, videoData []byte, format video.PreviewFormat, input GenerateThumbnailInput) (string, error) {
+	preview, err := tools.RenderPreview(ctx, videoData, video.PreviewOptions{
+		Format:   format,
+		Width:    fc.Config.ThumbnailPreviewWidth,
+		Duration: time.Duration(fc.Config.ThumbnailPreviewSeconds * float64(time.Second)),
+	})
+	if err != nil {
+		return "", err
+	}
+	previewKey := fmt.Sprintf("users/%s/vlogs/%s/preview.%s", input.UserID, input.VideoID, format)
+	objectKey, err := fc.Storage.UploadFile(ctx, previewKey, preview, format.ContentType())
+	if err != nil {
+		return "", err
+	}
+	return publicObjectURL(ctx, objectKey), nil
+}
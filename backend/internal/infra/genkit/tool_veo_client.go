@@ -10,144 +10,235 @@ import (
 	"cloud.google.com/go/storage"
 	"google.golang.org/genai"
 
-	pkgStorage "github.com/o-ga09/zenn-hackthon-2026/internal/infra/storage"
-	pkgConfig "github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
 	pkgerrors "github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/http"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/ulid"
 )
 
-// VeoGenerateConfig はVeo動画生成の設定
-type VeoGenerateConfig struct {
-	Prompt          string
-	DurationSeconds int32
-	AspectRatio     string // "16:9" or "9:16"
-	UserID          string
+// veoCheckpoint はVeoリクエストのステップ（シーンごと）のチェックポイント
+type veoCheckpoint struct {
+	VideoID       string `json:"video_id"`                 // クリップのID（GCS一時出力パスに使用）
+	OperationName string `json:"operation_name,omitempty"` // 開始したオペレーションの名前
+	GCSURI        string `json:"gcs_uri,omitempty"`        // 生成された動画のGCS URI（完了時のみ）
 }
 
-// VeoGenerateResult はVeo動画生成の結果
-type VeoGenerateResult struct {
-	VideoID  string
-	VideoURL string // R2のURL
-	Duration float64
+// VeoVideoGenerator はVeoでシーンのクリップを生成するagent.IVideoGeneratorの実装
+// 生成した動画はGCSの一時バケットに出力し、ダウンロードしてから返す
+type VeoVideoGenerator struct {
+	genAI  *genai.Client
+	gcs    *storage.Client
+	config *FlowConfig
 }
 
-// GenerateVideoWithVeo はVeo3を使用して動画を生成し、R2にアップロードする
-func GenerateVideoWithVeo(ctx context.Context, fc *FlowContext, config VeoGenerateConfig) (*VeoGenerateResult, error) {
-	if fc.GenAI == nil {
+// NewVeoVideoGenerator は新しいVeoVideoGeneratorを作成する
+func NewVeoVideoGenerator(genAI *genai.Client, gcs *storage.Client, config *FlowConfig) *VeoVideoGenerator {
+	return &VeoVideoGenerator{genAI: genAI, gcs: gcs, config: config}
+}
+
+// GenerateClip はシーンのクリップをVeoで生成し、GCSからダウンロードする
+// 前回の実行で開始済みのオペレーションがある場合は、新たに生成せず名前で再ポーリングする
+func (g *VeoVideoGenerator) GenerateClip(ctx context.Context, req agent.VideoClipRequest, onProgress func(fraction float64)) (*agent.VideoClip, error) {
+	if g.genAI == nil {
 		return nil, fmt.Errorf("%w: GenAI client not initialized", pkgerrors.ErrGenkitNotInitialized)
 	}
-	if fc.GCSClient == nil {
+	if g.gcs == nil {
 		return nil, fmt.Errorf("%w: GCS client not initialized", pkgerrors.ErrGenkitNotInitialized)
 	}
-	if fc.Storage == nil {
-		return nil, pkgerrors.ErrStorageNotInitialized
-	}
 
-	// 動画IDを生成
-	videoID, err := ulid.GenerateULID()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate video ID: %w", err)
+	var veo veoCheckpoint
+	agent.LoadCheckpoint(ctx, agent.StepNameVeoRequest, req.SceneIndex, &veo)
+	if veo.VideoID == "" {
+		clipID, err := ulid.GenerateULID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate clip ID: %w", err)
+		}
+		veo = veoCheckpoint{VideoID: clipID}
 	}
 
-	// GCS一時出力パスを設定
-	gcsOutputPath := fmt.Sprintf("gs://%s/temp/%s/", fc.Config.GCSTempBucket, videoID)
-
-	// デフォルト値設定
-	duration := config.DurationSeconds
+	duration := req.DurationSeconds
 	if duration == 0 {
-		duration = 8
+		duration = constant.VeoClipMaxSeconds
 	}
-	aspectRatio := config.AspectRatio
+	aspectRatio := req.AspectRatio
 	if aspectRatio == "" {
 		aspectRatio = "16:9"
 	}
 
-	// Veo動画生成オペレーションを開始
-	op, err := fc.GenAI.Models.GenerateVideos(ctx,
-		fc.Config.VeoModel,
-		config.Prompt,
-		nil, // 画像入力なし（テキストのみ）
-		&genai.GenerateVideosConfig{
-			DurationSeconds:  genai.Ptr(duration),
-			AspectRatio:      aspectRatio,
-			Resolution:       "720p",
-			NumberOfVideos:   1,
-			OutputGCSURI:     gcsOutputPath,
-			GenerateAudio:    genai.Ptr(true),
-			PersonGeneration: "allow_adult",
-		},
-	)
+	if veo.GCSURI == "" {
+		err := agent.RunStep(ctx, agent.StepNameVeoRequest, req.SceneIndex, func() error {
+			return g.runOperation(ctx, veoRequest{
+				Item:           req.SceneIndex,
+				Prompt:         req.Prompt,
+				ReferenceImage: req.ReferenceImage,
+				ReferenceMode:  req.ReferenceMode,
+				Config: &genai.GenerateVideosConfig{
+					DurationSeconds:  genai.Ptr(duration),
+					AspectRatio:      aspectRatio,
+					Resolution:       "720p",
+					NumberOfVideos:   1,
+					OutputGCSURI:     fmt.Sprintf("gs://%s/temp/%s/", g.config.GCSTempBucket, veo.VideoID),
+					GenerateAudio:    genai.Ptr(true),
+					PersonGeneration: "allow_adult",
+				},
+			}, &veo, onProgress)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// GCSから動画データを取得
+	var data []byte
+	err := agent.RunStep(ctx, agent.StepNameGCSDownload, req.SceneIndex, func() error {
+		var err error
+		data, err = downloadFromGCS(ctx, g.gcs, veo.GCSURI)
+		if err != nil {
+			return fmt.Errorf("failed to download video from GCS: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to start video generation: %w", err)
+		return nil, err
 	}
+	return &agent.VideoClip{
+		SceneIndex:      req.SceneIndex,
+		Data:            data,
+		DurationSeconds: float64(duration),
+		SourceURI:       veo.GCSURI,
+	}, nil
+}
 
-	// オペレーション完了を待機（バックグラウンドコンテキストを使用してHTTPタイムアウトを回避）
-	maxWait := time.Duration(fc.Config.VeoMaxWaitTime) * time.Second
-	pollInterval := time.Duration(fc.Config.VeoPollingInterval) * time.Second
-	startTime := time.Now()
+// ReleaseClip はGCSの一時ファイルを削除する（削除失敗はログのみ）
+func (g *VeoVideoGenerator) ReleaseClip(ctx context.Context, clip *agent.VideoClip) {
+	if clip.SourceURI == "" || g.gcs == nil {
+		return
+	}
+	if err := deleteFromGCS(ctx, g.gcs, clip.SourceURI); err != nil {
+		logger.Warn(ctx, "failed to delete temp file from GCS", "uri", clip.SourceURI, "error", err)
+	}
+}
 
-	// Veoポーリング用に独立したコンテキストを使用
-	veoCtx := context.Background()
+// veoRequest はVeo動画生成オペレーションの入力
+type veoRequest struct {
+	Item           int // チェックポイントのシーン番号
+	Prompt         string
+	ReferenceImage *agent.ReferenceImage
+	ReferenceMode  string
+	Config         *genai.GenerateVideosConfig
+}
+
+// imageInput は写真の使い方に応じて、写真を最初のフレーム（animate）または参考画像（inspired）としてVeoの入力に設定する
+// 写真を取得できない場合はテキストのみで生成する
+func (r veoRequest) imageInput(ctx context.Context) *genai.Image {
+	if r.ReferenceImage == nil {
+		return nil
+	}
+	data, contentType, err := http.FetchMediaData(r.ReferenceImage.URL, r.ReferenceImage.ContentType)
+	if err != nil {
+		logger.Warn(ctx, "failed to fetch reference image, generating from text only", "file_id", r.ReferenceImage.FileID, "error", err)
+		return nil
+	}
+	if r.ReferenceImage.ContentType != "" {
+		contentType = r.ReferenceImage.ContentType
+	}
+	image := &genai.Image{ImageBytes: data, MIMEType: contentType}
+	if r.ReferenceMode == agent.ReferenceModeAnimate {
+		return image
+	}
+	r.Config.ReferenceImages = []*genai.VideoGenerationReferenceImage{{
+		Image:         image,
+		ReferenceType: genai.VideoGenerationReferenceTypeAsset,
+	}}
+	return nil
+}
+
+// runOperation はVeo動画生成オペレーションを開始し、完了まで待機する
+// 開始したオペレーションの名前と生成された動画のGCS URIはチェックポイントに保存し、
+// チェックポイントにオペレーションの名前がある場合は新たに開始せずに再ポーリングする
+// Veoは進捗を返さないため、最大待機時間に対する経過時間をonPollに通知する
+func (g *VeoVideoGenerator) runOperation(ctx context.Context, req veoRequest, checkpoint *veoCheckpoint, onPoll func(fraction float64)) error {
+	var op *genai.GenerateVideosOperation
+	if checkpoint.OperationName != "" {
+		var err error
+		op, err = g.genAI.Operations.GetVideosOperation(ctx, &genai.GenerateVideosOperation{Name: checkpoint.OperationName}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to resume video generation: %w", err)
+		}
+		// 前回のオペレーションが失敗していた場合は新たに生成する
+		if op.Done && op.Error != nil {
+			logger.Warn(ctx, "previous veo operation failed, starting a new one", "operation", checkpoint.OperationName, "error", op.Error)
+			op = nil
+		}
+	}
+	if op == nil {
+		var err error
+		op, err = g.genAI.Models.GenerateVideos(ctx,
+			g.config.VeoModel,
+			req.Prompt,
+			req.imageInput(ctx),
+			req.Config,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to start video generation: %w", err)
+		}
+		checkpoint.OperationName = op.Name
+		agent.SaveCheckpoint(ctx, agent.StepNameVeoRequest, req.Item, checkpoint)
+	}
+
+	// オペレーション完了を待機
+	// VLogのキャンセルなどでコンテキストがキャンセルされた場合は次の確認時点で待機を中断する
+	maxWait := time.Duration(g.config.VeoMaxWaitTime) * time.Second
+	pollInterval := time.Duration(g.config.VeoPollingInterval) * time.Second
+	startTime := time.Now()
 
 	for !op.Done {
 		if time.Since(startTime) > maxWait {
-			return nil, fmt.Errorf("video generation timed out after %v", maxWait)
+			return fmt.Errorf("video generation timed out after %v", maxWait)
 		}
-		time.Sleep(pollInterval)
-		op, err = fc.GenAI.Operations.GetVideosOperation(veoCtx, op, nil)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("video generation aborted: %w", context.Cause(ctx))
+		case <-time.After(pollInterval):
+		}
+		var err error
+		op, err = g.genAI.Operations.GetVideosOperation(ctx, op, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get operation status: %w", err)
+			if ctx.Err() != nil {
+				return fmt.Errorf("video generation aborted: %w", context.Cause(ctx))
+			}
+			return fmt.Errorf("failed to get operation status: %w", err)
 		}
+		onPoll(float64(time.Since(startTime)) / float64(maxWait))
 	}
 
 	// オペレーション全体をデバッグ出力
 	fmt.Printf("[Veo] Operation Name: %s\n", op.Name)
+
+	// 動画が生成されなかった場合、再実行時は新たに生成するようオペレーションの名前を破棄する
+	if op.Error != nil || op.Response == nil || len(op.Response.GeneratedVideos) == 0 {
+		checkpoint.OperationName = ""
+		agent.SaveCheckpoint(ctx, agent.StepNameVeoRequest, req.Item, checkpoint)
+	}
 	if op.Error != nil {
-		return nil, fmt.Errorf("veo operation error: error=%v", op.Error)
+		return fmt.Errorf("veo operation error: error=%v", op.Error)
 	}
 
 	// エラーチェック
 	if op.Response == nil {
-		return nil, fmt.Errorf("no response from Veo API")
+		return fmt.Errorf("no response from Veo API")
 	}
 
 	if len(op.Response.GeneratedVideos) == 0 {
 		// エラー詳細を出力
-		return nil, fmt.Errorf("no video generated (response empty)")
+		return fmt.Errorf("no video generated (response empty)")
 	}
 
-	generatedVideo := op.Response.GeneratedVideos[0]
-	gcsVideoURI := generatedVideo.Video.URI
-
-	// GCSから動画データを取得
-	videoData, err := downloadFromGCS(ctx, fc.GCSClient, gcsVideoURI)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download video from GCS: %w", err)
-	}
-
-	// R2にアップロード
-	r2Key := fmt.Sprintf("users/%s/vlogs/%s.mp4", config.UserID, videoID)
-	objectKey, err := fc.Storage.UploadFile(ctx, r2Key, videoData, "video/mp4")
-	if err != nil {
-		return nil, fmt.Errorf("failed to upload video to R2: %w", err)
-	}
-
-	// GCS一時ファイルを削除
-	if err := deleteFromGCS(ctx, fc.GCSClient, gcsVideoURI); err != nil {
-		// 削除失敗はログのみ（致命的ではない）
-		fmt.Printf("warning: failed to delete temp file from GCS: %v\n", err)
-	}
-
-	env := pkgConfig.GetCtxEnv(ctx)
-	url := pkgStorage.ObjectURKFromKey(env.CLOUDFLARE_R2_PUBLIC_URL, objectKey)
-	if env.Env == "local" {
-		url = fmt.Sprintf("%s/%s/%s", env.CLOUDFLARE_R2_PUBLIC_URL, env.CLOUDFLARE_R2_BUCKET_NAME, objectKey)
-	}
-	return &VeoGenerateResult{
-		VideoID:  videoID,
-		VideoURL: url,
-		Duration: float64(duration),
-	}, nil
+	checkpoint.GCSURI = op.Response.GeneratedVideos[0].Video.URI
+	agent.SaveCheckpoint(ctx, agent.StepNameVeoRequest, req.Item, checkpoint)
+	return nil
 }
 
 // downloadFromGCS はGCSからファイルをダウンロードする
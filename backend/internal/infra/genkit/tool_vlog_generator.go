@@ -1,7 +1,9 @@
 package genkit
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
@@ -9,25 +11,26 @@ import (
 	pkgerrors "github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
 )
 
-const defaultShortDuration = 15.0 // デフォルトの短い動画の長さ（秒）
-
 // GenerateVlogVideoInput はVLog動画生成ツールの入力
 type GenerateVlogVideoInput struct {
 	AnalysisResults []agent.MediaAnalysisOutput `json:"analysisResults,omitempty" jsonschema:"description=メディア分析結果のリスト"`
 	Style           agent.VlogStyle             `json:"style,omitempty" jsonschema:"description=VLogのスタイル設定"`
 	Title           string                      `json:"title,omitempty" jsonschema:"description=VLogのタイトル"`
+	Description     string                      `json:"description,omitempty" jsonschema:"description=VLogの説明文（タイトルを指定した場合のみ使用）"`
 	MediaItems      []agent.MediaItem           `json:"mediaItems,omitempty" jsonschema:"description=元のメディアアイテム"`
 	UserID          string                      `json:"userId,omitempty" jsonschema:"description=ユーザーID"`
+	Storyboard      *agent.Storyboard           `json:"storyboard,omitempty" jsonschema:"description=シーンの構成（絵コンテ）"`
 }
 
 // GenerateVlogVideoOutput はVLog動画生成ツールの出力
 type GenerateVlogVideoOutput struct {
-	VideoURL    string                `json:"videoUrl" jsonschema:"description=生成された動画のURL"`
-	VideoID     string                `json:"videoId" jsonschema:"description=動画ID"`
-	Duration    float64               `json:"duration" jsonschema:"description=動画の長さ（秒）"`
-	Title       string                `json:"title" jsonschema:"description=生成されたタイトル"`
-	Description string                `json:"description" jsonschema:"description=生成された説明文"`
-	Subtitles   []agent.SubtitleEntry `json:"subtitles" jsonschema:"description=字幕データ"`
+	VideoURL     string                `json:"videoUrl" jsonschema:"description=生成された動画のURL"`
+	VideoID      string                `json:"videoId" jsonschema:"description=動画ID"`
+	Duration     float64               `json:"duration" jsonschema:"description=動画の長さ（秒）"`
+	Title        string                `json:"title" jsonschema:"description=生成されたタイトル"`
+	Description  string                `json:"description" jsonschema:"description=生成された説明文"`
+	Subtitles    []agent.SubtitleEntry `json:"subtitles" jsonschema:"description=字幕データ"`
+	MusicTrackID string                `json:"musicTrackId,omitempty" jsonschema:"description=BGMに使用した曲のID"`
 }
 
 // DefineGenerateVlogVideoTool はVLog動画生成ツールを定義する
@@ -41,41 +44,18 @@ func DefineGenerateVlogVideoTool(g *genkit.Genkit) ai.Tool {
 			}
 
 			// タイトルと説明文を生成
-			title := input.Title
-			description := ""
-			if title == "" {
-				generated, err := generateTitleAndDescription(ctx, fc.Genkit, input.AnalysisResults)
-				if err != nil {
-					// タイトル生成失敗時はデフォルトを使用
-					title = "Travel Vlog"
-					description = ""
-				} else {
-					title = generated.Title
-					description = generated.Description
-				}
-			}
+			title, description := resolveTitle(ctx, fc.Genkit, input.Title, input.Description, input.AnalysisResults)
 
-			// 字幕を生成
-			subtitles := generateSubtitles(input.AnalysisResults, input.Style)
-
-			// 分析結果からプロンプト用サマリーを構築
-			summaries := make([]MediaAnalysisSummary, 0, len(input.AnalysisResults))
-			for _, r := range input.AnalysisResults {
-				summaries = append(summaries, MediaAnalysisSummary{
-					Description: r.Description,
-					Landmarks:   r.Landmarks,
-					Activities:  r.Activities,
-					Mood:        r.Mood,
-				})
+			// 絵コンテがない場合は分析結果を各シーンに順に割り当てた絵コンテを使用する
+			storyboard := input.Storyboard
+			if storyboard == nil {
+				storyboard = planStoryboard(input.AnalysisResults, input.Style)
 			}
 
-			// Veo用プロンプトを構築
-			veoPrompt := BuildVlogPrompt(summaries, VlogStyleConfig{
-				Theme:      input.Style.Theme,
-				MusicMood:  input.Style.MusicMood,
-				Duration:   input.Style.Duration,
-				Transition: input.Style.Transition,
-			})
+			// 絵コンテのキャプションから字幕を作成
+			transition := time.Duration(fc.Config.SceneTransitionSeconds * float64(time.Second))
+			subtitles := subtitlesFromStoryboard(storyboard, transition)
+			duration := storyboardDuration(storyboard, transition)
 
 			// UserIDを取得
 			userID := input.UserID
@@ -83,30 +63,35 @@ func DefineGenerateVlogVideoTool(g *genkit.Genkit) ai.Tool {
 				userID = "anonymous"
 			}
 
-			// Veo3で動画生成（サポートされる長さ: 4, 6, 8秒のみ）
-			duration := int32(input.Style.Duration)
-			// Veo 3.1がサポートするのは 4, 6, 8 秒のみ
-			if duration != 4 && duration != 6 && duration != 8 {
-				duration = 8 // デフォルトは8秒
+			// 絵コンテのシーンごとに生成したクリップをつなげる
+			config := VideoGenerateConfig{
+				AspectRatio:   "16:9",
+				UserID:        userID,
+				Scenes:        scenesFromStoryboard(storyboard, input.MediaItems, input.AnalysisResults, input.Style),
+				Transition:    input.Style.Transition,
+				ReferenceMode: input.Style.ReferenceMode,
 			}
-
-			veoResult, err := GenerateVideoWithVeo(ctx, fc, VeoGenerateConfig{
-				Prompt:          veoPrompt,
-				DurationSeconds: duration,
-				AspectRatio:     "16:9",
-				UserID:          userID,
-			})
+			if input.Style.SubtitleMode == agent.SubtitleModeBurned {
+				config.BurnedSubtitles = subtitles
+			}
+			config.Music = selectMusicTrack(ctx, fc.MusicTrackRepo, input.Style, input.AnalysisResults, duration)
+			if input.Style.Narration != nil {
+				config.Narration = buildNarrationScript(subtitles, input.AnalysisResults, duration)
+				config.NarrationStyle = *input.Style.Narration
+			}
+			videoResult, err := GenerateVideo(ctx, fc, config)
 			if err != nil {
-				return GenerateVlogVideoOutput{}, fmt.Errorf("veo generation failed: %w", err)
+				return GenerateVlogVideoOutput{}, fmt.Errorf("video generation failed: %w", err)
 			}
 
 			return GenerateVlogVideoOutput{
-				VideoURL:    veoResult.VideoURL,
-				VideoID:     veoResult.VideoID,
-				Duration:    veoResult.Duration,
-				Title:       title,
-				Description: description,
-				Subtitles:   subtitles,
+				VideoURL:     videoResult.VideoURL,
+				VideoID:      videoResult.VideoID,
+				Duration:     videoResult.Duration,
+				Title:        title,
+				Description:  description,
+				Subtitles:    subtitles,
+				MusicTrackID: videoResult.MusicTrackID,
 			}, nil
 		},
 	)
@@ -125,7 +110,34 @@ type TitlePromptInput struct {
 	Moods      []string `json:"moods"`
 }
 
-func generateTitleAndDescription(ctx *ai.ToolContext, g *genkit.Genkit, results []agent.MediaAnalysisOutput) (*TitleDescription, error) {
+// resolveTitle はVLogのタイトルと説明文を返す
+// タイトルが指定されていない場合は分析結果から生成する（前回の実行で生成済みの場合はチェックポイントから再開する）
+func resolveTitle(ctx context.Context, g *genkit.Genkit, title, description string, results []agent.MediaAnalysisOutput) (string, string) {
+	if title != "" {
+		return title, description
+	}
+
+	var generated *TitleDescription
+	var err error
+	if !agent.LoadCheckpoint(ctx, agent.StepNameTitleGeneration, 0, &generated) || generated == nil {
+		err = agent.RunStep(ctx, agent.StepNameTitleGeneration, 0, func() error {
+			var err error
+			generated, err = generateTitleAndDescription(ctx, g, results)
+			if err != nil {
+				return err
+			}
+			agent.SaveCheckpoint(ctx, agent.StepNameTitleGeneration, 0, generated)
+			return nil
+		})
+	}
+	if err != nil {
+		// タイトル生成失敗時はデフォルトを使用
+		return "Travel Vlog", ""
+	}
+	return generated.Title, generated.Description
+}
+
+func generateTitleAndDescription(ctx context.Context, g *genkit.Genkit, results []agent.MediaAnalysisOutput) (*TitleDescription, error) {
 	var locations, activities, moods []string
 	for _, r := range results {
 		locations = append(locations, r.Landmarks...)
@@ -157,38 +169,3 @@ func generateTitleAndDescription(ctx *ai.ToolContext, g *genkit.Genkit, results
 
 	return &result, nil
 }
-
-func generateSubtitles(results []agent.MediaAnalysisOutput, style agent.VlogStyle) []agent.SubtitleEntry {
-	subtitles := make([]agent.SubtitleEntry, 0, len(results))
-	duration := float64(style.Duration)
-	if duration == 0 {
-		duration = defaultShortDuration
-	}
-
-	if len(results) == 0 {
-		return subtitles
-	}
-
-	timePerMedia := duration / float64(len(results))
-
-	for i, result := range results {
-		startTime := float64(i) * timePerMedia
-		endTime := startTime + timePerMedia - 0.5
-
-		caption := result.SuggestedCaption
-		if caption == "" {
-			caption = result.Description
-			if len(caption) > 50 {
-				caption = caption[:47] + "..."
-			}
-		}
-
-		subtitles = append(subtitles, agent.SubtitleEntry{
-			StartTime: startTime,
-			EndTime:   endTime,
-			Text:      caption,
-		})
-	}
-
-	return subtitles
-}
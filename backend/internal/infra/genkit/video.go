@@ -0,0 +1,248 @@
+package genkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	pkgStorage "github.com/o-ga09/zenn-hackthon-2026/internal/infra/storage"
+	pkgConfig "github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	pkgerrors "github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/http"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/subtitle"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/ulid"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
+)
+
+// VideoGenerateConfig はVLog動画生成の設定
+type VideoGenerateConfig struct {
+	Prompt          string
+	DurationSeconds int32
+	AspectRatio     string // "16:9" or "9:16"
+	UserID          string
+	// ReferenceImage は動画生成に渡すユーザーの写真（Scenes未指定の場合のみ使用）
+	ReferenceImage *agent.ReferenceImage
+	// ReferenceMode は写真の使い方（inspired: 参考画像 / animate: 最初のフレーム）
+	ReferenceMode string
+	// Scenes はシーンごとのクリップの設定（未指定の場合はPrompt・DurationSeconds・ReferenceImageの1シーン）
+	Scenes []Scene
+	// Transition はシーン間のトランジション効果（fade/slide/zoom）
+	Transition string
+	// BurnedSubtitles は動画に焼き込む字幕（空の場合は焼き込まない）
+	BurnedSubtitles []agent.SubtitleEntry
+	// Narration は読み上げるナレーションの台本（空の場合はナレーションを付けない）
+	Narration []agent.SubtitleEntry
+	// NarrationStyle はナレーションの声の設定
+	NarrationStyle agent.NarrationStyle
+	// Music はBGMに使用する曲（nilの場合はBGMを付けない）
+	Music *domain.MusicTrack
+}
+
+// VideoGenerateResult はVLog動画生成の結果
+type VideoGenerateResult struct {
+	VideoID  string
+	VideoURL string // R2のURL
+	Duration float64
+	// MusicTrackID はBGMに使用した曲のID（BGMなしの場合は空）
+	MusicTrackID string
+}
+
+// GenerateVideo はFlowContextの動画生成バックエンドでシーンごとのクリップを生成し、トランジションでつなげてR2にアップロードする
+func GenerateVideo(ctx context.Context, fc *FlowContext, config VideoGenerateConfig) (*VideoGenerateResult, error) {
+	if fc.VideoGenerator == nil {
+		return nil, fmt.Errorf("%w: video generator not initialized", pkgerrors.ErrGenkitNotInitialized)
+	}
+	if fc.Storage == nil {
+		return nil, pkgerrors.ErrStorageNotInitialized
+	}
+
+	// 前回の実行でR2へのアップロードまで完了している場合は、アップロード済みの動画をそのまま使用する
+	var uploaded r2UploadCheckpoint
+	if agent.LoadCheckpoint(ctx, agent.StepNameR2Upload, 0, &uploaded) && uploaded.ObjectKey != "" {
+		result := newVideoGenerateResult(ctx, uploaded.VideoID, uploaded.ObjectKey, uploaded.Duration)
+		result.MusicTrackID = uploaded.MusicTrackID
+		return result, nil
+	}
+
+	scenes := config.Scenes
+	if len(scenes) == 0 {
+		scenes = []Scene{{Index: 1, Prompt: config.Prompt, DurationSeconds: config.DurationSeconds, ReferenceImage: config.ReferenceImage}}
+	}
+	aspectRatio := config.AspectRatio
+	if aspectRatio == "" {
+		aspectRatio = "16:9"
+	}
+	requests := make([]agent.VideoClipRequest, 0, len(scenes))
+	for _, scene := range scenes {
+		requests = append(requests, agent.VideoClipRequest{
+			SceneIndex:      scene.Index,
+			Prompt:          scene.Prompt,
+			DurationSeconds: scene.DurationSeconds,
+			AspectRatio:     aspectRatio,
+			ReferenceImage:  scene.ReferenceImage,
+			ReferenceMode:   config.ReferenceMode,
+		})
+	}
+
+	// シーンごとのクリップを並行して生成する
+	clips, err := generateSceneClips(ctx, fc.VideoGenerator, requests, fc.Config.VeoMaxConcurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	// クリップをトランジションでつなげる（1シーンの場合はそのまま使用する）
+	videoData := clips[0].Data
+	duration := clips[0].DurationSeconds
+	if len(clips) > 1 {
+		err = agent.RunStep(ctx, agent.StepNameConcatenate, 0, func() error {
+			var err error
+			transitionDuration := time.Duration(fc.Config.SceneTransitionSeconds * float64(time.Second))
+			videoData, duration, err = concatSceneClips(ctx, video.DefaultTools, clips, video.ParseTransition(config.Transition), transitionDuration)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ナレーションを合成して動画の音声に重ねる（失敗した場合はナレーションなしの動画をアップロードする）
+	if len(config.Narration) > 0 && fc.SpeechSynthesizer != nil {
+		err = agent.RunStep(ctx, agent.StepNameNarration, 0, func() error {
+			narration, err := synthesizeNarration(ctx, fc.SpeechSynthesizer, config.Narration, config.NarrationStyle, duration)
+			if err != nil {
+				return err
+			}
+			mixed, err := video.DefaultTools.MixNarration(ctx, videoData, narration, video.MixOptions{
+				BackgroundVolume: fc.Config.NarrationBackgroundVolume,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to mix narration: %w", err)
+			}
+			videoData = mixed
+			return nil
+		})
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("narration failed: %v", err))
+		}
+	}
+
+	// BGMを重ね、動画の音声（Veoの音声・ナレーション）の間は音量を下げる（失敗した場合はBGMなしの動画をアップロードする）
+	var musicTrackID string
+	if config.Music != nil {
+		err = agent.RunStep(ctx, agent.StepNameBackgroundMusic, 0, func() error {
+			music, _, err := http.FetchMediaData(publicObjectURL(ctx, config.Music.ObjectKey), config.Music.ContentType)
+			if err != nil {
+				return err
+			}
+			mixed, err := video.DefaultTools.MixMusic(ctx, videoData, music, video.MusicOptions{
+				Volume:         fc.Config.MusicVolume,
+				FadeOutSeconds: fc.Config.MusicFadeOutSeconds,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to mix music: %w", err)
+			}
+			videoData = mixed
+			musicTrackID = config.Music.ID
+			return nil
+		})
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("background music failed: %v", err))
+		}
+	}
+
+	// 字幕を焼き込む（失敗した場合は字幕なしの動画をアップロードし、字幕ファイルで表示する）
+	if len(config.BurnedSubtitles) > 0 {
+		err = agent.RunStep(ctx, agent.StepNameBurnSubtitles, 0, func() error {
+			burned, err := burnSubtitles(ctx, video.DefaultTools, videoData, config.BurnedSubtitles, video.SubtitleStyle{
+				FontName: fc.Config.SubtitleFontName,
+				FontSize: fc.Config.SubtitleFontSize,
+			})
+			if err != nil {
+				return err
+			}
+			videoData = burned
+			return nil
+		})
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("subtitle burn-in failed: %v", err))
+		}
+	}
+
+	// 動画IDを生成
+	videoID, err := ulid.GenerateULID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate video ID: %w", err)
+	}
+
+	// R2にアップロード
+	agent.ReportProgress(ctx, agent.FlowProgress{
+		Step:     string(agent.StepUploadingVideo),
+		Progress: progressUploadingVideo,
+		Message:  "動画をアップロードしています...",
+	})
+	r2Key := fmt.Sprintf("users/%s/vlogs/%s.mp4", config.UserID, videoID)
+	var objectKey string
+	err = agent.RunStep(ctx, agent.StepNameR2Upload, 0, func() error {
+		var err error
+		objectKey, err = fc.Storage.UploadFile(ctx, r2Key, videoData, "video/mp4")
+		if err != nil {
+			return fmt.Errorf("failed to upload video to R2: %w", err)
+		}
+		agent.SaveCheckpoint(ctx, agent.StepNameR2Upload, 0, r2UploadCheckpoint{VideoID: videoID, ObjectKey: objectKey, Duration: duration, MusicTrackID: musicTrackID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 一時ファイルを削除
+	for _, clip := range clips {
+		fc.VideoGenerator.ReleaseClip(ctx, clip)
+	}
+
+	result := newVideoGenerateResult(ctx, videoID, objectKey, duration)
+	result.MusicTrackID = musicTrackID
+	return result, nil
+}
+
+// burnSubtitles は字幕をSRT形式にして動画に焼き込む
+func burnSubtitles(ctx context.Context, tools video.Tools, data []byte, entries []agent.SubtitleEntry, style video.SubtitleStyle) ([]byte, error) {
+	cues := make([]subtitle.Cue, 0, len(entries))
+	for _, entry := range entries {
+		cues = append(cues, subtitle.Cue{Start: entry.StartTime, End: entry.EndTime, Text: entry.Text})
+	}
+	return tools.BurnSubtitles(ctx, data, subtitle.EncodeSRT(cues), style)
+}
+
+// r2UploadCheckpoint はR2へのアップロードのステップのチェックポイント
+type r2UploadCheckpoint struct {
+	VideoID      string  `json:"video_id"`
+	ObjectKey    string  `json:"object_key"`
+	Duration     float64 `json:"duration,omitempty"`
+	MusicTrackID string  `json:"music_track_id,omitempty"`
+}
+
+// newVideoGenerateResult はR2にアップロードした動画から結果を作成する
+func newVideoGenerateResult(ctx context.Context, videoID, objectKey string, duration float64) *VideoGenerateResult {
+	if duration == 0 {
+		duration = constant.VeoClipMaxSeconds
+	}
+	return &VideoGenerateResult{
+		VideoID:  videoID,
+		VideoURL: publicObjectURL(ctx, objectKey),
+		Duration: duration,
+	}
+}
+
+// publicObjectURL はR2にアップロードしたオブジェクトの公開URLを返す
+func publicObjectURL(ctx context.Context, objectKey string) string {
+	env := pkgConfig.GetCtxEnv(ctx)
+	if env.Env == "local" {
+		return fmt.Sprintf("%s/%s/%s", env.CLOUDFLARE_R2_PUBLIC_URL, env.CLOUDFLARE_R2_BUCKET_NAME, objectKey)
+	}
+	return pkgStorage.ObjectURKFromKey(env.CLOUDFLARE_R2_PUBLIC_URL, objectKey)
+}
@@ -0,0 +1,144 @@
+package localqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+)
+
+var ErrQueueClosed = errors.New("local queue is closed")
+
+type job struct {
+	ctx  context.Context
+	task *queue.Task
+}
+
+// LocalQueue はプロセス内のワーカープールでタスクを実行するキュー
+// ローカル開発やGCPに依存しない結合テストで利用する
+type LocalQueue struct {
+	jobs     chan *job
+	registry *queue.Registry
+
+	mu     sync.RWMutex
+	closed bool
+
+	wg    sync.WaitGroup
+	stop  context.Context // Shutdownのタイムアウト時に実行中のタスクを中断する
+	abort context.CancelFunc
+}
+
+// New はworkers個のワーカーと最大capacity件のバッファを持つキューを作成し、ワーカーを起動する
+// タスクはregistryに登録された処理関数・リトライ設定で実行する
+func New(registry *queue.Registry, workers, capacity int) *LocalQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	stop, abort := context.WithCancel(context.Background())
+	q := &LocalQueue{
+		jobs:     make(chan *job, capacity),
+		registry: registry,
+		stop:     stop,
+		abort:    abort,
+	}
+
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+// Enqueue はタスクをキューに追加する
+// バッファが埋まっている場合は空きが出るかctxがキャンセルされるまで待機する
+func (q *LocalQueue) Enqueue(ctx context.Context, task *queue.Task) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if _, err := q.registry.Lookup(task.Type); err != nil {
+		return err
+	}
+
+	// リクエストのキャンセル・デッドラインは引き継がず、値（DB・ユーザーIDなど）のみ伝播する
+	j := &job{ctx: context.WithoutCancel(ctx), task: task}
+	select {
+	case q.jobs <- j:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown は新規タスクの受付を停止し、キューに残ったタスクの完了を待つ
+// ctxがタイムアウトした場合は実行中のタスクを中断して戻る
+func (q *LocalQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.abort()
+		return nil
+	case <-ctx.Done():
+		q.abort()
+		return ctx.Err()
+	}
+}
+
+func (q *LocalQueue) worker() {
+	defer q.wg.Done()
+	for j := range q.jobs {
+		q.process(j)
+	}
+}
+
+func (q *LocalQueue) process(j *job) {
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	stopAfter := context.AfterFunc(q.stop, cancel)
+	defer stopAfter()
+
+	def, err := q.registry.Lookup(j.task.Type)
+	if err != nil {
+		logger.Error(ctx, "local queue task failed", "task_id", j.task.ID, "task_type", j.task.Type, "error", err)
+		return
+	}
+
+	err = retry.Do(ctx, def.Retry, func() error {
+		return q.invoke(ctx, def, j.task)
+	})
+	if err != nil {
+		logger.Error(ctx, "local queue task failed", "task_id", j.task.ID, "task_type", j.task.Type, "error", err)
+	}
+}
+
+// invoke はパニックをエラーに変換してタスクを実行する
+func (q *LocalQueue) invoke(ctx context.Context, def *queue.Definition, task *queue.Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in task handler: %v", r)
+		}
+	}()
+	return def.Handle(ctx, task)
+}
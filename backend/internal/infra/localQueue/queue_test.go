@@ -0,0 +1,117 @@
+package localqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryConfig = retry.Config{
+	MaxRetries:     2,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+	Multiplier:     1,
+}
+
+type testPayload struct {
+	Value string `json:"value"`
+}
+
+// newTestQueue は"TestTask"に処理関数を登録したキューを作成する
+func newTestQueue(workers, capacity int, handler func(ctx context.Context, task *queue.Task, payload *testPayload) error) *LocalQueue {
+	registry := queue.NewRegistry()
+	queue.Register(registry, "TestTask", "test", testRetryConfig, handler)
+	return New(registry, workers, capacity)
+}
+
+func newTestTask(t *testing.T) *queue.Task {
+	t.Helper()
+	task, err := queue.NewTask("TestTask", "task-1", 1, &testPayload{Value: "hello"}, "")
+	require.NoError(t, err)
+	return task
+}
+
+func TestLocalQueue(t *testing.T) {
+	t.Run("登録したハンドラーでタスクが実行される", func(t *testing.T) {
+		var count int32
+		q := newTestQueue(2, 10, func(ctx context.Context, task *queue.Task, payload *testPayload) error {
+			if payload.Value == "hello" {
+				atomic.AddInt32(&count, 1)
+			}
+			return nil
+		})
+
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, q.Enqueue(context.Background(), newTestTask(t)))
+		}
+		assert.NoError(t, q.Shutdown(context.Background()))
+		assert.Equal(t, int32(5), atomic.LoadInt32(&count))
+	})
+
+	t.Run("失敗したタスクはリトライされる", func(t *testing.T) {
+		var attempts int32
+		q := newTestQueue(1, 1, func(ctx context.Context, task *queue.Task, payload *testPayload) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return errors.New("temporary error")
+			}
+			return nil
+		})
+
+		assert.NoError(t, q.Enqueue(context.Background(), newTestTask(t)))
+		assert.NoError(t, q.Shutdown(context.Background()))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("パニックしたタスクもリトライされる", func(t *testing.T) {
+		var attempts int32
+		q := newTestQueue(1, 1, func(ctx context.Context, task *queue.Task, payload *testPayload) error {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				panic("unexpected")
+			}
+			return nil
+		})
+
+		assert.NoError(t, q.Enqueue(context.Background(), newTestTask(t)))
+		assert.NoError(t, q.Shutdown(context.Background()))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("未登録のタスクタイプはエラーになる", func(t *testing.T) {
+		q := New(queue.NewRegistry(), 1, 1)
+		defer q.Shutdown(context.Background())
+		assert.ErrorIs(t, q.Enqueue(context.Background(), &queue.Task{Type: "Unknown"}), queue.ErrUnknownTaskType)
+	})
+
+	t.Run("シャットダウン後はタスクを受け付けない", func(t *testing.T) {
+		q := newTestQueue(1, 1, func(ctx context.Context, task *queue.Task, payload *testPayload) error { return nil })
+		assert.NoError(t, q.Shutdown(context.Background()))
+		assert.ErrorIs(t, q.Enqueue(context.Background(), newTestTask(t)), ErrQueueClosed)
+	})
+
+	t.Run("シャットダウンがタイムアウトした場合は実行中のタスクを中断する", func(t *testing.T) {
+		cancelled := make(chan struct{})
+		q := newTestQueue(1, 1, func(ctx context.Context, task *queue.Task, payload *testPayload) error {
+			<-ctx.Done()
+			close(cancelled)
+			return nil
+		})
+		assert.NoError(t, q.Enqueue(context.Background(), newTestTask(t)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, q.Shutdown(ctx), context.DeadlineExceeded)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("task was not cancelled")
+		}
+	})
+}
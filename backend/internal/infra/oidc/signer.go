@@ -0,0 +1,72 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+const localKeyID = "local"
+
+// LocalSigner はローカル開発・テスト用にIDトークンを発行する署名器
+// 起動ごとに生成したRSA鍵で署名し、KeySetとして検証にも利用できる
+type LocalSigner struct {
+	key *rsa.PrivateKey
+}
+
+func NewLocalSigner() (*LocalSigner, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return &LocalSigner{key: key}, nil
+}
+
+// Sign はクレームにRS256で署名したトークンを返す
+func (s *LocalSigner) Sign(claims *Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: "RS256", Kid: localKeyID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Key は署名に使用した公開鍵を返す
+func (s *LocalSigner) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if kid != localKeyID {
+		return nil, fmt.Errorf("unknown key id: %s", kid)
+	}
+	return &s.key.PublicKey, nil
+}
+
+// ServeHTTP は公開鍵をJWKS形式で返す（RemoteKeySetの取得先として利用できる）
+func (s *LocalSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(jwks{Keys: []jwk{{
+		Kid: localKeyID,
+		Kty: "RSA",
+		Alg: "RS256",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
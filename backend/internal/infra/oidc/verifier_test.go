@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAudience = "https://api.example.com"
+	testEmail    = "tasks@example.iam.gserviceaccount.com"
+)
+
+func validClaims(now time.Time) *Claims {
+	return &Claims{
+		Issuer:        GoogleIssuer,
+		Subject:       "1234567890",
+		Audience:      Audience{testAudience},
+		Email:         testEmail,
+		EmailVerified: true,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	signer, err := NewLocalSigner()
+	require.NoError(t, err)
+
+	// JWKSエンドポイント経由で公開鍵を取得する
+	jwksServer := httptest.NewServer(signer)
+	defer jwksServer.Close()
+
+	now := time.Unix(1790000000, 0)
+	verifier := NewVerifier(NewRemoteKeySet(jwksServer.URL), GoogleIssuer, testAudience)
+	verifier.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	t.Run("正しいトークンを検証できる", func(t *testing.T) {
+		token, err := signer.Sign(validClaims(now))
+		require.NoError(t, err)
+
+		claims, err := verifier.Verify(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, testEmail, claims.Email)
+		assert.True(t, claims.EmailVerified)
+	})
+
+	cases := []struct {
+		name   string
+		modify func(c *Claims)
+	}{
+		{name: "issuerが異なるトークンはエラーになる", modify: func(c *Claims) { c.Issuer = "https://evil.example.com" }},
+		{name: "audienceが異なるトークンはエラーになる", modify: func(c *Claims) { c.Audience = Audience{"https://other.example.com"} }},
+		{name: "有効期限切れのトークンはエラーになる", modify: func(c *Claims) { c.ExpiresAt = now.Add(-10 * time.Minute).Unix() }},
+		{name: "未来に発行されたトークンはエラーになる", modify: func(c *Claims) { c.IssuedAt = now.Add(10 * time.Minute).Unix() }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := validClaims(now)
+			tc.modify(claims)
+			token, err := signer.Sign(claims)
+			require.NoError(t, err)
+
+			_, err = verifier.Verify(ctx, token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	t.Run("別の鍵で署名されたトークンはエラーになる", func(t *testing.T) {
+		other, err := NewLocalSigner()
+		require.NoError(t, err)
+		token, err := other.Sign(validClaims(now))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("改ざんされたトークンはエラーになる", func(t *testing.T) {
+		token, err := signer.Sign(validClaims(now))
+		require.NoError(t, err)
+		forged, err := signer.Sign(&Claims{Issuer: GoogleIssuer, Audience: Audience{testAudience}, Email: "attacker@example.com", ExpiresAt: now.Add(time.Hour).Unix()})
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		parts[1] = strings.Split(forged, ".")[1]
+		_, err = verifier.Verify(ctx, strings.Join(parts, "."))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("不正な形式のトークンはエラーになる", func(t *testing.T) {
+		_, err := verifier.Verify(ctx, "invalid")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
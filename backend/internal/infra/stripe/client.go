@@ -0,0 +1,302 @@
+package stripe
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+)
+
+// Webhook署名のタイムスタンプ許容誤差
+const signatureTolerance = 5 * time.Minute
+
+type StripeClient struct {
+	httpClient    *http.Client
+	baseURL       string
+	secretKey     string
+	webhookSecret string
+	priceIDs      map[string]string // 課金商品 -> StripeのPrice ID
+	now           func() time.Time
+}
+
+func NewClient(ctx context.Context) *StripeClient {
+	env := config.GetCtxEnv(ctx)
+	return &StripeClient{
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		baseURL:       strings.TrimRight(env.STRIPE_API_BASE_URL, "/"),
+		secretKey:     env.STRIPE_SECRET_KEY,
+		webhookSecret: env.STRIPE_WEBHOOK_SECRET,
+		priceIDs: map[string]string{
+			domain.BillingProductTokens100:  env.STRIPE_PRICE_100_TOKENS,
+			domain.BillingProductTokens500:  env.STRIPE_PRICE_500_TOKENS,
+			domain.BillingProductTokens1000: env.STRIPE_PRICE_1000_TOKENS,
+			domain.BillingProductMonthly:    env.STRIPE_PRICE_MONTHLY,
+			domain.BillingProductYearly:     env.STRIPE_PRICE_YEARLY,
+		},
+		now: time.Now,
+	}
+}
+
+// CreateCheckoutSession はCheckout Sessionを作成する
+// IdempotencyKeyはStripeのIdempotency-Keyヘッダーとして送信する
+func (c *StripeClient) CreateCheckoutSession(ctx context.Context, params *domain.StripeCheckoutParams) (*domain.StripeCheckoutSession, error) {
+	priceID := c.priceIDs[params.Product]
+	if priceID == "" {
+		return nil, fmt.Errorf("stripe price id is not configured: product=%s", params.Product)
+	}
+
+	form := url.Values{}
+	form.Set("mode", params.Mode)
+	form.Set("line_items[0][price]", priceID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("success_url", params.SuccessURL)
+	form.Set("cancel_url", params.CancelURL)
+	form.Set("client_reference_id", params.ClientReferenceID)
+	for k, v := range params.Metadata {
+		form.Set(fmt.Sprintf("metadata[%s]", k), v)
+		if params.Mode == "subscription" {
+			form.Set(fmt.Sprintf("subscription_data[metadata][%s]", k), v)
+		}
+	}
+
+	var session checkoutSessionObject
+	if err := c.do(ctx, http.MethodPost, "/v1/checkout/sessions", form, params.IdempotencyKey, &session); err != nil {
+		return nil, err
+	}
+	return session.toDomain(), nil
+}
+
+// GetSubscription はSubscriptionを取得する
+func (c *StripeClient) GetSubscription(ctx context.Context, id string) (*domain.StripeSubscription, error) {
+	var sub subscriptionObject
+	if err := c.do(ctx, http.MethodGet, "/v1/subscriptions/"+url.PathEscape(id), nil, "", &sub); err != nil {
+		return nil, err
+	}
+	return c.toSubscription(&sub), nil
+}
+
+// ParseWebhookEvent はStripe-Signatureヘッダーを検証してイベントを返す
+func (c *StripeClient) ParseWebhookEvent(payload []byte, signature string) (*domain.StripeEvent, error) {
+	if err := c.verifySignature(payload, signature); err != nil {
+		return nil, err
+	}
+
+	var ev eventObject
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stripe event: %w", err)
+	}
+
+	event := &domain.StripeEvent{ID: ev.ID, Type: ev.Type}
+	switch {
+	case strings.HasPrefix(ev.Type, "checkout.session."):
+		var session checkoutSessionObject
+		if err := json.Unmarshal(ev.Data.Object, &session); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal checkout session: %w", err)
+		}
+		event.CheckoutSession = session.toDomain()
+	case strings.HasPrefix(ev.Type, "customer.subscription."):
+		var sub subscriptionObject
+		if err := json.Unmarshal(ev.Data.Object, &sub); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal subscription: %w", err)
+		}
+		event.Subscription = c.toSubscription(&sub)
+	}
+	return event, nil
+}
+
+// toSubscription はSubscriptionに契約中のPrice IDの課金商品を設定して返す
+func (c *StripeClient) toSubscription(o *subscriptionObject) *domain.StripeSubscription {
+	sub := o.toDomain()
+	for product, priceID := range c.priceIDs {
+		if priceID != "" && priceID == sub.PriceID {
+			sub.Product = product
+			break
+		}
+	}
+	return sub
+}
+
+// verifySignature は "t=<timestamp>,v1=<signature>" 形式のヘッダーを検証する
+// シークレットが未設定の場合は誰でも署名を計算できるため、すべてのWebhookを拒否する
+func (c *StripeClient) verifySignature(payload []byte, header string) error {
+	if c.webhookSecret == "" {
+		return fmt.Errorf("%w: webhook secret is not configured", errors.ErrInvalidStripeSignature)
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", errors.ErrInvalidStripeSignature)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", errors.ErrInvalidStripeSignature)
+	}
+	if diff := c.now().Sub(time.Unix(ts, 0)); diff > signatureTolerance || diff < -signatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", errors.ErrInvalidStripeSignature)
+	}
+
+	expected := ComputeSignature(c.webhookSecret, ts, payload)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no matching signature", errors.ErrInvalidStripeSignature)
+}
+
+// ComputeSignature はWebhookの署名（v1）を計算する
+func ComputeSignature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *StripeClient) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create stripe request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call stripe api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read stripe response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr errorResponse
+		_ = json.Unmarshal(respBody, &apiErr)
+		return fmt.Errorf("stripe api error: status=%d type=%s message=%s", resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal stripe response: %w", err)
+	}
+	return nil
+}
+
+// ============================================================
+// Stripe APIのレスポンス
+// ============================================================
+
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type eventObject struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type checkoutSessionObject struct {
+	ID                string            `json:"id"`
+	URL               string            `json:"url"`
+	Mode              string            `json:"mode"`
+	Customer          string            `json:"customer"`
+	Subscription      string            `json:"subscription"`
+	PaymentIntent     string            `json:"payment_intent"`
+	ClientReferenceID string            `json:"client_reference_id"`
+	AmountTotal       int               `json:"amount_total"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+func (o *checkoutSessionObject) toDomain() *domain.StripeCheckoutSession {
+	return &domain.StripeCheckoutSession{
+		ID:                o.ID,
+		URL:               o.URL,
+		Mode:              o.Mode,
+		CustomerID:        o.Customer,
+		SubscriptionID:    o.Subscription,
+		PaymentIntentID:   o.PaymentIntent,
+		ClientReferenceID: o.ClientReferenceID,
+		AmountTotal:       o.AmountTotal,
+		Metadata:          o.Metadata,
+	}
+}
+
+type subscriptionObject struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (o *subscriptionObject) toDomain() *domain.StripeSubscription {
+	// 新しいAPIバージョンでは期間終了日時がitems側に移動しているためフォールバックする
+	periodEnd := o.CurrentPeriodEnd
+	if periodEnd == 0 && len(o.Items.Data) > 0 {
+		periodEnd = o.Items.Data[0].CurrentPeriodEnd
+	}
+	var priceID string
+	if len(o.Items.Data) > 0 {
+		priceID = o.Items.Data[0].Price.ID
+	}
+	return &domain.StripeSubscription{
+		ID:               o.ID,
+		CustomerID:       o.Customer,
+		Status:           o.Status,
+		CurrentPeriodEnd: time.Unix(periodEnd, 0),
+		PriceID:          priceID,
+		Metadata:         o.Metadata,
+	}
+}
@@ -0,0 +1,175 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// newFakeStripeServer はCheckout Session作成とSubscription取得に応答するフェイクStripeサーバー
+func newFakeStripeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	sessions := map[string]string{} // Idempotency-Key -> Session ID
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/checkout/sessions", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("line_items[0][price]") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"missing price"}}`))
+			return
+		}
+
+		key := r.Header.Get("Idempotency-Key")
+		id, ok := sessions[key]
+		if !ok {
+			id = fmt.Sprintf("cs_test_%d", len(sessions)+1)
+			sessions[key] = id
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":                  id,
+			"url":                 "https://checkout.stripe.test/" + id,
+			"mode":                r.Form.Get("mode"),
+			"client_reference_id": r.Form.Get("client_reference_id"),
+			"metadata":            map[string]string{"product": r.Form.Get("metadata[product]")},
+		})
+	})
+	mux.HandleFunc("/v1/subscriptions/sub_test_1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"sub_test_1","customer":"cus_test_1","status":"active","items":{"data":[{"current_period_end":1790000000,"price":{"id":"price_monthly"}}]}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestClient(baseURL string) *StripeClient {
+	ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{
+		STRIPE_API_BASE_URL:     baseURL,
+		STRIPE_SECRET_KEY:       "sk_test",
+		STRIPE_WEBHOOK_SECRET:   testWebhookSecret,
+		STRIPE_PRICE_100_TOKENS: "price_tokens_100",
+		STRIPE_PRICE_MONTHLY:    "price_monthly",
+	})
+	return NewClient(ctx)
+}
+
+func TestStripeClient_CreateCheckoutSession(t *testing.T) {
+	server := newFakeStripeServer(t)
+	defer server.Close()
+	client := newTestClient(server.URL)
+	ctx := context.Background()
+
+	params := &domain.StripeCheckoutParams{
+		Product:           domain.BillingProductTokens100,
+		Mode:              "payment",
+		SuccessURL:        "https://example.com/success",
+		CancelURL:         "https://example.com/cancel",
+		ClientReferenceID: "user-1",
+		IdempotencyKey:    "user-1:req-1",
+		Metadata:          map[string]string{"product": domain.BillingProductTokens100},
+	}
+
+	t.Run("Checkout Sessionを作成できる", func(t *testing.T) {
+		session, err := client.CreateCheckoutSession(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, "cs_test_1", session.ID)
+		assert.Equal(t, "https://checkout.stripe.test/cs_test_1", session.URL)
+		assert.Equal(t, "user-1", session.ClientReferenceID)
+		assert.Equal(t, domain.BillingProductTokens100, session.Metadata["product"])
+	})
+
+	t.Run("同じIdempotency-Keyでは同じセッションが返る", func(t *testing.T) {
+		session, err := client.CreateCheckoutSession(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, "cs_test_1", session.ID)
+	})
+
+	t.Run("Price IDが未設定の商品はエラーになる", func(t *testing.T) {
+		_, err := client.CreateCheckoutSession(ctx, &domain.StripeCheckoutParams{Product: domain.BillingProductYearly, Mode: "subscription"})
+		assert.Error(t, err)
+	})
+}
+
+func TestStripeClient_GetSubscription(t *testing.T) {
+	server := newFakeStripeServer(t)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	sub, err := client.GetSubscription(context.Background(), "sub_test_1")
+	require.NoError(t, err)
+	assert.Equal(t, "cus_test_1", sub.CustomerID)
+	assert.Equal(t, "active", sub.Status)
+	assert.Equal(t, int64(1790000000), sub.CurrentPeriodEnd.Unix())
+	assert.Equal(t, "price_monthly", sub.PriceID)
+	assert.Equal(t, domain.BillingProductMonthly, sub.Product)
+}
+
+func TestStripeClient_ParseWebhookEvent(t *testing.T) {
+	client := newTestClient("http://localhost")
+	now := time.Unix(1790000000, 0)
+	client.now = func() time.Time { return now }
+
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_test_1","mode":"payment","payment_intent":"pi_1","amount_total":980}}}`)
+	sign := func(ts time.Time, secret string) string {
+		return fmt.Sprintf("t=%d,v1=%s", ts.Unix(), ComputeSignature(secret, ts.Unix(), payload))
+	}
+
+	t.Run("正しい署名のイベントを解析できる", func(t *testing.T) {
+		event, err := client.ParseWebhookEvent(payload, sign(now, testWebhookSecret))
+		require.NoError(t, err)
+		assert.Equal(t, "evt_1", event.ID)
+		assert.Equal(t, "checkout.session.completed", event.Type)
+		require.NotNil(t, event.CheckoutSession)
+		assert.Equal(t, "cs_test_1", event.CheckoutSession.ID)
+		assert.Equal(t, "pi_1", event.CheckoutSession.PaymentIntentID)
+		assert.Equal(t, 980, event.CheckoutSession.AmountTotal)
+	})
+
+	t.Run("シークレットが異なる署名はエラーになる", func(t *testing.T) {
+		_, err := client.ParseWebhookEvent(payload, sign(now, "whsec_other"))
+		assert.True(t, errors.Is(err, errors.ErrInvalidStripeSignature))
+	})
+
+	t.Run("許容時間を過ぎた署名はエラーになる", func(t *testing.T) {
+		_, err := client.ParseWebhookEvent(payload, sign(now.Add(-10*time.Minute), testWebhookSecret))
+		assert.True(t, errors.Is(err, errors.ErrInvalidStripeSignature))
+	})
+
+	t.Run("不正な形式のヘッダーはエラーになる", func(t *testing.T) {
+		_, err := client.ParseWebhookEvent(payload, "invalid")
+		assert.True(t, errors.Is(err, errors.ErrInvalidStripeSignature))
+	})
+
+	t.Run("シークレットが未設定の場合は空のシークレットで署名したイベントも拒否する", func(t *testing.T) {
+		unconfigured := newTestClient("http://localhost")
+		unconfigured.webhookSecret = ""
+		unconfigured.now = client.now
+		_, err := unconfigured.ParseWebhookEvent(payload, sign(now, ""))
+		assert.True(t, errors.Is(err, errors.ErrInvalidStripeSignature))
+	})
+
+	t.Run("サブスクリプションのイベントはPrice IDを課金商品に変換する（未知のPrice IDは空）", func(t *testing.T) {
+		for priceID, product := range map[string]string{"price_monthly": domain.BillingProductMonthly, "price_unknown": ""} {
+			subPayload := []byte(fmt.Sprintf(`{"id":"evt_2","type":"customer.subscription.updated","data":{"object":{"id":"sub_test_1","status":"active","items":{"data":[{"current_period_end":1790000000,"price":{"id":%q}}]}}}}`, priceID))
+			signature := fmt.Sprintf("t=%d,v1=%s", now.Unix(), ComputeSignature(testWebhookSecret, now.Unix(), subPayload))
+
+			event, err := client.ParseWebhookEvent(subPayload, signature)
+			require.NoError(t, err)
+			require.NotNil(t, event.Subscription)
+			assert.Equal(t, priceID, event.Subscription.PriceID)
+			assert.Equal(t, product, event.Subscription.Product)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package voicevox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+)
+
+// Client はVOICEVOX ENGINEのHTTP APIで音声を合成するagent.ISpeechSynthesizerの実装
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient はVOICEVOX ENGINE（例: http://localhost:50021）のクライアントを作成する
+func NewClient(baseURL string) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Synthesize はaudio_queryで作成した音声合成用のクエリに話速・音高を設定し、synthesisでWAVを合成する
+func (c *Client) Synthesize(ctx context.Context, req agent.SpeechRequest) (*agent.SpeechClip, error) {
+	speaker := strconv.Itoa(req.SpeakerID)
+
+	query, err := c.post(ctx, "/audio_query", url.Values{"text": {req.Text}, "speaker": {speaker}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio query: %w", err)
+	}
+	// クエリの未知の項目はそのまま送り返す
+	var audioQuery map[string]any
+	if err := json.Unmarshal(query, &audioQuery); err != nil {
+		return nil, fmt.Errorf("failed to parse audio query: %w", err)
+	}
+	if req.SpeedScale > 0 {
+		audioQuery["speedScale"] = req.SpeedScale
+	}
+	if req.PitchScale != 0 {
+		audioQuery["pitchScale"] = req.PitchScale
+	}
+	body, err := json.Marshal(audioQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audio query: %w", err)
+	}
+
+	wav, err := c.post(ctx, "/synthesis", url.Values{"speaker": {speaker}}, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
+	}
+	duration, err := wavDuration(wav)
+	if err != nil {
+		return nil, err
+	}
+	return &agent.SpeechClip{Data: wav, DurationSeconds: duration}, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, params url.Values, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path+"?"+params.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("voicevox returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// wavDuration はWAVのfmtチャンクのバイトレートとdataチャンクの大きさから長さ（秒）を求める
+func wavDuration(data []byte) (float64, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return 0, fmt.Errorf("invalid wav data")
+	}
+	var byteRate, dataSize uint32
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := binary.LittleEndian.Uint32(data[offset+4 : offset+8])
+		body := offset + 8
+		switch id {
+		case "fmt ":
+			if body+12 > len(data) {
+				return 0, fmt.Errorf("invalid wav fmt chunk")
+			}
+			byteRate = binary.LittleEndian.Uint32(data[body+8 : body+12])
+		case "data":
+			dataSize = size
+		}
+		// チャンクは2バイト境界に揃える
+		offset = body + int(size) + int(size%2)
+	}
+	if byteRate == 0 {
+		return 0, fmt.Errorf("wav fmt chunk not found")
+	}
+	return float64(dataSize) / float64(byteRate), nil
+}
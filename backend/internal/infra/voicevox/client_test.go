@@ -0,0 +1,117 @@
+package voicevox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWAV は指定した長さの無音のWAV（24kHz・16bit・モノラル）を作成する
+func testWAV(seconds float64) []byte {
+	const sampleRate, bytesPerSample = 24000, 2
+	size := uint32(seconds * sampleRate * bytesPerSample)
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+size)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * bytesPerSample), uint16(bytesPerSample), uint16(16)} {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, size)
+	buf.Write(make([]byte, size))
+	return buf.Bytes()
+}
+
+// newFakeVoicevoxServer はaudio_query・synthesisに応答するVOICEVOX ENGINEの代わりのサーバー
+// 合成した音声の長さは文字数×0.2秒を話速で割った長さにする
+func newFakeVoicevoxServer(t *testing.T, queries *[]map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audio_query", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Query().Get("speaker") == "999" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"detail":"該当する話者が見つかりません"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"accent_phrases": []any{},
+			"speedScale":     1.0,
+			"pitchScale":     0.0,
+			"kana":           r.URL.Query().Get("text"),
+		})
+	})
+	mux.HandleFunc("/synthesis", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var query map[string]any
+		require.NoError(t, json.Unmarshal(body, &query))
+		*queries = append(*queries, query)
+
+		chars := len([]rune(query["kana"].(string)))
+		_, _ = w.Write(testWAV(float64(chars) * 0.2 / query["speedScale"].(float64)))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Synthesize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("audio_queryに話速・音高を設定して合成する", func(t *testing.T) {
+		var queries []map[string]any
+		client := NewClient(newFakeVoicevoxServer(t, &queries).URL + "/")
+
+		clip, err := client.Synthesize(ctx, agent.SpeechRequest{Text: "清水寺に到着", SpeakerID: 3, SpeedScale: 1.2, PitchScale: 0.05})
+		require.NoError(t, err)
+		assert.InDelta(t, 1.0, clip.DurationSeconds, 0.001)
+		assert.Equal(t, "RIFF", string(clip.Data[:4]))
+
+		require.Len(t, queries, 1)
+		assert.Equal(t, 1.2, queries[0]["speedScale"])
+		assert.Equal(t, 0.05, queries[0]["pitchScale"])
+		// 未知の項目はそのまま送り返す
+		assert.Equal(t, []any{}, queries[0]["accent_phrases"])
+	})
+
+	t.Run("話速・音高を指定しない場合はクエリの値を使用する", func(t *testing.T) {
+		var queries []map[string]any
+		client := NewClient(newFakeVoicevoxServer(t, &queries).URL)
+
+		clip, err := client.Synthesize(ctx, agent.SpeechRequest{Text: "抹茶", SpeakerID: 1})
+		require.NoError(t, err)
+		assert.InDelta(t, 0.4, clip.DurationSeconds, 0.001)
+		assert.Equal(t, 1.0, queries[0]["speedScale"])
+	})
+
+	t.Run("VOICEVOXがエラーを返した場合はエラーにする", func(t *testing.T) {
+		var queries []map[string]any
+		client := NewClient(newFakeVoicevoxServer(t, &queries).URL)
+
+		_, err := client.Synthesize(ctx, agent.SpeechRequest{Text: "抹茶", SpeakerID: 999})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 422")
+		assert.Empty(t, queries)
+	})
+}
+
+func TestWavDuration(t *testing.T) {
+	duration, err := wavDuration(testWAV(1.5))
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, duration, 0.001)
+
+	_, err = wavDuration([]byte("not a wav"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package progress
+
+import (
+	"context"
+	"sync"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+)
+
+// 購読者ごとの受信バッファ（溢れた購読者は切断し、Last-Event-IDで再接続させる）
+const subscriberBuffer = 64
+
+type subscriber struct {
+	topics []string
+	ch     chan *domain.ProgressEvent
+}
+
+// hub はプロセス内の購読者にイベントを配信する
+type hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[*subscriber]struct{}
+	closed bool
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[string]map[*subscriber]struct{})}
+}
+
+func (h *hub) add(topics []string) *subscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := &subscriber{topics: topics, ch: make(chan *domain.ProgressEvent, subscriberBuffer)}
+	if h.closed {
+		close(s.ch)
+		return s
+	}
+	for _, topic := range topics {
+		if h.subs[topic] == nil {
+			h.subs[topic] = make(map[*subscriber]struct{})
+		}
+		h.subs[topic][s] = struct{}{}
+	}
+	return s
+}
+
+// remove は購読者を削除してチャネルを閉じる（削除済みの場合は何もしない）
+func (h *hub) remove(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(s)
+}
+
+func (h *hub) removeLocked(s *subscriber) {
+	removed := false
+	for _, topic := range s.topics {
+		if _, ok := h.subs[topic][s]; !ok {
+			continue
+		}
+		removed = true
+		delete(h.subs[topic], s)
+		if len(h.subs[topic]) == 0 {
+			delete(h.subs, topic)
+		}
+	}
+	if removed {
+		close(s.ch)
+	}
+}
+
+// dispatch はトピックの購読者にイベントを配信する
+// 受信が追いつかない購読者はブロックせずに切断する
+func (h *hub) dispatch(event *domain.ProgressEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs[event.Topic] {
+		select {
+		case s.ch <- event:
+		default:
+			h.removeLocked(s)
+		}
+	}
+}
+
+func (h *hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.subs {
+		for s := range subs {
+			h.removeLocked(s)
+		}
+	}
+}
+
+// subscribe は購読者を登録し、afterIDより後の保持中のイベントを再送してから新しいイベントを配信する
+// 購読の登録後に再送対象を取得するため、再送と配信で重複したイベントは再送済みとして配信しない
+func (h *hub) subscribe(ctx context.Context, topics []string, afterID int64, replay func() ([]*domain.ProgressEvent, error)) (<-chan *domain.ProgressEvent, error) {
+	s := h.add(topics)
+
+	var replayed []*domain.ProgressEvent
+	if afterID > 0 {
+		var err error
+		replayed, err = replay()
+		if err != nil {
+			h.remove(s)
+			return nil, err
+		}
+	}
+
+	out := make(chan *domain.ProgressEvent)
+	go func() {
+		defer close(out)
+		defer h.remove(s)
+
+		send := func(event *domain.ProgressEvent) bool {
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		sent := make(map[int64]struct{}, len(replayed))
+		for _, event := range replayed {
+			if !send(event) {
+				return
+			}
+			sent[event.ID] = struct{}{}
+		}
+		for {
+			select {
+			case event, ok := <-s.ch:
+				if !ok {
+					return
+				}
+				if _, ok := sent[event.ID]; ok {
+					continue
+				}
+				if !send(event) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+)
+
+const (
+	// トピックごとに再送用に保持するイベント数
+	memoryHistorySize = 100
+	// 最後のイベントから保持する期間
+	memoryRetention = 30 * time.Minute
+)
+
+// MemoryBus はプロセス内で進捗イベントを配信する
+// 単一インスタンス（ローカル環境・プロセス内キュー）向けの実装
+type MemoryBus struct {
+	*hub
+
+	mu        sync.Mutex
+	lastID    int64
+	history   map[string][]*domain.ProgressEvent
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		hub:     newHub(),
+		history: make(map[string][]*domain.ProgressEvent),
+		now:     time.Now,
+	}
+}
+
+// Publish はトピックにイベントを発行する
+func (b *MemoryBus) Publish(ctx context.Context, topic string, msg Message) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.lastID++
+	event := &domain.ProgressEvent{
+		ID:        b.lastID,
+		Topic:     topic,
+		Name:      msg.Name,
+		Data:      string(data),
+		Final:     msg.Final,
+		CreatedAt: b.now(),
+	}
+	events := append(b.history[topic], event)
+	if len(events) > memoryHistorySize {
+		events = events[len(events)-memoryHistorySize:]
+	}
+	b.history[topic] = events
+	b.pruneLocked()
+	// 配信順がIDの順序と一致するよう、ロックを保持したまま配信する
+	b.dispatch(event)
+	b.mu.Unlock()
+	return nil
+}
+
+// Subscribe はトピックのイベントを購読する
+func (b *MemoryBus) Subscribe(ctx context.Context, topics []string, afterID int64) (<-chan *domain.ProgressEvent, error) {
+	return b.subscribe(ctx, topics, afterID, func() ([]*domain.ProgressEvent, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		var events []*domain.ProgressEvent
+		for _, topic := range topics {
+			for _, event := range b.history[topic] {
+				if event.ID > afterID {
+					events = append(events, event)
+				}
+			}
+		}
+		sortEvents(events)
+		return events, nil
+	})
+}
+
+// Close は購読をすべて終了する
+func (b *MemoryBus) Close() error {
+	b.close()
+	return nil
+}
+
+// pruneLocked は保持期間を過ぎたトピックの履歴を削除する（1分に1回まで）
+func (b *MemoryBus) pruneLocked() {
+	now := b.now()
+	if now.Sub(b.lastPrune) < time.Minute {
+		return
+	}
+	b.lastPrune = now
+	for topic, events := range b.history {
+		if now.Sub(events[len(events)-1].CreatedAt) > memoryRetention {
+			delete(b.history, topic)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan *domain.ProgressEvent) *domain.ProgressEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func assertClosed(t *testing.T, ch <-chan *domain.ProgressEvent) {
+	t.Helper()
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed")
+	}
+}
+
+func TestMemoryBus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("同じトピックの購読者全員に配信する", func(t *testing.T) {
+		bus := NewMemoryBus()
+		defer bus.Close()
+
+		first, err := bus.Subscribe(ctx, []string{VLogTopic("vlog-1")}, 0)
+		require.NoError(t, err)
+		second, err := bus.Subscribe(ctx, []string{VLogTopic("vlog-1")}, 0)
+		require.NoError(t, err)
+		other, err := bus.Subscribe(ctx, []string{VLogTopic("vlog-2")}, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, bus.Publish(ctx, VLogTopic("vlog-1"), Message{Data: map[string]float64{"progress": 0.5}}))
+
+		for _, ch := range []<-chan *domain.ProgressEvent{first, second} {
+			event := receive(t, ch)
+			assert.Equal(t, int64(1), event.ID)
+			assert.JSONEq(t, `{"progress":0.5}`, event.Data)
+		}
+		select {
+		case event := <-other:
+			t.Fatalf("unexpected event: %+v", event)
+		default:
+		}
+	})
+
+	t.Run("Last-Event-ID以降のイベントを再送してから新しいイベントを配信する", func(t *testing.T) {
+		bus := NewMemoryBus()
+		defer bus.Close()
+
+		topic := VLogTopic("vlog-1")
+		for i := 0; i < 3; i++ {
+			require.NoError(t, bus.Publish(ctx, topic, Message{Data: i}))
+		}
+
+		ch, err := bus.Subscribe(ctx, []string{topic}, 1)
+		require.NoError(t, err)
+		require.NoError(t, bus.Publish(ctx, topic, Message{Name: EventCancelled, Data: 3, Final: true}))
+
+		assert.Equal(t, int64(2), receive(t, ch).ID)
+		assert.Equal(t, int64(3), receive(t, ch).ID)
+		event := receive(t, ch)
+		assert.Equal(t, int64(4), event.ID)
+		assert.Equal(t, EventCancelled, event.Name)
+		assert.True(t, event.Final)
+	})
+
+	t.Run("受信が追いつかない購読者は切断する", func(t *testing.T) {
+		bus := NewMemoryBus()
+		defer bus.Close()
+
+		topic := MediaTopic("media-1")
+		slow := bus.add([]string{topic})
+
+		for i := 0; i <= subscriberBuffer; i++ {
+			require.NoError(t, bus.Publish(ctx, topic, Message{Data: i}))
+		}
+
+		for range subscriberBuffer {
+			<-slow.ch
+		}
+		_, ok := <-slow.ch
+		assert.False(t, ok)
+
+		// 切断された購読者はLast-Event-IDで再接続して続きを受信できる
+		ch, err := bus.Subscribe(ctx, []string{topic}, int64(subscriberBuffer))
+		require.NoError(t, err)
+		assert.Equal(t, int64(subscriberBuffer+1), receive(t, ch).ID)
+	})
+
+	t.Run("終了時は購読を閉じる", func(t *testing.T) {
+		bus := NewMemoryBus()
+		ch, err := bus.Subscribe(ctx, []string{VLogTopic("vlog-1")}, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, bus.Close())
+		assertClosed(t, ch)
+	})
+}
@@ -0,0 +1,41 @@
+package progress
+
+import (
+	"context"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+)
+
+// SSEのイベント名
+const (
+	EventCancelled = "cancelled" // VLog生成のキャンセル
+)
+
+// VLogTopic はVLogの進捗イベントのトピックを返す
+func VLogTopic(vlogID string) string {
+	return "vlog:" + vlogID
+}
+
+// MediaTopic はメディア分析の進捗イベントのトピックを返す
+func MediaTopic(mediaID string) string {
+	return "media:" + mediaID
+}
+
+// Message は発行するイベントの内容
+type Message struct {
+	Name  string      // SSEのイベント名（空の場合はmessage）
+	Data  interface{} // JSONに変換して配信する
+	Final bool        // トピックの最後のイベントかどうか
+}
+
+// IBus は進捗イベントを発行・購読するインターフェース
+type IBus interface {
+	// Publish はトピックにイベントを発行する
+	Publish(ctx context.Context, topic string, msg Message) error
+	// Subscribe はトピックのイベントを購読する
+	// afterIDより後の保持中のイベントを再送してから新しいイベントを配信する（0の場合は新しいイベントのみ）
+	// ctxが終了するか、購読者の受信が追いつかなくなった場合はチャネルを閉じる
+	Subscribe(ctx context.Context, topics []string, afterID int64) (<-chan *domain.ProgressEvent, error)
+	// Close は購読をすべて終了する
+	Close() error
+}
@@ -0,0 +1,151 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+const (
+	// 1回の取得件数
+	storeFetchLimit = 500
+	// 採番後にコミットが遅れたイベントを取りこぼさないよう、取得済みのIDから遡って再取得する件数
+	storeLookback = 100
+	// イベントの保持期間
+	storeRetention = 24 * time.Hour
+)
+
+// StoreBus はデータベースを経由して複数インスタンスに進捗イベントを配信する
+// 発行したイベントはテーブルに保存し、インスタンスごとに1つのポーラーが新しいイベントを取得して購読者に配信する
+type StoreBus struct {
+	*hub
+
+	repo     domain.IProgressEventRepository
+	interval time.Duration
+
+	floor  int64 // 起動時点の最新ID（これ以前のイベントは配信しない）
+	cursor int64
+	seen   map[int64]struct{}
+
+	stop context.CancelFunc
+	done chan struct{}
+	once sync.Once
+}
+
+func NewStoreBus(repo domain.IProgressEventRepository, interval time.Duration) *StoreBus {
+	return &StoreBus{
+		hub:      newHub(),
+		repo:     repo,
+		interval: interval,
+		seen:     make(map[int64]struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start はポーラーを開始する（ctxにはDB接続を設定しておく）
+func (b *StoreBus) Start(ctx context.Context) error {
+	cursor, err := b.repo.LatestID(ctx)
+	if err != nil {
+		return err
+	}
+	b.floor = cursor
+	b.cursor = cursor
+
+	ctx, b.stop = context.WithCancel(ctx)
+	go b.run(ctx)
+	return nil
+}
+
+// Publish はイベントを保存する（購読者への配信はポーラーが行う）
+func (b *StoreBus) Publish(ctx context.Context, topic string, msg Message) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	return b.repo.Create(ctx, &domain.ProgressEvent{
+		Topic: topic,
+		Name:  msg.Name,
+		Data:  string(data),
+		Final: msg.Final,
+	})
+}
+
+// Subscribe はトピックのイベントを購読する
+func (b *StoreBus) Subscribe(ctx context.Context, topics []string, afterID int64) (<-chan *domain.ProgressEvent, error) {
+	return b.subscribe(ctx, topics, afterID, func() ([]*domain.ProgressEvent, error) {
+		return b.repo.ListByTopicsAfter(ctx, topics, afterID, storeFetchLimit)
+	})
+}
+
+// Close はポーラーを停止し、購読をすべて終了する
+func (b *StoreBus) Close() error {
+	b.once.Do(func() {
+		if b.stop != nil {
+			b.stop()
+			<-b.done
+		}
+		b.close()
+	})
+	return nil
+}
+
+func (b *StoreBus) run(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.poll(ctx); err != nil {
+				logger.Warn(ctx, "failed to poll progress events", "error", err)
+			}
+		case <-cleanup.C:
+			if err := b.repo.DeleteBefore(ctx, time.Now().Add(-storeRetention)); err != nil {
+				logger.Warn(ctx, "failed to delete old progress events", "error", err)
+			}
+		}
+	}
+}
+
+// poll は新しいイベントを取得して購読者に配信する
+func (b *StoreBus) poll(ctx context.Context) error {
+	for {
+		events, err := b.repo.ListAfter(ctx, max(b.cursor-storeLookback, b.floor), storeFetchLimit)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if _, ok := b.seen[event.ID]; ok {
+				continue
+			}
+			b.seen[event.ID] = struct{}{}
+			b.dispatch(event)
+			if event.ID > b.cursor {
+				b.cursor = event.ID
+			}
+		}
+		for id := range b.seen {
+			if id <= b.cursor-storeLookback {
+				delete(b.seen, id)
+			}
+		}
+		if len(events) < storeFetchLimit {
+			return nil
+		}
+	}
+}
+
+func sortEvents(events []*domain.ProgressEvent) {
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+}
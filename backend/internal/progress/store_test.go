@@ -0,0 +1,149 @@
+package progress
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProgressEventRepo struct {
+	mu     sync.Mutex
+	lastID int64
+	events []*domain.ProgressEvent
+}
+
+func (r *fakeProgressEventRepo) Create(ctx context.Context, event *domain.ProgressEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	event.ID = r.lastID
+	r.events = append(r.events, event)
+	return nil
+}
+
+// reserve はIDの採番だけを行い、コミットが遅れたイベントを再現する
+func (r *fakeProgressEventRepo) reserve() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	return r.lastID
+}
+
+func (r *fakeProgressEventRepo) commit(event *domain.ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *fakeProgressEventRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.ProgressEvent, error) {
+	return r.list(func(event *domain.ProgressEvent) bool { return event.ID > afterID }, limit), nil
+}
+
+func (r *fakeProgressEventRepo) ListByTopicsAfter(ctx context.Context, topics []string, afterID int64, limit int) ([]*domain.ProgressEvent, error) {
+	return r.list(func(event *domain.ProgressEvent) bool {
+		for _, topic := range topics {
+			if event.Topic == topic && event.ID > afterID {
+				return true
+			}
+		}
+		return false
+	}, limit), nil
+}
+
+func (r *fakeProgressEventRepo) list(match func(*domain.ProgressEvent) bool, limit int) []*domain.ProgressEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []*domain.ProgressEvent
+	for _, event := range r.events {
+		if match(event) {
+			events = append(events, event)
+		}
+	}
+	sortEvents(events)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+func (r *fakeProgressEventRepo) LatestID(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastID, nil
+}
+
+func (r *fakeProgressEventRepo) DeleteBefore(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func TestStoreBus(t *testing.T) {
+	ctx := context.Background()
+	topic := VLogTopic("vlog-1")
+
+	t.Run("起動前のイベントは配信せず、保存されたイベントを購読者に配信する", func(t *testing.T) {
+		repo := &fakeProgressEventRepo{}
+		require.NoError(t, repo.Create(ctx, &domain.ProgressEvent{Topic: topic, Data: `"old"`}))
+
+		bus := NewStoreBus(repo, 10*time.Millisecond)
+		require.NoError(t, bus.Start(ctx))
+		defer bus.Close()
+
+		ch, err := bus.Subscribe(ctx, []string{topic}, 0)
+		require.NoError(t, err)
+		require.NoError(t, bus.Publish(ctx, topic, Message{Data: "new", Final: true}))
+
+		event := receive(t, ch)
+		assert.Equal(t, int64(2), event.ID)
+		assert.Equal(t, `"new"`, event.Data)
+		assert.True(t, event.Final)
+	})
+
+	t.Run("コミットが遅れたイベントも1回だけ配信する", func(t *testing.T) {
+		repo := &fakeProgressEventRepo{}
+		bus := NewStoreBus(repo, time.Hour)
+		require.NoError(t, bus.Start(ctx))
+		defer bus.Close()
+
+		ch, err := bus.Subscribe(ctx, []string{topic}, 0)
+		require.NoError(t, err)
+
+		late := repo.reserve()
+		require.NoError(t, bus.Publish(ctx, topic, Message{Data: 2}))
+		require.NoError(t, bus.poll(ctx))
+		assert.Equal(t, int64(2), receive(t, ch).ID)
+
+		repo.commit(&domain.ProgressEvent{ID: late, Topic: topic, Data: "1"})
+		require.NoError(t, bus.poll(ctx))
+		require.NoError(t, bus.poll(ctx))
+		assert.Equal(t, late, receive(t, ch).ID)
+		select {
+		case event := <-ch:
+			t.Fatalf("unexpected event: %+v", event)
+		default:
+		}
+	})
+
+	t.Run("Last-Event-ID以降のイベントを再送する", func(t *testing.T) {
+		repo := &fakeProgressEventRepo{}
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.Create(ctx, &domain.ProgressEvent{Topic: topic, Data: "{}"}))
+		}
+		require.NoError(t, repo.Create(ctx, &domain.ProgressEvent{Topic: VLogTopic("vlog-2"), Data: "{}"}))
+
+		bus := NewStoreBus(repo, time.Hour)
+		require.NoError(t, bus.Start(ctx))
+
+		ch, err := bus.Subscribe(ctx, []string{topic}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), receive(t, ch).ID)
+		assert.Equal(t, int64(3), receive(t, ch).ID)
+
+		require.NoError(t, bus.Close())
+		assertClosed(t, ch)
+	})
+}
@@ -0,0 +1,36 @@
+package queue
+
+import "context"
+
+type (
+	definitionKey struct{}
+	attemptKey    struct{}
+)
+
+// WithAttempt は現在の実行回数（1始まり）をコンテキストに設定する
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// AttemptFromContext は現在の実行回数を返す（不明な場合は0）
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// DefinitionFromContext は実行中のタスクの定義を返す
+func DefinitionFromContext(ctx context.Context) *Definition {
+	def, _ := ctx.Value(definitionKey{}).(*Definition)
+	return def
+}
+
+// IsFinalAttempt は現在の実行が最後の試行かどうかを返す
+// 実行回数が不明な場合は再実行されない前提でtrueを返す
+func IsFinalAttempt(ctx context.Context) bool {
+	def := DefinitionFromContext(ctx)
+	attempt := AttemptFromContext(ctx)
+	if def == nil || attempt == 0 {
+		return true
+	}
+	return attempt >= def.MaxAttempts()
+}
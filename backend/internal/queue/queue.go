@@ -2,16 +2,38 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 )
 
 type IQueue interface {
 	Enqueue(ctx context.Context, task *Task) error
+	// Shutdown は新規タスクの受付を停止し、処理中のタスクの完了を待つ
+	Shutdown(ctx context.Context) error
 }
 
+// HandlerFunc はタスクを処理する関数
+type HandlerFunc func(ctx context.Context, task *Task) error
+
 type Task struct {
-	ID      string      `json:"id"`
-	Version int         `json:"version"`
-	Type    string      `json:"type"`
-	Data    interface{} `json:"data"` // VlogInput または map[string]interface{}
-	Status  string      `json:"status"`
+	ID      string          `json:"id"`
+	Version int             `json:"version"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"` // タスクタイプごとのペイロード（Registryに登録した型で復元する）
+	Status  string          `json:"status"`
+}
+
+// NewTask はペイロードをJSONにエンコードしてタスクを作成する
+func NewTask(taskType, id string, version int, payload interface{}, status string) (*Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+	return &Task{
+		ID:      id,
+		Version: version,
+		Type:    taskType,
+		Data:    data,
+		Status:  status,
+	}, nil
 }
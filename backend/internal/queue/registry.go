@@ -0,0 +1,165 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+)
+
+// TaskEndpointPrefix はタスク実行エンドポイントの共通パス
+const TaskEndpointPrefix = "/internal/tasks"
+
+// タスクタイプ
+const (
+	TaskTypeProcessVLog          = "ProcessVLogTask"
+	TaskTypeProcessMediaAnalysis = "ProcessMediaAnalysisTask"
+)
+
+var (
+	ErrUnknownTaskType = errors.New("unknown task type")
+	ErrInvalidPayload  = errors.New("invalid task payload")
+)
+
+// MediaAnalysisPayload はメディア分析タスクのペイロード
+type MediaAnalysisPayload struct {
+	UserID   string   `json:"user_id"`
+	MediaIDs []string `json:"media_ids"`
+}
+
+// Middleware はすべてのタスクの処理関数に共通処理を追加する
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Definition はタスクタイプごとの処理関数・エンドポイント・リトライ設定
+type Definition struct {
+	Type  string
+	Path  string       // TaskEndpointPrefixからの相対パス
+	Retry retry.Config // プロセス内キューでのリトライ設定（Cloud Tasksではキュー側の設定が優先される）
+
+	Requeue RequeueHook
+
+	registry *Registry
+	handle   HandlerFunc
+}
+
+// RequeueHook はデッドレターから再実行する際のタスク固有の処理
+type RequeueHook struct {
+	// Prepare は再登録前に対象レコードの状態を戻す（トランザクション内で実行され、task.Versionを変更できる）
+	Prepare func(ctx context.Context, task *Task) error
+	// Abort はタスクの登録に失敗した場合にPrepareの変更を取り消す
+	Abort func(ctx context.Context, task *Task) error
+}
+
+// DefinitionOption はタスク定義の任意設定
+type DefinitionOption func(*Definition)
+
+// WithRequeueHook はデッドレターからの再実行時の処理を設定する
+func WithRequeueHook(hook RequeueHook) DefinitionOption {
+	return func(d *Definition) {
+		d.Requeue = hook
+	}
+}
+
+// MaxAttempts は最大実行回数（初回実行 + リトライ回数）を返す
+func (d *Definition) MaxAttempts() int {
+	return d.Retry.MaxRetries + 1
+}
+
+// Endpoint はタスクを受け付けるHTTPエンドポイントのパスを返す
+func (d *Definition) Endpoint() string {
+	return TaskEndpointPrefix + "/" + d.Path
+}
+
+// Handle はミドルウェアを通してペイロードを登録した型に復元し、処理関数を実行する
+func (d *Definition) Handle(ctx context.Context, task *Task) error {
+	d.registry.mu.RLock()
+	middlewares := d.registry.middlewares
+	d.registry.mu.RUnlock()
+
+	h := d.handle
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h(context.WithValue(ctx, definitionKey{}, d), task)
+}
+
+// Registry はタスクタイプと定義の対応を保持する
+type Registry struct {
+	mu          sync.RWMutex
+	defs        map[string]*Definition
+	order       []string
+	middlewares []Middleware
+}
+
+func NewRegistry() *Registry {
+	return &Registry{defs: make(map[string]*Definition)}
+}
+
+// Register はペイロード型Pを受け取るタスクを登録する
+// 同じタスクタイプ・パスを二重に登録した場合はpanicする
+func Register[P any](r *Registry, taskType, path string, retryCfg retry.Config, handler func(ctx context.Context, task *Task, payload *P) error, opts ...DefinitionOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.defs[taskType]; ok {
+		panic(fmt.Sprintf("queue: task type %s is already registered", taskType))
+	}
+	for _, def := range r.defs {
+		if def.Path == path {
+			panic(fmt.Sprintf("queue: task path %s is already registered", path))
+		}
+	}
+
+	def := &Definition{
+		Type:     taskType,
+		Path:     path,
+		Retry:    retryCfg,
+		registry: r,
+		handle: func(ctx context.Context, task *Task) error {
+			var payload P
+			if err := json.Unmarshal(task.Data, &payload); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidPayload, task.Type, err)
+			}
+			return handler(ctx, task, &payload)
+		},
+	}
+	for _, opt := range opts {
+		opt(def)
+	}
+	r.defs[taskType] = def
+	r.order = append(r.order, taskType)
+}
+
+// Use はすべてのタスクに適用するミドルウェアを追加する（先に追加したものが外側になる）
+func (r *Registry) Use(middlewares ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Lookup はタスクタイプの定義を返す
+func (r *Registry) Lookup(taskType string) (*Definition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[taskType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTaskType, taskType)
+	}
+	return def, nil
+}
+
+// Definitions は登録順にすべての定義を返す
+func (r *Registry) Definitions() []*Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]*Definition, 0, len(r.order))
+	for _, taskType := range r.order {
+		defs = append(defs, r.defs[taskType])
+	}
+	return defs
+}
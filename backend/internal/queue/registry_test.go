@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	var received *MediaAnalysisPayload
+	Register(registry, TaskTypeProcessMediaAnalysis, "analyze-media", retry.DefaultConfig, func(ctx context.Context, task *Task, payload *MediaAnalysisPayload) error {
+		received = payload
+		return nil
+	})
+
+	t.Run("登録したタスクの定義を取得できる", func(t *testing.T) {
+		def, err := registry.Lookup(TaskTypeProcessMediaAnalysis)
+		require.NoError(t, err)
+		assert.Equal(t, "/internal/tasks/analyze-media", def.Endpoint())
+		assert.Equal(t, retry.DefaultConfig, def.Retry)
+		assert.Len(t, registry.Definitions(), 1)
+	})
+
+	t.Run("ペイロードが登録した型に復元される", func(t *testing.T) {
+		task, err := NewTask(TaskTypeProcessMediaAnalysis, "", 0, &MediaAnalysisPayload{UserID: "user-1", MediaIDs: []string{"m1", "m2"}}, "")
+		require.NoError(t, err)
+
+		def, err := registry.Lookup(task.Type)
+		require.NoError(t, err)
+		require.NoError(t, def.Handle(context.Background(), task))
+		assert.Equal(t, &MediaAnalysisPayload{UserID: "user-1", MediaIDs: []string{"m1", "m2"}}, received)
+	})
+
+	t.Run("不正なペイロードはエラーになる", func(t *testing.T) {
+		def, err := registry.Lookup(TaskTypeProcessMediaAnalysis)
+		require.NoError(t, err)
+		err = def.Handle(context.Background(), &Task{Type: TaskTypeProcessMediaAnalysis, Data: []byte(`{"media_ids":"m1"}`)})
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+
+	t.Run("未登録のタスクタイプはエラーになる", func(t *testing.T) {
+		_, err := registry.Lookup("Unknown")
+		assert.ErrorIs(t, err, ErrUnknownTaskType)
+	})
+
+	t.Run("ミドルウェアを通して処理関数が実行される", func(t *testing.T) {
+		var order []string
+		mw := func(name string) Middleware {
+			return func(next HandlerFunc) HandlerFunc {
+				return func(ctx context.Context, task *Task) error {
+					order = append(order, name)
+					return next(ctx, task)
+				}
+			}
+		}
+		r := NewRegistry()
+		Register(r, "TestTask", "test", retry.DefaultConfig, func(ctx context.Context, task *Task, payload *MediaAnalysisPayload) error {
+			order = append(order, "handler")
+			return nil
+		})
+		r.Use(mw("outer"), mw("inner"))
+
+		def, err := r.Lookup("TestTask")
+		require.NoError(t, err)
+		require.NoError(t, def.Handle(context.Background(), &Task{Type: "TestTask", Data: []byte(`{}`)}))
+		assert.Equal(t, []string{"outer", "inner", "handler"}, order)
+	})
+
+	t.Run("同じタスクタイプを二重に登録するとpanicする", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Register(registry, TaskTypeProcessMediaAnalysis, "other", retry.DefaultConfig, func(ctx context.Context, task *Task, payload *MediaAnalysisPayload) error { return nil })
+		})
+	})
+}
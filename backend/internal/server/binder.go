@@ -2,6 +2,7 @@ package server
 
 import (
 	"mime/multipart"
+	"net/http"
 	"reflect"
 	"strings"
 
@@ -23,8 +24,12 @@ func NewCustomBinder() *CustomBinder {
 // Bind リクエストデータを構造体にバインド
 func (cb *CustomBinder) Bind(i interface{}, c echo.Context) error {
 	// デフォルトのバインド処理（クエリパラメータ、JSONボディなど）
-	if err := cb.defaultBinder.Bind(i, c); err != nil && err != echo.ErrUnsupportedMediaType {
-		return err
+	// ボディのないPOST（キャンセル・動画の生成開始など）はパス・クエリパラメータのみをバインドする
+	req := c.Request()
+	if req.ContentLength != 0 || req.Method == http.MethodGet || req.Method == http.MethodDelete {
+		if err := cb.defaultBinder.Bind(i, c); err != nil && err != echo.ErrUnsupportedMediaType {
+			return err
+		}
 	}
 
 	// パスパラメータのバインド
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -11,10 +12,12 @@ import (
 	"github.com/labstack/echo/middleware"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/database/mysql"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/oidc"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
 	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
 )
 
 type RequestInfo struct {
@@ -134,6 +137,98 @@ func AuthMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// AdminMiddleware は管理者ユーザー以外のアクセスを拒否する
+// AuthMiddlewareの後に適用する
+func AdminMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			userID := Ctx.GetCtxFromUser(ctx)
+			if userID == "" {
+				return errors.MakeAuthorizationError(ctx, "ユーザー情報が見つかりません")
+			}
+
+			var user domain.User
+			if err := Ctx.GetDB(ctx).First(&user, "id = ?", userID).Error; err != nil {
+				return errors.MakeAuthorizationError(ctx, "ユーザー情報の取得に失敗しました")
+			}
+			if user.Type != constant.UserTypeAdmin {
+				return errors.MakeForbiddenError(ctx, "管理者権限が必要です")
+			}
+			return next(c)
+		}
+	}
+}
+
+// TaskAuthMiddleware はCloud Tasksが付与したOIDCトークンとX-CloudTasks-*ヘッダーを検証する
+// issuer・audience（BASE_URL）はverifierで、サービスアカウントとキュー名は設定値と照合する
+func TaskAuthMiddleware(verifier *oidc.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			env := config.GetCtxEnv(ctx)
+			req := c.Request()
+
+			if err := verifyServiceAccountToken(ctx, verifier, req, env.SERVICE_ACCOUNT_EMAIL); err != nil {
+				return err
+			}
+
+			if req.Header.Get("X-CloudTasks-QueueName") != env.CLOUD_TASKS_QUEUE_NAME || req.Header.Get("X-CloudTasks-TaskName") == "" {
+				return errors.MakeAuthorizationError(ctx, "Cloud Tasksからのリクエストではありません")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SchedulerAuthMiddleware はCloud Schedulerが付与したOIDCトークンとX-CloudSchedulerヘッダーを検証する
+// サービスアカウントはSCHEDULER_SERVICE_ACCOUNT（未設定の場合はSERVICE_ACCOUNT_EMAIL）と照合する
+func SchedulerAuthMiddleware(verifier *oidc.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			env := config.GetCtxEnv(ctx)
+			req := c.Request()
+
+			email := env.SCHEDULER_SERVICE_ACCOUNT
+			if email == "" {
+				email = env.SERVICE_ACCOUNT_EMAIL
+			}
+			if err := verifyServiceAccountToken(ctx, verifier, req, email); err != nil {
+				return err
+			}
+
+			if req.Header.Get("X-CloudScheduler") != "true" {
+				return errors.MakeAuthorizationError(ctx, "Cloud Schedulerからのリクエストではありません")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// verifyServiceAccountToken はAuthorizationヘッダーのOIDCトークンを検証し、発行元のサービスアカウントを照合する
+func verifyServiceAccountToken(ctx context.Context, verifier *oidc.Verifier, req *http.Request, email string) error {
+	authorization := req.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return errors.MakeAuthorizationError(ctx, "認証トークンが見つかりません")
+	}
+
+	claims, err := verifier.Verify(ctx, token)
+	if err != nil {
+		logger.Warn(ctx, "failed to verify service account token", "error", err)
+		return errors.MakeAuthorizationError(ctx, "無効な認証トークンです")
+	}
+	if email == "" || claims.Email != email || !claims.EmailVerified {
+		logger.Warn(ctx, "unexpected service account token email", "email", claims.Email)
+		return errors.MakeAuthorizationError(ctx, "許可されていないサービスアカウントです")
+	}
+	return nil
+}
+
 func CORS() echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{
@@ -145,9 +240,10 @@ func CORS() echo.MiddlewareFunc {
 			echo.GET,
 			echo.OPTIONS,
 			echo.PUT,
+			echo.PATCH,
 			echo.DELETE,
 		},
-		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Requested-With", "X-Share-Password"},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours in seconds
 	})
@@ -221,10 +317,11 @@ type CustomValidator struct {
 }
 
 // Validate implements the echo.Validator interface
+// バリデーションエラーは不正な引数のエラー（422）として返す
 func (cv *CustomValidator) Validate(i interface{}) error {
 	err := cv.validator.Struct(i)
 	if err != nil {
-		return err
+		return errors.MakeInvalidArgumentError(context.Background(), err.Error())
 	}
 	return nil
 }
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/oidc"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAuthMiddleware(t *testing.T) {
+	env := &config.Config{
+		BASE_URL:               "https://api.example.com",
+		SERVICE_ACCOUNT_EMAIL:  "tasks@example.iam.gserviceaccount.com",
+		CLOUD_TASKS_QUEUE_NAME: "test-queue",
+	}
+	signer, err := oidc.NewLocalSigner()
+	require.NoError(t, err)
+	middleware := TaskAuthMiddleware(oidc.NewVerifier(signer, oidc.GoogleIssuer, env.BASE_URL))
+
+	sign := func(t *testing.T, email string) string {
+		t.Helper()
+		now := time.Now()
+		token, err := signer.Sign(&oidc.Claims{
+			Issuer:        oidc.GoogleIssuer,
+			Audience:      oidc.Audience{env.BASE_URL},
+			Email:         email,
+			EmailVerified: true,
+			IssuedAt:      now.Unix(),
+			ExpiresAt:     now.Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	// serve はミドルウェアを通してリクエストを処理し、後続のハンドラーが呼ばれたかを返す
+	serve := func(header http.Header) (bool, error) {
+		req := httptest.NewRequest(http.MethodPost, "/internal/tasks/create-vlog", nil)
+		req.Header = header
+		req = req.WithContext(context.WithValue(req.Context(), config.CtxEnvKey, env))
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		called := false
+		err := middleware(func(c echo.Context) error {
+			called = true
+			return nil
+		})(c)
+		return called, err
+	}
+
+	validHeader := func(token string) http.Header {
+		h := http.Header{}
+		h.Set("Authorization", "Bearer "+token)
+		h.Set("X-CloudTasks-QueueName", env.CLOUD_TASKS_QUEUE_NAME)
+		h.Set("X-CloudTasks-TaskName", "task-1")
+		return h
+	}
+
+	t.Run("正しいトークンとヘッダーのリクエストは通過する", func(t *testing.T) {
+		called, err := serve(validHeader(sign(t, env.SERVICE_ACCOUNT_EMAIL)))
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("トークンがないリクエストは拒否される", func(t *testing.T) {
+		h := validHeader("")
+		h.Del("Authorization")
+		called, err := serve(h)
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("サービスアカウントが異なるトークンは拒否される", func(t *testing.T) {
+		called, err := serve(validHeader(sign(t, "other@example.iam.gserviceaccount.com")))
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("キュー名が異なるリクエストは拒否される", func(t *testing.T) {
+		h := validHeader(sign(t, env.SERVICE_ACCOUNT_EMAIL))
+		h.Set("X-CloudTasks-QueueName", "other-queue")
+		called, err := serve(h)
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("タスク名がないリクエストは拒否される", func(t *testing.T) {
+		h := validHeader(sign(t, env.SERVICE_ACCOUNT_EMAIL))
+		h.Del("X-CloudTasks-TaskName")
+		called, err := serve(h)
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestSchedulerAuthMiddleware(t *testing.T) {
+	signer, err := oidc.NewLocalSigner()
+	require.NoError(t, err)
+	const baseURL = "https://api.example.com"
+	middleware := SchedulerAuthMiddleware(oidc.NewVerifier(signer, oidc.GoogleIssuer, baseURL))
+
+	sign := func(t *testing.T, email string) string {
+		t.Helper()
+		now := time.Now()
+		token, err := signer.Sign(&oidc.Claims{
+			Issuer:        oidc.GoogleIssuer,
+			Audience:      oidc.Audience{baseURL},
+			Email:         email,
+			EmailVerified: true,
+			IssuedAt:      now.Unix(),
+			ExpiresAt:     now.Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	// serve はミドルウェアを通してリクエストを処理し、後続のハンドラーが呼ばれたかを返す
+	serve := func(env *config.Config, header http.Header) (bool, error) {
+		req := httptest.NewRequest(http.MethodPost, "/internal/scheduler/subscriptions", nil)
+		req.Header = header
+		req = req.WithContext(context.WithValue(req.Context(), config.CtxEnvKey, env))
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		called := false
+		err := middleware(func(c echo.Context) error {
+			called = true
+			return nil
+		})(c)
+		return called, err
+	}
+
+	validHeader := func(token string) http.Header {
+		h := http.Header{}
+		h.Set("Authorization", "Bearer "+token)
+		h.Set("X-CloudScheduler", "true")
+		return h
+	}
+
+	env := &config.Config{
+		BASE_URL:                  baseURL,
+		SERVICE_ACCOUNT_EMAIL:     "tasks@example.iam.gserviceaccount.com",
+		SCHEDULER_SERVICE_ACCOUNT: "scheduler@example.iam.gserviceaccount.com",
+	}
+
+	t.Run("スケジューラーのサービスアカウントのトークンは通過する", func(t *testing.T) {
+		called, err := serve(env, validHeader(sign(t, env.SCHEDULER_SERVICE_ACCOUNT)))
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("トークンがないリクエストは拒否される", func(t *testing.T) {
+		h := validHeader("")
+		h.Del("Authorization")
+		called, err := serve(env, h)
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("スケジューラー以外のサービスアカウントのトークンは拒否される", func(t *testing.T) {
+		called, err := serve(env, validHeader(sign(t, env.SERVICE_ACCOUNT_EMAIL)))
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("X-CloudSchedulerヘッダーがないリクエストは拒否される", func(t *testing.T) {
+		h := validHeader(sign(t, env.SCHEDULER_SERVICE_ACCOUNT))
+		h.Del("X-CloudScheduler")
+		called, err := serve(env, h)
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("スケジューラーのサービスアカウントが未設定の場合はSERVICE_ACCOUNT_EMAILと照合する", func(t *testing.T) {
+		fallback := &config.Config{BASE_URL: baseURL, SERVICE_ACCOUNT_EMAIL: env.SERVICE_ACCOUNT_EMAIL}
+		called, err := serve(fallback, validHeader(sign(t, env.SERVICE_ACCOUNT_EMAIL)))
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+}
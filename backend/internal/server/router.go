@@ -1,5 +1,7 @@
 package server
 
+import "github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+
 func (s *Server) SetupApplicationRoute() {
 	apiRoot := s.Engine.Group("/api")
 	// 認証API
@@ -19,6 +21,19 @@ func (s *Server) SetupApplicationRoute() {
 		users.POST("", s.User.Create)        // ユーザー作成
 		users.PUT("/:id", s.User.Update)     // ユーザー更新
 		users.DELETE("/:id", s.User.Delete)  // ユーザー削除
+
+		// /api/user と同じAPI
+		users.GET("/me/tokens", s.Token.GetTokens)
+		users.POST("/me/tokens/estimate", s.Token.Estimate)
+		users.GET("/me/transactions", s.Token.ListTransactions)
+	}
+
+	// ログインユーザーのトークンAPI
+	user := apiRoot.Group("/user", AuthMiddleware())
+	{
+		user.GET("/tokens", s.Token.GetTokens)              // トークン残高・次回のトークン付与日時取得
+		user.POST("/tokens/estimate", s.Token.Estimate)     // VLog生成の消費トークン見積もり
+		user.GET("/transactions", s.Token.ListTransactions) // トークン取引履歴取得
 	}
 
 	// 画像管理API
@@ -34,12 +49,36 @@ func (s *Server) SetupApplicationRoute() {
 	// VLog管理API
 	vlogs := apiRoot.Group("/vlogs", AuthMiddleware())
 	{
-		vlogs.GET("", s.VLog.List)                    // VLog一覧取得
-		vlogs.GET("/:id", s.VLog.GetByID)             // IDでVLog取得
-		vlogs.GET("/:id/stream", s.VLog.StreamStatus) // VLog進捗ストリーミング
-		vlogs.DELETE("/:id", s.VLog.Delete)           // VLog削除
+		vlogs.GET("", s.VLog.List)                            // VLog一覧取得
+		vlogs.GET("/:id", s.VLog.GetByID)                     // IDでVLog取得
+		vlogs.PUT("/:id", s.VLog.Update)                      // タイトル・説明文・字幕の編集
+		vlogs.GET("/:id/stream", s.VLog.StreamStatus)         // VLog進捗ストリーミング
+		vlogs.GET("/:id/timeline", s.VLog.Timeline)           // VLog生成のステップごとの実行記録
+		vlogs.GET("/:id/subtitles", s.VLog.Subtitles)         // 字幕ファイル取得（srt / vtt / json）
+		vlogs.DELETE("/:id", s.VLog.Delete)                   // VLog削除
+		vlogs.POST("/:id/retry", s.VLog.Retry)                // 失敗したVLog生成の再実行
+		vlogs.POST("/:id/cancel", s.VLog.Cancel)              // VLog生成のキャンセル
+		vlogs.GET("/:id/storyboard", s.VLog.Storyboard)       // 絵コンテ取得
+		vlogs.PUT("/:id/storyboard", s.VLog.UpdateStoryboard) // 絵コンテの編集（確認待ちの間のみ）
+		vlogs.POST("/:id/render", s.VLog.Render)              // 確認した絵コンテで動画の生成を開始
+		vlogs.GET("/:id/share", s.Share.Get)                  // 共有リンク取得
+		vlogs.POST("/:id/share", s.Share.Issue)               // 共有リンクの再発行
+		vlogs.DELETE("/:id/share", s.Share.Revoke)            // 共有リンクの無効化
 	}
 
+	// VLog下書きAPI（分析と構成の作成のみを行い、下書きを編集してから動画を生成する）
+	drafts := vlogs.Group("/drafts")
+	{
+		drafts.POST("", s.Agent.CreateDraft)                        // 下書き作成
+		drafts.GET("/:id", s.VLog.Draft)                            // 下書き取得
+		drafts.PATCH("/:id", s.VLog.UpdateDraft)                    // タイトル・メディアの並び順・スタイルの編集
+		drafts.PATCH("/:id/scenes/:index", s.VLog.UpdateDraftScene) // シーンの編集（1始まりのシーン番号）
+		drafts.POST("/:id/render", s.VLog.Render)                   // 下書きで動画の生成を開始
+	}
+
+	// 共有API（共有コードで閲覧するため認証不要）
+	apiRoot.GET("/share/:code", s.Share.GetShared) // 共有リンクからVLog取得
+
 	// AIエージェントAPI
 	agentGroup := apiRoot.Group("/agent", AuthMiddleware())
 	{
@@ -58,10 +97,38 @@ func (s *Server) SetupApplicationRoute() {
 		notifications.DELETE("", s.Notification.DeleteAllNotifications) // 全通知削除
 	}
 
-	// 内部タスクAPI（Cloud Tasksからの呼び出し用、自動でIAM認証される）
-	internal := s.Engine.Group("/internal")
+	// 決済API
+	billing := apiRoot.Group("/billing", AuthMiddleware())
+	{
+		billing.POST("/checkout", s.Billing.CreateCheckout) // Checkout Session作成
+	}
+
+	// 管理者API
+	admin := apiRoot.Group("/admin", AuthMiddleware(), AdminMiddleware())
+	{
+		admin.GET("/dead-letters", s.DeadLetter.List)                 // デッドレター一覧取得
+		admin.GET("/dead-letters/:id", s.DeadLetter.GetByID)          // デッドレター詳細取得
+		admin.POST("/dead-letters/:id/requeue", s.DeadLetter.Requeue) // デッドレターのタスクを再実行
+	}
+
+	// Webhook（Stripe署名で検証するため認証不要）
+	webhooks := apiRoot.Group("/webhooks")
+	{
+		webhooks.POST("/stripe", s.Billing.StripeWebhook)
+	}
+
+	// 内部タスクAPI（Cloud Tasksからの呼び出し用、OIDCトークンで認証する）
+	// タスクレジストリに登録されたタスクごとにエンドポイントを生成
+	tasks := s.Engine.Group(queue.TaskEndpointPrefix, TaskAuthMiddleware(s.TaskVerifier))
+	{
+		for _, def := range s.Task.Definitions() {
+			tasks.POST("/"+def.Path, s.Task.Process(def))
+		}
+	}
+
+	// Cloud Schedulerからの定期実行用（OIDCトークンで認証する）
+	internal := s.Engine.Group("/internal", SchedulerAuthMiddleware(s.TaskVerifier))
 	{
-		internal.POST("/tasks/create-vlog", s.Agent.ProcessVLogTask)
-		internal.POST("/tasks/analyze-media", s.Agent.ProcessMediaAnalysisTask)
+		internal.POST("/scheduler/subscriptions", s.Scheduler.RunSubscriptionJobs) // サブスクリプションのトークン付与・期限切れ処理
 	}
 }
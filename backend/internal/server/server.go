@@ -12,13 +12,24 @@ import (
 
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/handler"
 	cloudtask "github.com/o-ga09/zenn-hackthon-2026/internal/infra/cloudTask"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/database/mysql"
+	fakevideo "github.com/o-ga09/zenn-hackthon-2026/internal/infra/fakeVideo"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/genkit"
+	localqueue "github.com/o-ga09/zenn-hackthon-2026/internal/infra/localQueue"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/oidc"
 	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/storage"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/stripe"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/infra/voicevox"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/progress"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/service"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/config"
+	Ctx "github.com/o-ga09/zenn-hackthon-2026/pkg/context"
 	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/video"
 )
 
 type Server struct {
@@ -28,8 +39,17 @@ type Server struct {
 	Auth         handler.IAuthServer
 	Image        handler.IImageServer
 	VLog         handler.IVLogServer
+	Share        handler.IShareServer
 	Agent        handler.IAgentServer
 	Notification handler.INotificationHandler
+	Token        handler.ITokenServer
+	Billing      handler.IBillingServer
+	Scheduler    handler.ISchedulerServer
+	Task         handler.ITaskServer
+	DeadLetter   handler.IDeadLetterServer
+	TaskVerifier *oidc.Verifier
+	Queue        queue.IQueue
+	Progress     progress.IBus
 }
 
 func New(ctx context.Context) *Server {
@@ -42,44 +62,80 @@ func New(ctx context.Context) *Server {
 	userHandler := handler.NewUserServer(&mysql.UserRepository{}, r2Storage)
 	authHandler := handler.NewAuthServer(&mysql.UserRepository{}, r2Storage)
 	imageHandler := handler.NewImageServer(&mysql.MediaRepository{}, r2Storage, &mysql.MediaAnalyticsRepository{})
-	vlogHandler := handler.NewVLogServer(&mysql.VLogRepository{})
 
-	// GCSクライアントの初期化
-	gcsClient, err := config.GetGCSClient(ctx)
-	if err != nil {
-		log.Printf("warning: failed to initialize GCS client: %v", err)
-		// GCSクライアント初期化失敗は警告のみ（Veo機能が使えなくなる）
-	}
-
-	// GenAIクライアントの初期化
-	genaiClient, err := config.GetGenAIClient(ctx)
-	if err != nil {
-		log.Printf("warning: failed to initialize GenAI client: %v", err)
-		// GenAIクライアント初期化失敗は警告のみ（Veo機能が使えなくなる）
-	}
-
-	// Cloud Tasks クライアントの初期化
-	taskClient, err := cloudtask.NewClient(ctx)
-	if err != nil {
-		log.Printf("warning: failed to initialize cloud tasks client: %v", err)
+	// タスクキューの初期化（QUEUE_DRIVERで切り替え）
+	// タスクの処理関数・エンドポイントはハンドラー作成後にレジストリへ登録する
+	taskRegistry := queue.NewRegistry()
+	var taskQueue queue.IQueue
+	switch env.QUEUE_DRIVER {
+	case "local":
+		taskQueue = localqueue.New(taskRegistry, env.LOCAL_QUEUE_WORKERS, env.LOCAL_QUEUE_CAPACITY)
+	default:
+		taskClient, err := cloudtask.NewClient(ctx, taskRegistry)
+		if err != nil {
+			log.Fatalf("failed to initialize cloud tasks client: %v", err)
+		}
+		taskQueue = taskClient
 	}
 
 	// GenkitAgent の初期化（依存性注入）
 	mediaAnalyticsRepo := &mysql.MediaAnalyticsRepository{}
 	txManager := mysql.NewTransactionManager()
+	musicTrackRepo := &mysql.MusicTrackRepository{}
+	storyboardRepo := &mysql.VlogStoryboardRepository{}
 
 	genkitAgent := genkit.NewGenkitAgent(ctx,
 		genkit.WithAgentStorage(r2Storage),
-		genkit.WithAgentGCSClient(gcsClient),
-		genkit.WithAgentGenAIClient(genaiClient),
+		genkit.WithAgentVideoGenerator(newVideoGenerator(ctx, env)),
+		genkit.WithAgentThumbnailPreview(env.THUMBNAIL_PREVIEW_FORMAT),
+		genkit.WithAgentSubtitleFont(env.SUBTITLE_FONT_NAME),
+		genkit.WithAgentSpeechSynthesizer(newSpeechSynthesizer(env)),
 		genkit.WithAgentMediaAnalyticsRepository(mediaAnalyticsRepo),
+		genkit.WithAgentMusicTrackRepository(musicTrackRepo),
 		genkit.WithBaseURL(env.BASE_URL),
 	)
 	vlogRepo := &mysql.VLogRepository{}
+	vlogStepRepo := &mysql.VlogStepRepository{}
 	mediaRepo := &mysql.MediaRepository{}
 	notificationRepo := &mysql.NotificationRepository{}
-	agentHandler := handler.NewAgentServer(ctx, r2Storage, genkitAgent, vlogRepo, mediaRepo, mediaAnalyticsRepo, taskClient, txManager, notificationRepo)
+	tokenTxRepo := &mysql.TokenTransactionRepository{}
+	tokenLedger := service.NewTokenLedger(&mysql.UserRepository{}, tokenTxRepo, txManager)
+	progressBus, err := newProgressBus(ctx, env)
+	if err != nil {
+		log.Fatalf("failed to initialize progress bus: %v", err)
+	}
+	shareService := service.NewVlogShareService(&mysql.VlogShareRepository{}, vlogRepo, txManager, env.BASE_URL)
+	agentHandler := handler.NewAgentServer(ctx, r2Storage, genkitAgent, vlogRepo, mediaRepo, mediaAnalyticsRepo, taskQueue, txManager, notificationRepo, tokenLedger, progressBus, vlogStepRepo, shareService, musicTrackRepo, storyboardRepo)
+	agentHandler.RegisterTasks(taskRegistry)
+	// 再配信されたタスクの重複実行を防ぐ
+	// 再試行回数を使い切ったタスクはデッドレターに移動する
+	deadLetterRepo := &mysql.DeadLetterTaskRepository{}
+	taskRegistry.Use(service.TaskExecutionMiddleware(service.NewTaskExecutionGuard(&mysql.TaskExecutionRepository{}, deadLetterRepo, txManager)))
+	taskHandler := handler.NewTaskServer(taskRegistry)
+	deadLetterService := service.NewDeadLetterService(deadLetterRepo, taskRegistry, taskQueue, txManager)
+	deadLetterHandler := handler.NewDeadLetterServer(deadLetterRepo, deadLetterService)
+	vlogHandler := handler.NewVLogServer(vlogRepo, vlogStepRepo, deadLetterService, tokenLedger, txManager, progressBus, storyboardRepo, taskQueue)
+	shareHandler := handler.NewShareServer(vlogRepo, shareService)
+	taskVerifier, err := newTaskVerifier(env)
+	if err != nil {
+		log.Fatalf("failed to initialize task verifier: %v", err)
+	}
 	notificationHandler := handler.NewNotificationHandler(notificationRepo)
+	subscriptionRepo := &mysql.SubscriptionRepository{}
+	tokenHandler := handler.NewTokenServer(&mysql.UserRepository{}, tokenTxRepo, subscriptionRepo, mediaRepo, mediaAnalyticsRepo)
+	billingService := service.NewBillingService(
+		stripe.NewClient(ctx),
+		&mysql.UserRepository{},
+		&mysql.PaymentRepository{},
+		subscriptionRepo,
+		&mysql.CheckoutSessionRepository{},
+		&mysql.StripeWebhookEventRepository{},
+		tokenLedger,
+		txManager,
+	)
+	billingHandler := handler.NewBillingServer(billingService)
+	subscriptionScheduler := service.NewSubscriptionScheduler(subscriptionRepo, &mysql.UserRepository{}, notificationRepo, tokenLedger, txManager)
+	schedulerHandler := handler.NewSchedulerServer(subscriptionScheduler)
 
 	// Echoインスタンス作成
 	e := echo.New()
@@ -93,11 +149,97 @@ func New(ctx context.Context) *Server {
 		Auth:         authHandler,
 		Image:        imageHandler,
 		VLog:         vlogHandler,
+		Share:        shareHandler,
 		Agent:        agentHandler,
 		Notification: notificationHandler,
+		Token:        tokenHandler,
+		Billing:      billingHandler,
+		Scheduler:    schedulerHandler,
+		Task:         taskHandler,
+		DeadLetter:   deadLetterHandler,
+		TaskVerifier: taskVerifier,
+		Queue:        taskQueue,
+		Progress:     progressBus,
 	}
 }
 
+// newProgressBus はPROGRESS_BUS_DRIVERに応じて進捗イベントの配信方式を作成する
+// mysqlの場合はインスタンスをまたいで配信するため、テーブルをポーリングするポーラーを起動する
+func newProgressBus(ctx context.Context, env *config.Config) (progress.IBus, error) {
+	if env.PROGRESS_BUS_DRIVER == "memory" {
+		return progress.NewMemoryBus(), nil
+	}
+
+	db, err := mysql.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bus := progress.NewStoreBus(&mysql.ProgressEventRepository{}, env.PROGRESS_POLL_INTERVAL)
+	if err := bus.Start(Ctx.SetDB(ctx, db)); err != nil {
+		return nil, err
+	}
+	return bus, nil
+}
+
+// newVideoGenerator はVIDEO_GENERATOR_DRIVERに応じてVLogの動画生成バックエンドを作成する
+// fakeの場合はネットワークに接続せず、ffmpegで単色のクリップを生成する
+func newVideoGenerator(ctx context.Context, env *config.Config) agent.IVideoGenerator {
+	if env.VIDEO_GENERATOR_DRIVER == "fake" {
+		return fakevideo.New(video.DefaultTools)
+	}
+
+	// GCSクライアントの初期化
+	gcsClient, err := config.GetGCSClient(ctx)
+	if err != nil {
+		log.Printf("warning: failed to initialize GCS client: %v", err)
+		// GCSクライアント初期化失敗は警告のみ（Veo機能が使えなくなる）
+	}
+
+	// GenAIクライアントの初期化
+	genaiClient, err := config.GetGenAIClient(ctx)
+	if err != nil {
+		log.Printf("warning: failed to initialize GenAI client: %v", err)
+		// GenAIクライアント初期化失敗は警告のみ（Veo機能が使えなくなる）
+	}
+	return genkit.NewVeoVideoGenerator(genaiClient, gcsClient, genkit.DefaultFlowConfig())
+}
+
+// newSpeechSynthesizer はVOICEVOX_URLが設定されている場合にナレーションの音声合成を作成する
+func newSpeechSynthesizer(env *config.Config) agent.ISpeechSynthesizer {
+	if env.VOICEVOX_URL == "" {
+		return nil
+	}
+	return voicevox.NewClient(env.VOICEVOX_URL)
+}
+
+// newTaskVerifier はTASK_AUTH_MODEに応じて内部タスクAPIのトークン検証器を作成する
+// localの場合は起動時に生成した署名鍵で検証し、動作確認用のトークンをログに出力する
+func newTaskVerifier(env *config.Config) (*oidc.Verifier, error) {
+	if env.TASK_AUTH_MODE != "local" {
+		return oidc.NewVerifier(oidc.NewRemoteKeySet(env.TASK_OIDC_JWKS_URL), env.TASK_OIDC_ISSUER, env.BASE_URL), nil
+	}
+
+	signer, err := oidc.NewLocalSigner()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	token, err := signer.Sign(&oidc.Claims{
+		Issuer:        env.TASK_OIDC_ISSUER,
+		Subject:       env.SERVICE_ACCOUNT_EMAIL,
+		Audience:      oidc.Audience{env.BASE_URL},
+		Email:         env.SERVICE_ACCOUNT_EMAIL,
+		EmailVerified: true,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(24 * time.Hour).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("local task auth enabled (Authorization: Bearer %s)", token)
+	return oidc.NewVerifier(signer, env.TASK_OIDC_ISSUER, env.BASE_URL), nil
+}
+
 func (s *Server) Run(ctx context.Context) error {
 	// ミドルウェアの設定
 	s.Engine.Use(middleware.Recover())
@@ -138,10 +280,21 @@ func (s *Server) Run(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
+	// SSEの購読を終了してからサーバーをシャットダウンする
+	if err := s.Progress.Close(); err != nil {
+		logger.Error(ctx, fmt.Sprintf("failed to close progress bus: %v", err))
+	}
+
 	// サーバーのシャットダウン
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error(ctx, fmt.Sprintf("failed to shutdown server: %v", err))
 		return err
 	}
+
+	// キューに残ったタスクの完了を待機
+	if err := s.Queue.Shutdown(ctx); err != nil {
+		logger.Error(ctx, fmt.Sprintf("failed to drain task queue: %v", err))
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// billingProduct は課金商品の内容
+type billingProduct struct {
+	paymentType string
+	plan        string
+	tokens      int
+	amount      int // 金額（円）
+}
+
+// 課金商品一覧（docs/requirements/012_billing_and_pricing.md）
+var billingProducts = map[string]billingProduct{
+	domain.BillingProductTokens100:  {paymentType: domain.PaymentTypeTokenPurchase, tokens: 100, amount: 980},
+	domain.BillingProductTokens500:  {paymentType: domain.PaymentTypeTokenPurchase, tokens: 500, amount: 4500},
+	domain.BillingProductTokens1000: {paymentType: domain.PaymentTypeTokenPurchase, tokens: 1000, amount: 8000},
+	domain.BillingProductMonthly:    {paymentType: domain.PaymentTypeSubscription, plan: domain.SubscriptionPlanMonthly, tokens: constant.PremiumPlanMonthlyTokens, amount: 1980},
+	domain.BillingProductYearly:     {paymentType: domain.PaymentTypeSubscription, plan: domain.SubscriptionPlanYearly, tokens: constant.PremiumPlanMonthlyTokens, amount: 19800},
+}
+
+// IBillingService は決済（Stripe Checkout / Webhook）を扱うインターフェース
+type IBillingService interface {
+	// CreateCheckout はCheckout Sessionを作成する（同一clientRequestIDの場合は作成済みのセッションを返す）
+	CreateCheckout(ctx context.Context, userID, clientRequestID, product, successURL, cancelURL string) (*domain.CheckoutSession, error)
+	// HandleWebhook は署名を検証してWebhookイベントを処理する（同一イベントIDは一度だけ処理する）
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type BillingService struct {
+	stripeClient     domain.IStripeClient
+	userRepo         domain.IUserRepository
+	paymentRepo      domain.IPaymentRepository
+	subscriptionRepo domain.ISubscriptionRepository
+	checkoutRepo     domain.ICheckoutSessionRepository
+	eventRepo        domain.IStripeWebhookEventRepository
+	tokenLedger      ITokenLedger
+	txManager        domain.ITransactionManager
+}
+
+func NewBillingService(
+	stripeClient domain.IStripeClient,
+	userRepo domain.IUserRepository,
+	paymentRepo domain.IPaymentRepository,
+	subscriptionRepo domain.ISubscriptionRepository,
+	checkoutRepo domain.ICheckoutSessionRepository,
+	eventRepo domain.IStripeWebhookEventRepository,
+	tokenLedger ITokenLedger,
+	txManager domain.ITransactionManager,
+) *BillingService {
+	return &BillingService{
+		stripeClient:     stripeClient,
+		userRepo:         userRepo,
+		paymentRepo:      paymentRepo,
+		subscriptionRepo: subscriptionRepo,
+		checkoutRepo:     checkoutRepo,
+		eventRepo:        eventRepo,
+		tokenLedger:      tokenLedger,
+		txManager:        txManager,
+	}
+}
+
+// CreateCheckout はCheckout Sessionを作成する
+func (s *BillingService) CreateCheckout(ctx context.Context, userID, clientRequestID, product, successURL, cancelURL string) (*domain.CheckoutSession, error) {
+	p, ok := billingProducts[product]
+	if !ok {
+		return nil, errors.MakeInvalidArgumentError(ctx, "不正な課金商品です")
+	}
+
+	existing, err := s.checkoutRepo.FindByClientRequestID(ctx, userID, clientRequestID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(ctx, err)
+	}
+	if existing != nil {
+		if existing.Product != product {
+			return nil, errors.MakeConflictError(ctx, "同じリクエストIDで別の商品が指定されています")
+		}
+		return existing, nil
+	}
+
+	mode := "payment"
+	if p.paymentType == domain.PaymentTypeSubscription {
+		mode = "subscription"
+	}
+	session, err := s.stripeClient.CreateCheckoutSession(ctx, &domain.StripeCheckoutParams{
+		Product:           product,
+		Mode:              mode,
+		SuccessURL:        successURL,
+		CancelURL:         cancelURL,
+		ClientReferenceID: userID,
+		IdempotencyKey:    fmt.Sprintf("%s:%s", userID, clientRequestID),
+		Metadata: map[string]string{
+			"user_id": userID,
+			"product": product,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+
+	checkout := &domain.CheckoutSession{
+		UserID:          userID,
+		ClientRequestID: clientRequestID,
+		StripeSessionID: session.ID,
+		Product:         product,
+		Type:            p.paymentType,
+		Plan:            p.plan,
+		Tokens:          p.tokens,
+		Amount:          p.amount,
+		URL:             session.URL,
+		Status:          domain.CheckoutSessionStatusOpen,
+	}
+	if err := s.checkoutRepo.Create(ctx, checkout); err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return checkout, nil
+}
+
+// HandleWebhook はWebhookイベントを処理する
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.stripeClient.ParseWebhookEvent(payload, signature)
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidStripeSignature) {
+			return errors.MakeBusinessError(ctx, errors.ErrInvalidStripeSignature.Error())
+		}
+		return errors.Wrap(ctx, err)
+	}
+
+	// サブスクリプション購入時は期間終了日時を取得するためStripeに問い合わせる（トランザクション外で行う）
+	var subscription *domain.StripeSubscription
+	if event.Type == "checkout.session.completed" && event.CheckoutSession != nil && event.CheckoutSession.SubscriptionID != "" {
+		subscription, err = s.stripeClient.GetSubscription(ctx, event.CheckoutSession.SubscriptionID)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+	}
+
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		processed, err := s.eventRepo.Exists(ctx, event.ID)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if processed {
+			return nil
+		}
+		if err := s.eventRepo.Create(ctx, &domain.StripeWebhookEvent{EventID: event.ID, Type: event.Type}); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+
+		switch event.Type {
+		case "checkout.session.completed":
+			return s.handleCheckoutCompleted(ctx, event.CheckoutSession, subscription)
+		case "customer.subscription.created", "customer.subscription.updated":
+			return s.handleSubscriptionChanged(ctx, event.Subscription, false)
+		case "customer.subscription.deleted":
+			return s.handleSubscriptionChanged(ctx, event.Subscription, true)
+		}
+		return nil
+	})
+}
+
+// handleCheckoutCompleted は決済完了時にPayment作成・トークン付与・プラン変更を行う
+func (s *BillingService) handleCheckoutCompleted(ctx context.Context, session *domain.StripeCheckoutSession, subscription *domain.StripeSubscription) error {
+	checkout, err := s.checkoutRepo.FindByStripeSessionID(ctx, session.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.MakeNotFoundError(ctx, "Checkoutセッションが見つかりません")
+		}
+		return errors.Wrap(ctx, err)
+	}
+	if checkout.Status == domain.CheckoutSessionStatusCompleted {
+		return nil
+	}
+
+	amount := session.AmountTotal
+	if amount == 0 {
+		amount = checkout.Amount
+	}
+	stripePaymentID := session.PaymentIntentID
+	if stripePaymentID == "" {
+		stripePaymentID = session.SubscriptionID
+	}
+	completedAt := time.Now()
+	if err := s.paymentRepo.Create(ctx, &domain.Payment{
+		UserID:          checkout.UserID,
+		Type:            checkout.Type,
+		Amount:          amount,
+		TokensGranted:   checkout.Tokens,
+		Status:          domain.PaymentStatusCompleted,
+		StripePaymentID: stripePaymentID,
+		StripeSessionID: session.ID,
+		CompletedAt:     &completedAt,
+	}); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	description := fmt.Sprintf("トークン購入（%dトークン）", checkout.Tokens)
+	if checkout.Type == domain.PaymentTypeSubscription {
+		description = "プレミアムプラン付与"
+	}
+	if err := s.tokenLedger.Grant(ctx, checkout.UserID, session.ID, domain.TokenTransactionTypePurchase, checkout.Tokens, description); err != nil {
+		return err
+	}
+
+	if checkout.Type == domain.PaymentTypeSubscription && subscription != nil {
+		sub, err := s.subscriptionRepo.FindByStripeSubscriptionID(ctx, subscription.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.Wrap(ctx, err)
+		}
+		if sub == nil {
+			sub = &domain.Subscription{
+				UserID:           checkout.UserID,
+				Plan:             checkout.Plan,
+				Status:           domain.SubscriptionStatusActive,
+				StripeCustomerID: subscription.CustomerID,
+				StripeSubID:      subscription.ID,
+				CurrentPeriodEnd: subscription.CurrentPeriodEnd,
+			}
+			// 初回の付与期間分は購入時に付与済み
+			refilledPeriodEnd := sub.RefillPeriodEnd(completedAt)
+			sub.LastRefilledPeriodEnd = &refilledPeriodEnd
+			if err := s.subscriptionRepo.Create(ctx, sub); err != nil {
+				return errors.Wrap(ctx, err)
+			}
+		}
+		if _, err := changeUserPlan(ctx, s.userRepo, checkout.UserID, constant.UserPlanPremium); err != nil {
+			return err
+		}
+	}
+
+	checkout.Status = domain.CheckoutSessionStatusCompleted
+	return s.checkoutRepo.Update(ctx, checkout)
+}
+
+// handleSubscriptionChanged はサブスクリプションの状態変更・プラン変更（月額⇔年額）を反映する
+// 未登録のサブスクリプションはcheckout.session.completedで作成されるため無視する
+// 課金商品にないPrice IDに変更された場合はプランを変更しない
+func (s *BillingService) handleSubscriptionChanged(ctx context.Context, stripeSub *domain.StripeSubscription, deleted bool) error {
+	if stripeSub == nil {
+		return nil
+	}
+	sub, err := s.subscriptionRepo.FindByStripeSubscriptionID(ctx, stripeSub.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.Wrap(ctx, err)
+	}
+
+	sub.Status = toSubscriptionStatus(stripeSub.Status)
+	if deleted {
+		sub.Status = domain.SubscriptionStatusCancelled
+	}
+	if !stripeSub.CurrentPeriodEnd.IsZero() && stripeSub.CurrentPeriodEnd.Unix() > 0 {
+		sub.CurrentPeriodEnd = stripeSub.CurrentPeriodEnd
+	}
+	if p, ok := billingProducts[stripeSub.Product]; ok && p.plan != "" {
+		sub.Plan = p.plan
+	} else if stripeSub.PriceID != "" {
+		logger.Warn(ctx, "unknown stripe price id", "subscription_id", stripeSub.ID, "price_id", stripeSub.PriceID)
+	}
+	if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+		return errors.Wrap(ctx, err)
+	}
+
+	plan := constant.UserPlanFree
+	if sub.Status == domain.SubscriptionStatusActive {
+		plan = constant.UserPlanPremium
+	}
+	_, err = changeUserPlan(ctx, s.userRepo, sub.UserID, plan)
+	return err
+}
+
+// changeUserPlan はユーザーのプランを変更する（変更があった場合はtrueを返す）
+func changeUserPlan(ctx context.Context, userRepo domain.IUserRepository, userID, plan string) (bool, error) {
+	user, err := userRepo.FindByID(ctx, &domain.User{BaseModel: domain.BaseModel{ID: userID}})
+	if err != nil {
+		return false, errors.Wrap(ctx, err)
+	}
+	if user.Plan == plan {
+		return false, nil
+	}
+	user.Plan = plan
+	if err := userRepo.Update(ctx, user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// toSubscriptionStatus はStripeのステータスをサブスクリプションステータスに変換する
+func toSubscriptionStatus(status string) string {
+	switch status {
+	case "active", "trialing", "past_due":
+		return domain.SubscriptionStatusActive
+	case "canceled":
+		return domain.SubscriptionStatusCancelled
+	default:
+		return domain.SubscriptionStatusExpired
+	}
+}
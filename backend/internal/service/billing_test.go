@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const testStripeSignature = "t=1,v1=valid"
+
+// fakeStripeClient はpayloadをイベントIDとして登録済みのイベントを返すStripeクライアント
+type fakeStripeClient struct {
+	events        map[string]*domain.StripeEvent
+	subscriptions map[string]*domain.StripeSubscription
+	checkoutCalls int
+}
+
+func (c *fakeStripeClient) CreateCheckoutSession(ctx context.Context, params *domain.StripeCheckoutParams) (*domain.StripeCheckoutSession, error) {
+	c.checkoutCalls++
+	id := "cs_" + params.IdempotencyKey
+	return &domain.StripeCheckoutSession{ID: id, URL: "https://checkout.stripe.test/" + id}, nil
+}
+
+func (c *fakeStripeClient) GetSubscription(ctx context.Context, id string) (*domain.StripeSubscription, error) {
+	return c.subscriptions[id], nil
+}
+
+func (c *fakeStripeClient) ParseWebhookEvent(payload []byte, signature string) (*domain.StripeEvent, error) {
+	if signature != testStripeSignature {
+		return nil, errors.ErrInvalidStripeSignature
+	}
+	return c.events[string(payload)], nil
+}
+
+type fakePaymentRepo struct {
+	domain.IPaymentRepository
+	payments []*domain.Payment
+}
+
+func (r *fakePaymentRepo) Create(ctx context.Context, payment *domain.Payment) error {
+	r.payments = append(r.payments, payment)
+	return nil
+}
+
+type fakeCheckoutSessionRepo struct {
+	sessions []*domain.CheckoutSession
+}
+
+func (r *fakeCheckoutSessionRepo) Create(ctx context.Context, session *domain.CheckoutSession) error {
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *fakeCheckoutSessionRepo) Update(ctx context.Context, session *domain.CheckoutSession) error {
+	return nil
+}
+
+func (r *fakeCheckoutSessionRepo) FindByClientRequestID(ctx context.Context, userID, clientRequestID string) (*domain.CheckoutSession, error) {
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.ClientRequestID == clientRequestID {
+			return session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCheckoutSessionRepo) FindByStripeSessionID(ctx context.Context, stripeSessionID string) (*domain.CheckoutSession, error) {
+	for _, session := range r.sessions {
+		if session.StripeSessionID == stripeSessionID {
+			return session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeWebhookEventRepo struct {
+	events []*domain.StripeWebhookEvent
+}
+
+func (r *fakeWebhookEventRepo) Create(ctx context.Context, event *domain.StripeWebhookEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeWebhookEventRepo) Exists(ctx context.Context, eventID string) (bool, error) {
+	for _, event := range r.events {
+		if event.EventID == eventID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type billingTestEnv struct {
+	service          *BillingService
+	stripe           *fakeStripeClient
+	userRepo         *fakeUserRepo
+	paymentRepo      *fakePaymentRepo
+	subscriptionRepo *fakeSubscriptionRepo
+	eventRepo        *fakeWebhookEventRepo
+}
+
+func newBillingTestEnv() *billingTestEnv {
+	user := newTestUser("user-1", 0)
+	user.Plan = constant.UserPlanFree
+	env := &billingTestEnv{
+		stripe:           &fakeStripeClient{events: map[string]*domain.StripeEvent{}, subscriptions: map[string]*domain.StripeSubscription{}},
+		userRepo:         &fakeUserRepo{users: map[string]*domain.User{"user-1": user}},
+		paymentRepo:      &fakePaymentRepo{},
+		subscriptionRepo: &fakeSubscriptionRepo{},
+		eventRepo:        &fakeWebhookEventRepo{},
+	}
+	ledger := NewTokenLedger(env.userRepo, &fakeTokenTransactionRepo{}, fakeTransactionManager{})
+	env.service = NewBillingService(env.stripe, env.userRepo, env.paymentRepo, env.subscriptionRepo, &fakeCheckoutSessionRepo{}, env.eventRepo, ledger, fakeTransactionManager{})
+	return env
+}
+
+// deliver はイベントを登録し、Webhookとして受信する
+func (e *billingTestEnv) deliver(ctx context.Context, event *domain.StripeEvent) error {
+	e.stripe.events[event.ID] = event
+	return e.service.HandleWebhook(ctx, []byte(event.ID), testStripeSignature)
+}
+
+// checkoutCompleted はCheckoutを作成し、その決済完了イベントを返す
+func (e *billingTestEnv) checkoutCompleted(t *testing.T, ctx context.Context, eventID, product, subscriptionID string) *domain.StripeEvent {
+	t.Helper()
+	checkout, err := e.service.CreateCheckout(ctx, "user-1", "req-"+eventID, product, "https://example.com/success", "https://example.com/cancel")
+	require.NoError(t, err)
+	return &domain.StripeEvent{
+		ID:   eventID,
+		Type: "checkout.session.completed",
+		CheckoutSession: &domain.StripeCheckoutSession{
+			ID:              checkout.StripeSessionID,
+			SubscriptionID:  subscriptionID,
+			PaymentIntentID: "pi_" + eventID,
+		},
+	}
+}
+
+func TestBillingService_CreateCheckout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("同じクライアントリクエストIDでの再送は作成済みのセッションを返す", func(t *testing.T) {
+		env := newBillingTestEnv()
+		first, err := env.service.CreateCheckout(ctx, "user-1", "req-1", domain.BillingProductTokens500, "https://example.com/success", "https://example.com/cancel")
+		require.NoError(t, err)
+		second, err := env.service.CreateCheckout(ctx, "user-1", "req-1", domain.BillingProductTokens500, "https://example.com/success", "https://example.com/cancel")
+		require.NoError(t, err)
+
+		assert.Equal(t, first.StripeSessionID, second.StripeSessionID)
+		assert.Equal(t, 1, env.stripe.checkoutCalls)
+	})
+
+	t.Run("同じクライアントリクエストIDで別の商品を指定するとエラー", func(t *testing.T) {
+		env := newBillingTestEnv()
+		_, err := env.service.CreateCheckout(ctx, "user-1", "req-1", domain.BillingProductTokens500, "", "")
+		require.NoError(t, err)
+		_, err = env.service.CreateCheckout(ctx, "user-1", "req-1", domain.BillingProductMonthly, "", "")
+		assert.Equal(t, errors.ErrCodeConflict, errors.GetCode(err))
+	})
+
+	t.Run("課金商品にない商品はエラー", func(t *testing.T) {
+		env := newBillingTestEnv()
+		_, err := env.service.CreateCheckout(ctx, "user-1", "req-1", "tokens_999", "", "")
+		assert.Equal(t, errors.ErrCodeInValidArgument, errors.GetCode(err))
+		assert.Zero(t, env.stripe.checkoutCalls)
+	})
+}
+
+func TestBillingService_HandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	periodEnd := time.Now().AddDate(0, 1, 0)
+
+	t.Run("トークン購入の決済完了で支払いを記録してトークンを付与する", func(t *testing.T) {
+		env := newBillingTestEnv()
+		require.NoError(t, env.deliver(ctx, env.checkoutCompleted(t, ctx, "evt_1", domain.BillingProductTokens500, "")))
+
+		require.Len(t, env.paymentRepo.payments, 1)
+		assert.Equal(t, domain.PaymentTypeTokenPurchase, env.paymentRepo.payments[0].Type)
+		assert.Equal(t, 4500, env.paymentRepo.payments[0].Amount)
+		assert.Equal(t, 500, env.userRepo.balance("user-1"))
+		assert.Equal(t, constant.UserPlanFree, env.userRepo.users["user-1"].Plan)
+	})
+
+	t.Run("同じイベントIDの再送は一度だけ処理する", func(t *testing.T) {
+		env := newBillingTestEnv()
+		event := env.checkoutCompleted(t, ctx, "evt_1", domain.BillingProductTokens500, "")
+		require.NoError(t, env.deliver(ctx, event))
+		require.NoError(t, env.deliver(ctx, event))
+
+		assert.Len(t, env.eventRepo.events, 1)
+		assert.Len(t, env.paymentRepo.payments, 1)
+		assert.Equal(t, 500, env.userRepo.balance("user-1"))
+	})
+
+	t.Run("完了済みのCheckoutの別イベントではトークンを二重に付与しない", func(t *testing.T) {
+		env := newBillingTestEnv()
+		event := env.checkoutCompleted(t, ctx, "evt_1", domain.BillingProductTokens500, "")
+		require.NoError(t, env.deliver(ctx, event))
+		retried := *event
+		retried.ID = "evt_2"
+		require.NoError(t, env.deliver(ctx, &retried))
+
+		assert.Len(t, env.paymentRepo.payments, 1)
+		assert.Equal(t, 500, env.userRepo.balance("user-1"))
+	})
+
+	t.Run("署名が不正なイベントは記録しない", func(t *testing.T) {
+		env := newBillingTestEnv()
+		err := env.service.HandleWebhook(ctx, []byte("evt_1"), "t=1,v1=invalid")
+		assert.Equal(t, errors.ErrCodeBussiness, errors.GetCode(err))
+		assert.Empty(t, env.eventRepo.events)
+	})
+
+	t.Run("サブスクリプションの購入・プラン変更・解約", func(t *testing.T) {
+		env := newBillingTestEnv()
+		env.stripe.subscriptions["sub_1"] = &domain.StripeSubscription{ID: "sub_1", CustomerID: "cus_1", Status: "active", CurrentPeriodEnd: periodEnd, Product: domain.BillingProductMonthly}
+		subscriptionEvent := func(id, eventType, status, priceID, product string) *domain.StripeEvent {
+			return &domain.StripeEvent{ID: id, Type: eventType, Subscription: &domain.StripeSubscription{
+				ID: "sub_1", Status: status, CurrentPeriodEnd: periodEnd, PriceID: priceID, Product: product,
+			}}
+		}
+
+		// 購入するとプレミアムプランになり、月額プランのサブスクリプションを作成してトークンを付与する
+		require.NoError(t, env.deliver(ctx, env.checkoutCompleted(t, ctx, "evt_1", domain.BillingProductMonthly, "sub_1")))
+		require.Len(t, env.subscriptionRepo.subscriptions, 1)
+		sub := env.subscriptionRepo.subscriptions[0]
+		assert.Equal(t, domain.SubscriptionPlanMonthly, sub.Plan)
+		assert.Equal(t, domain.SubscriptionStatusActive, sub.Status)
+		assert.Equal(t, constant.UserPlanPremium, env.userRepo.users["user-1"].Plan)
+		assert.Equal(t, constant.PremiumPlanMonthlyTokens, env.userRepo.balance("user-1"))
+
+		// 年額のPrice IDに変更すると年額プランに切り替える
+		require.NoError(t, env.deliver(ctx, subscriptionEvent("evt_2", "customer.subscription.updated", "active", "price_yearly", domain.BillingProductYearly)))
+		assert.Equal(t, domain.SubscriptionPlanYearly, sub.Plan)
+		assert.Equal(t, constant.UserPlanPremium, env.userRepo.users["user-1"].Plan)
+
+		// 課金商品にないPrice IDの場合はプランを変更しない（支払い遅延中は有効なまま）
+		require.NoError(t, env.deliver(ctx, subscriptionEvent("evt_3", "customer.subscription.updated", "past_due", "price_unknown", "")))
+		assert.Equal(t, domain.SubscriptionPlanYearly, sub.Plan)
+		assert.Equal(t, domain.SubscriptionStatusActive, sub.Status)
+		assert.Equal(t, constant.UserPlanPremium, env.userRepo.users["user-1"].Plan)
+
+		// 解約すると無料プランに戻す
+		require.NoError(t, env.deliver(ctx, subscriptionEvent("evt_4", "customer.subscription.deleted", "canceled", "price_unknown", "")))
+		assert.Equal(t, domain.SubscriptionStatusCancelled, sub.Status)
+		assert.Equal(t, constant.UserPlanFree, env.userRepo.users["user-1"].Plan)
+		assert.Equal(t, constant.PremiumPlanMonthlyTokens, env.userRepo.balance("user-1"))
+	})
+
+	t.Run("未登録のサブスクリプションのイベントは無視する", func(t *testing.T) {
+		env := newBillingTestEnv()
+		require.NoError(t, env.deliver(ctx, &domain.StripeEvent{ID: "evt_1", Type: "customer.subscription.updated", Subscription: &domain.StripeSubscription{ID: "sub_unknown", Status: "active"}}))
+
+		assert.Empty(t, env.subscriptionRepo.subscriptions)
+		assert.Equal(t, constant.UserPlanFree, env.userRepo.users["user-1"].Plan)
+	})
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// IDeadLetterService はデッドレターに移動したタスクを再実行するインターフェース
+type IDeadLetterService interface {
+	// Requeue はデッドレターのタスクを新しいバージョンで再登録する
+	Requeue(ctx context.Context, id string) (*domain.DeadLetterTask, error)
+	// RequeueTask はタスクの再実行待ちのデッドレターを再登録する
+	RequeueTask(ctx context.Context, taskType, taskID string) (*domain.DeadLetterTask, error)
+}
+
+type DeadLetterService struct {
+	deadLetterRepo domain.IDeadLetterTaskRepository
+	registry       *queue.Registry
+	taskQueue      queue.IQueue
+	txManager      domain.ITransactionManager
+}
+
+func NewDeadLetterService(
+	deadLetterRepo domain.IDeadLetterTaskRepository,
+	registry *queue.Registry,
+	taskQueue queue.IQueue,
+	txManager domain.ITransactionManager,
+) *DeadLetterService {
+	return &DeadLetterService{
+		deadLetterRepo: deadLetterRepo,
+		registry:       registry,
+		taskQueue:      taskQueue,
+		txManager:      txManager,
+	}
+}
+
+// Requeue はデッドレターのタスクを再登録する
+func (s *DeadLetterService) Requeue(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	return s.requeue(ctx, func(ctx context.Context) (*domain.DeadLetterTask, error) {
+		return s.deadLetterRepo.FindByID(ctx, id)
+	})
+}
+
+// RequeueTask はタスクの再実行待ちのデッドレターを再登録する
+func (s *DeadLetterService) RequeueTask(ctx context.Context, taskType, taskID string) (*domain.DeadLetterTask, error) {
+	return s.requeue(ctx, func(ctx context.Context) (*domain.DeadLetterTask, error) {
+		return s.deadLetterRepo.FindPendingByTask(ctx, taskType, taskID)
+	})
+}
+
+// requeue は元のタスクのバージョンを上げて再登録する
+// 古いバージョンのタスクが再配信されても実行されないよう、タスク実行記録とは別のバージョンで登録する
+func (s *DeadLetterService) requeue(ctx context.Context, find func(ctx context.Context) (*domain.DeadLetterTask, error)) (*domain.DeadLetterTask, error) {
+	var (
+		deadLetter *domain.DeadLetterTask
+		def        *queue.Definition
+		task       queue.Task
+	)
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		deadLetter, err = find(ctx)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.MakeNotFoundError(ctx, "再実行できるタスクが見つかりません")
+			}
+			return errors.Wrap(ctx, err)
+		}
+		if deadLetter.Status != domain.DeadLetterStatusPending {
+			return errors.MakeConflictError(ctx, "このタスクは再実行済みです")
+		}
+
+		def, err = s.registry.Lookup(deadLetter.TaskType)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		if err := json.Unmarshal([]byte(deadLetter.Payload), &task); err != nil {
+			return errors.Wrap(ctx, err)
+		}
+		task.Version = deadLetter.TaskVersion + 1
+		if def.Requeue.Prepare != nil {
+			if err := def.Requeue.Prepare(ctx, &task); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		deadLetter.Status = domain.DeadLetterStatusRequeued
+		deadLetter.RequeuedAt = &now
+		deadLetter.RequeuedTaskVersion = task.Version
+		return s.deadLetterRepo.Update(ctx, deadLetter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// タスクはコミット後に登録する（プロセス内キューがトランザクションを引き継がないようにする）
+	if err := s.taskQueue.Enqueue(ctx, &task); err != nil {
+		if abortErr := s.abort(ctx, deadLetter, def, &task); abortErr != nil {
+			logger.Error(ctx, "failed to abort requeue", "dead_letter_id", deadLetter.ID, "error", abortErr)
+		}
+		return nil, errors.Wrap(ctx, err)
+	}
+	return deadLetter, nil
+}
+
+// abort はタスクの登録に失敗した場合にデッドレターを再実行待ちに戻す
+func (s *DeadLetterService) abort(ctx context.Context, deadLetter *domain.DeadLetterTask, def *queue.Definition, task *queue.Task) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		deadLetter.Status = domain.DeadLetterStatusPending
+		if err := s.deadLetterRepo.Update(ctx, deadLetter); err != nil {
+			return err
+		}
+		if def.Requeue.Abort != nil {
+			return def.Requeue.Abort(ctx, task)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueue struct {
+	tasks []*queue.Task
+	err   error
+}
+
+func (q *fakeQueue) Enqueue(ctx context.Context, task *queue.Task) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.tasks = append(q.tasks, task)
+	return nil
+}
+
+func (q *fakeQueue) Shutdown(ctx context.Context) error { return nil }
+
+func TestDeadLetterService_Requeue(t *testing.T) {
+	ctx := context.Background()
+	setup := func(taskQueue *fakeQueue) (*DeadLetterService, *fakeDeadLetterRepo, *[]string) {
+		var hookCalls []string
+		registry := queue.NewRegistry()
+		queue.Register(registry, queue.TaskTypeProcessVLog, "create-vlog", retry.DefaultConfig, func(ctx context.Context, task *queue.Task, _ *json.RawMessage) error {
+			return nil
+		}, queue.WithRequeueHook(queue.RequeueHook{
+			Prepare: func(ctx context.Context, task *queue.Task) error {
+				hookCalls = append(hookCalls, fmt.Sprintf("prepare:%d", task.Version))
+				return nil
+			},
+			Abort: func(ctx context.Context, task *queue.Task) error {
+				hookCalls = append(hookCalls, fmt.Sprintf("abort:%d", task.Version))
+				return nil
+			},
+		}))
+
+		repo := &fakeDeadLetterRepo{}
+		require.NoError(t, repo.Create(ctx, &domain.DeadLetterTask{
+			TaskType:    queue.TaskTypeProcessVLog,
+			TaskID:      "vlog-1",
+			TaskVersion: 2,
+			Payload:     `{"id":"vlog-1","version":2,"type":"ProcessVLogTask","data":{"user_id":"user-1"},"status":""}`,
+			Status:      domain.DeadLetterStatusPending,
+		}))
+		return NewDeadLetterService(repo, registry, taskQueue, fakeTransactionManager{}), repo, &hookCalls
+	}
+
+	t.Run("バージョンを上げて保存済みのペイロードで再登録する", func(t *testing.T) {
+		taskQueue := &fakeQueue{}
+		svc, repo, hookCalls := setup(taskQueue)
+
+		deadLetter, err := svc.RequeueTask(ctx, queue.TaskTypeProcessVLog, "vlog-1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeadLetterStatusRequeued, deadLetter.Status)
+		assert.Equal(t, 3, deadLetter.RequeuedTaskVersion)
+		assert.Equal(t, []string{"prepare:3"}, *hookCalls)
+
+		require.Len(t, taskQueue.tasks, 1)
+		assert.Equal(t, 3, taskQueue.tasks[0].Version)
+		assert.JSONEq(t, `{"user_id":"user-1"}`, string(taskQueue.tasks[0].Data))
+
+		// 再実行済みのデッドレターは再登録できない
+		_, err = svc.Requeue(ctx, repo.records[0].ID)
+		assert.Error(t, err)
+		assert.Len(t, taskQueue.tasks, 1)
+	})
+
+	t.Run("タスクの登録に失敗した場合は再実行待ちに戻す", func(t *testing.T) {
+		taskQueue := &fakeQueue{err: fmt.Errorf("queue unavailable")}
+		svc, repo, hookCalls := setup(taskQueue)
+
+		_, err := svc.Requeue(ctx, repo.records[0].ID)
+		assert.Error(t, err)
+		assert.Equal(t, domain.DeadLetterStatusPending, repo.records[0].Status)
+		assert.Equal(t, []string{"prepare:3", "abort:3"}, *hookCalls)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+)
+
+// placeRadiusMeters は同じ場所で撮影したとみなす撮影地点の距離（メートル）
+const placeRadiusMeters = 1000
+
+// ArrangeMediaItems はメディアを撮影日時の順に並べ、同じ場所で撮影したメディアをまとめる
+// 場所は最初に訪れた順に並べ、撮影地点がないメディアは直前に撮影したメディアと同じ場所として扱う
+// 撮影日時がないメディアは元の順のまま最後に並べる。Orderは1始まりの並び順に振り直す
+func ArrangeMediaItems(items []agent.MediaItem) []agent.MediaItem {
+	type timedItem struct {
+		item       agent.MediaItem
+		capturedAt time.Time
+	}
+	var timed []timedItem
+	var untimed []agent.MediaItem
+	for _, item := range items {
+		if t, err := time.Parse(time.RFC3339, item.Timestamp); err == nil {
+			timed = append(timed, timedItem{item: item, capturedAt: t})
+		} else {
+			untimed = append(untimed, item)
+		}
+	}
+	slices.SortStableFunc(timed, func(a, b timedItem) int {
+		return a.capturedAt.Compare(b.capturedAt)
+	})
+
+	// 撮影地点が近いメディアを最初に訪れた場所にまとめる
+	var places [][]agent.MediaItem
+	var anchors []*agent.GeoLocation
+	current := -1
+	for _, t := range timed {
+		if location := t.item.Location; location != nil {
+			current = slices.IndexFunc(anchors, func(anchor *agent.GeoLocation) bool {
+				return anchor != nil && distanceMeters(*anchor, *location) <= placeRadiusMeters
+			})
+			if current < 0 {
+				places = append(places, nil)
+				anchors = append(anchors, location)
+				current = len(places) - 1
+			}
+		} else if current < 0 {
+			places = append(places, nil)
+			anchors = append(anchors, nil)
+			current = len(places) - 1
+		}
+		places[current] = append(places[current], t.item)
+	}
+
+	arranged := make([]agent.MediaItem, 0, len(items))
+	for _, place := range places {
+		arranged = append(arranged, place...)
+	}
+	arranged = append(arranged, untimed...)
+	for i := range arranged {
+		arranged[i].Order = i + 1
+	}
+	return arranged
+}
+
+// TravelDateFromMedia は最初に撮影したメディアの撮影日（YYYY-MM-DD形式）を返す（撮影日時がない場合は空）
+// 撮影日は撮影日時に記録されたタイムゾーンの日付とする
+func TravelDateFromMedia(items []agent.MediaItem) string {
+	var first *time.Time
+	for _, item := range items {
+		t, err := time.Parse(time.RFC3339, item.Timestamp)
+		if err != nil {
+			continue
+		}
+		if first == nil || t.Before(*first) {
+			first = &t
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	return first.Format(time.DateOnly)
+}
+
+// distanceMeters は2地点間の距離（メートル）を返す
+func distanceMeters(a, b agent.GeoLocation) float64 {
+	const earthRadius = 6371000
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrangeMediaItems(t *testing.T) {
+	kinkakuji := &agent.GeoLocation{Latitude: 35.0394, Longitude: 135.7292}
+	kinkakujiGate := &agent.GeoLocation{Latitude: 35.0380, Longitude: 135.7297}
+	kiyomizudera := &agent.GeoLocation{Latitude: 34.9949, Longitude: 135.7850}
+
+	ids := func(items []agent.MediaItem) []string {
+		var ids []string
+		for _, item := range items {
+			ids = append(ids, item.FileID)
+		}
+		return ids
+	}
+
+	t.Run("撮影日時の順に並べ、同じ場所で撮影したメディアをまとめる", func(t *testing.T) {
+		arranged := ArrangeMediaItems([]agent.MediaItem{
+			{FileID: "kiyomizu", Timestamp: "2026-05-03T13:00:00+09:00", Location: kiyomizudera},
+			{FileID: "gate", Timestamp: "2026-05-03T16:00:00+09:00", Location: kinkakujiGate},
+			{FileID: "kinkaku", Timestamp: "2026-05-03T10:00:00+09:00", Location: kinkakuji},
+			{FileID: "lunch", Timestamp: "2026-05-03T12:00:00+09:00"},
+		})
+		assert.Equal(t, []string{"kinkaku", "lunch", "gate", "kiyomizu"}, ids(arranged))
+		assert.Equal(t, []int{1, 2, 3, 4}, []int{arranged[0].Order, arranged[1].Order, arranged[2].Order, arranged[3].Order})
+	})
+
+	t.Run("撮影日時がないメディアは元の順のまま最後に並べる", func(t *testing.T) {
+		arranged := ArrangeMediaItems([]agent.MediaItem{
+			{FileID: "screenshot-1"},
+			{FileID: "photo", Timestamp: "2026-05-03T10:00:00Z"},
+			{FileID: "screenshot-2"},
+		})
+		assert.Equal(t, []string{"photo", "screenshot-1", "screenshot-2"}, ids(arranged))
+	})
+}
+
+func TestTravelDateFromMedia(t *testing.T) {
+	t.Run("最初に撮影したメディアの撮影地の日付を返す", func(t *testing.T) {
+		date := TravelDateFromMedia([]agent.MediaItem{
+			{Timestamp: "2026-05-04T09:00:00+09:00"},
+			{Timestamp: "2026-05-03T08:30:00+09:00"},
+			{},
+		})
+		assert.Equal(t, "2026-05-03", date)
+	})
+
+	t.Run("撮影日時がない場合は空を返す", func(t *testing.T) {
+		assert.Empty(t, TravelDateFromMedia([]agent.MediaItem{{FileID: "media-1"}}))
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+// 契約期間終了後、Stripeからの更新を待つ猶予期間
+const subscriptionExpiryGracePeriod = 72 * time.Hour
+
+// SubscriptionJobResult は定期処理の実行結果
+type SubscriptionJobResult struct {
+	Refilled   int // トークンを付与したサブスクリプション数
+	Expired    int // 期限切れにしたサブスクリプション数
+	Downgraded int // 無料プランに戻したユーザー数
+}
+
+// ISubscriptionScheduler はサブスクリプションの定期処理を行うインターフェース
+// 何度実行しても同じ期間に対して二重に付与・通知しない
+type ISubscriptionScheduler interface {
+	Run(ctx context.Context, now time.Time) (*SubscriptionJobResult, error)
+}
+
+type SubscriptionScheduler struct {
+	subscriptionRepo domain.ISubscriptionRepository
+	userRepo         domain.IUserRepository
+	notificationRepo domain.INotificationRepository
+	tokenLedger      ITokenLedger
+	txManager        domain.ITransactionManager
+}
+
+func NewSubscriptionScheduler(
+	subscriptionRepo domain.ISubscriptionRepository,
+	userRepo domain.IUserRepository,
+	notificationRepo domain.INotificationRepository,
+	tokenLedger ITokenLedger,
+	txManager domain.ITransactionManager,
+) *SubscriptionScheduler {
+	return &SubscriptionScheduler{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		tokenLedger:      tokenLedger,
+		txManager:        txManager,
+	}
+}
+
+// Run は期間更新されたサブスクリプションへのトークン付与、期限切れ処理、プランの戻しを行う
+// 個別の失敗はログに残して処理を継続する
+func (s *SubscriptionScheduler) Run(ctx context.Context, now time.Time) (*SubscriptionJobResult, error) {
+	result := &SubscriptionJobResult{}
+
+	// 1. 付与期間が切り替わったサブスクリプションにトークンを付与
+	due, err := s.subscriptionRepo.FindDueForRefill(ctx, now)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	for _, sub := range due {
+		if err := s.refill(ctx, sub, now); err != nil {
+			logger.Error(ctx, "failed to refill subscription tokens", "subscription_id", sub.ID, "error", err)
+			continue
+		}
+		result.Refilled++
+	}
+
+	// 2. 猶予期間を過ぎても更新されないサブスクリプションを期限切れにする
+	lapsed, err := s.subscriptionRepo.FindLapsed(ctx, now.Add(-subscriptionExpiryGracePeriod))
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	for _, sub := range lapsed {
+		sub.Status = domain.SubscriptionStatusExpired
+		if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+			logger.Error(ctx, "failed to expire subscription", "subscription_id", sub.ID, "error", err)
+			continue
+		}
+		result.Expired++
+	}
+
+	// 3. 有効なサブスクリプションがなくなったユーザーを無料プランに戻す
+	userIDs, err := s.subscriptionRepo.FindUserIDsToDowngrade(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	for _, userID := range userIDs {
+		if err := s.downgrade(ctx, userID); err != nil {
+			logger.Error(ctx, "failed to downgrade user plan", "user_id", userID, "error", err)
+			continue
+		}
+		result.Downgraded++
+	}
+
+	return result, nil
+}
+
+// refill は現在の付与期間（1か月）分のトークンを付与する
+func (s *SubscriptionScheduler) refill(ctx context.Context, sub *domain.Subscription, now time.Time) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		periodEnd := sub.RefillPeriodEnd(now)
+		sub.LastRefilledPeriodEnd = &periodEnd
+		// 楽観ロックにより同時実行された場合は片方のみ成功する
+		if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+			return err
+		}
+
+		referenceID := fmt.Sprintf("subscription:%s:%d", sub.ID, periodEnd.Unix())
+		if err := s.tokenLedger.Grant(ctx, sub.UserID, referenceID, domain.TokenTransactionTypeBonus, constant.PremiumPlanMonthlyTokens, "プレミアムプラン定期付与"); err != nil {
+			return err
+		}
+
+		return s.notificationRepo.Create(ctx, &domain.Notification{
+			UserID:  sub.UserID,
+			Type:    domain.NotificationTypeTokenRefilled,
+			Title:   "トークン付与",
+			Message: fmt.Sprintf("プレミアムプランの更新により%dトークンが付与されました", constant.PremiumPlanMonthlyTokens),
+		})
+	})
+}
+
+// downgrade はユーザーを無料プランに戻して通知する
+func (s *SubscriptionScheduler) downgrade(ctx context.Context, userID string) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		changed, err := changeUserPlan(ctx, s.userRepo, userID, constant.UserPlanFree)
+		if err != nil || !changed {
+			return err
+		}
+
+		return s.notificationRepo.Create(ctx, &domain.Notification{
+			UserID:  userID,
+			Type:    domain.NotificationTypePlanExpired,
+			Title:   "プラン終了",
+			Message: "プレミアムプランの契約が終了したため、無料プランに変更されました",
+		})
+	})
+}
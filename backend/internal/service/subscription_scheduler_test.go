@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeSubscriptionRepo struct {
+	domain.ISubscriptionRepository
+	subscriptions []*domain.Subscription
+}
+
+func (r *fakeSubscriptionRepo) Create(ctx context.Context, subscription *domain.Subscription) error {
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) Update(ctx context.Context, subscription *domain.Subscription) error {
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) FindByStripeSubscriptionID(ctx context.Context, stripeSubID string) (*domain.Subscription, error) {
+	for _, sub := range r.subscriptions {
+		if sub.StripeSubID == stripeSubID {
+			return sub, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSubscriptionRepo) FindDueForRefill(ctx context.Context, now time.Time) ([]*domain.Subscription, error) {
+	var due []*domain.Subscription
+	for _, sub := range r.subscriptions {
+		if sub.Status != domain.SubscriptionStatusActive || !sub.CurrentPeriodEnd.After(now) {
+			continue
+		}
+		if sub.LastRefilledPeriodEnd == nil || !sub.LastRefilledPeriodEnd.After(now) {
+			due = append(due, sub)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeSubscriptionRepo) FindLapsed(ctx context.Context, before time.Time) ([]*domain.Subscription, error) {
+	return nil, nil
+}
+
+func (r *fakeSubscriptionRepo) FindUserIDsToDowngrade(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type fakeNotificationRepo struct {
+	domain.INotificationRepository
+	notifications []*domain.Notification
+}
+
+func (r *fakeNotificationRepo) Create(ctx context.Context, notification *domain.Notification) error {
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+type fakeTokenLedger struct {
+	ITokenLedger
+	grants map[string]int // referenceID -> 付与トークン数
+}
+
+func (l *fakeTokenLedger) Grant(ctx context.Context, userID, referenceID, txType string, amount int, description string) error {
+	if _, ok := l.grants[referenceID]; !ok {
+		l.grants[referenceID] = amount
+	}
+	return nil
+}
+
+func (l *fakeTokenLedger) total() int {
+	total := 0
+	for _, amount := range l.grants {
+		total += amount
+	}
+	return total
+}
+
+func TestSubscriptionScheduler_Run(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// runDaily は購入直後の状態からdays日間、毎日定期処理を実行する
+	runDaily := func(t *testing.T, sub *domain.Subscription, days int) *fakeTokenLedger {
+		t.Helper()
+		refilled := sub.RefillPeriodEnd(start)
+		sub.LastRefilledPeriodEnd = &refilled
+		ledger := &fakeTokenLedger{grants: map[string]int{}}
+		scheduler := NewSubscriptionScheduler(&fakeSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}, nil, &fakeNotificationRepo{}, ledger, fakeTransactionManager{})
+		for day := 0; day < days; day++ {
+			_, err := scheduler.Run(ctx, start.AddDate(0, 0, day))
+			require.NoError(t, err)
+		}
+		return ledger
+	}
+
+	t.Run("年額プランは契約期間中に毎月トークンが付与される", func(t *testing.T) {
+		sub := &domain.Subscription{
+			BaseModel:        domain.BaseModel{ID: "sub-yearly"},
+			UserID:           "user-1",
+			Plan:             domain.SubscriptionPlanYearly,
+			Status:           domain.SubscriptionStatusActive,
+			CurrentPeriodEnd: start.AddDate(1, 0, 0),
+		}
+		ledger := runDaily(t, sub, 365)
+
+		// 初回の1か月分は購入時に付与済みのため、残りの11か月分が付与される
+		assert.Len(t, ledger.grants, 11)
+		assert.Equal(t, 11*constant.PremiumPlanMonthlyTokens, ledger.total())
+		assert.Equal(t, sub.CurrentPeriodEnd, *sub.LastRefilledPeriodEnd)
+	})
+
+	t.Run("月額プランは契約期間が更新されるまで追加で付与されない", func(t *testing.T) {
+		sub := &domain.Subscription{
+			BaseModel:        domain.BaseModel{ID: "sub-monthly"},
+			UserID:           "user-1",
+			Plan:             domain.SubscriptionPlanMonthly,
+			Status:           domain.SubscriptionStatusActive,
+			CurrentPeriodEnd: start.AddDate(0, 1, 0),
+		}
+		ledger := runDaily(t, sub, 31)
+		assert.Empty(t, ledger.grants)
+
+		// Stripeから契約期間の更新が届いた後は1か月分が付与される
+		sub.CurrentPeriodEnd = start.AddDate(0, 2, 0)
+		scheduler := NewSubscriptionScheduler(&fakeSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}, nil, &fakeNotificationRepo{}, ledger, fakeTransactionManager{})
+		for _, now := range []time.Time{start.AddDate(0, 1, 1), start.AddDate(0, 1, 2)} {
+			_, err := scheduler.Run(ctx, now)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, constant.PremiumPlanMonthlyTokens, ledger.total())
+	})
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/uuid"
+	"gorm.io/gorm"
+)
+
+// タスクの実行権の有効期間（実行中は期間の1/3ごとに延長する）
+const taskLeaseDuration = 5 * time.Minute
+
+// ITaskExecutionGuard はタスクID・バージョン単位でタスクの重複実行を防ぐインターフェース
+type ITaskExecutionGuard interface {
+	// Run は実行権を取得して処理関数を実行する
+	// 完了済み・デッドレター移動済みのタスク、より新しいバージョンが登録済みのタスクは実行せずnilを返す
+	// 最大実行回数に達しても失敗した場合はデッドレターに移動する
+	Run(ctx context.Context, task *queue.Task, fn queue.HandlerFunc) error
+}
+
+type TaskExecutionGuard struct {
+	executionRepo  domain.ITaskExecutionRepository
+	deadLetterRepo domain.IDeadLetterTaskRepository
+	txManager      domain.ITransactionManager
+	leaseDuration  time.Duration
+	now            func() time.Time
+}
+
+func NewTaskExecutionGuard(executionRepo domain.ITaskExecutionRepository, deadLetterRepo domain.IDeadLetterTaskRepository, txManager domain.ITransactionManager) *TaskExecutionGuard {
+	return &TaskExecutionGuard{
+		executionRepo:  executionRepo,
+		deadLetterRepo: deadLetterRepo,
+		txManager:      txManager,
+		leaseDuration:  taskLeaseDuration,
+		now:            time.Now,
+	}
+}
+
+// TaskExecutionMiddleware はタスクレジストリに登録するミドルウェアを返す
+func TaskExecutionMiddleware(guard ITaskExecutionGuard) queue.Middleware {
+	return func(next queue.HandlerFunc) queue.HandlerFunc {
+		return func(ctx context.Context, task *queue.Task) error {
+			return guard.Run(ctx, task, next)
+		}
+	}
+}
+
+// Run は実行権を取得して処理関数を実行する
+func (g *TaskExecutionGuard) Run(ctx context.Context, task *queue.Task, fn queue.HandlerFunc) error {
+	// IDのないタスクは重複を判定できないためそのまま実行する
+	if task.ID == "" {
+		return fn(ctx, task)
+	}
+
+	execution, err := g.acquire(ctx, task, uuid.GenerateID())
+	if err != nil {
+		return err
+	}
+	if execution == nil {
+		return nil
+	}
+
+	ctx = queue.WithAttempt(ctx, execution.Attempts)
+	startedAt := g.now()
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopRenewal := g.renewLease(runCtx, execution, cancel)
+
+	runErr := fn(runCtx, task)
+	stopRenewal()
+
+	// 実行権が他のワーカーに移った場合は結果を記録しない
+	if cause := context.Cause(runCtx); errors.Is(cause, errors.ErrTaskLeaseLost) {
+		return errors.Wrap(ctx, cause)
+	}
+
+	if err := g.finish(ctx, task, execution, startedAt, runErr); err != nil {
+		logger.Error(ctx, "failed to record task execution result", "task_id", task.ID, "task_type", task.Type, "error", err)
+	}
+	return runErr
+}
+
+// acquire はタスクの実行記録を作成、または失敗・リース切れの記録を引き継ぐ
+// 実行不要なタスクの場合はnilを返す
+func (g *TaskExecutionGuard) acquire(ctx context.Context, task *queue.Task, owner string) (*domain.TaskExecution, error) {
+	var acquired *domain.TaskExecution
+	err := g.txManager.Do(ctx, func(ctx context.Context) error {
+		latestVersion, err := g.executionRepo.FindLatestVersion(ctx, task.Type, task.ID)
+		if err != nil {
+			return err
+		}
+		if latestVersion > task.Version {
+			logger.Info(ctx, "skip outdated task", "task_id", task.ID, "task_type", task.Type, "version", task.Version, "latest_version", latestVersion)
+			return nil
+		}
+
+		execution, err := g.executionRepo.FindByTask(ctx, task.Type, task.ID, task.Version)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.Wrap(ctx, err)
+		}
+
+		now := g.now()
+		if execution == nil {
+			execution = &domain.TaskExecution{
+				TaskType:       task.Type,
+				TaskID:         task.ID,
+				TaskVersion:    task.Version,
+				Status:         domain.TaskExecutionStatusRunning,
+				Owner:          owner,
+				LeaseExpiresAt: now.Add(g.leaseDuration),
+				Attempts:       1,
+			}
+			// 同時に配信された場合は一意制約により片方のみ作成に成功する
+			if err := g.executionRepo.Create(ctx, execution); err != nil {
+				return err
+			}
+			acquired = execution
+			return nil
+		}
+
+		switch {
+		case execution.Status == domain.TaskExecutionStatusCompleted || execution.Status == domain.TaskExecutionStatusDead:
+			logger.Info(ctx, "skip finished task", "task_id", task.ID, "task_type", task.Type, "version", task.Version, "status", execution.Status)
+			return nil
+		case execution.Status == domain.TaskExecutionStatusRunning && now.Before(execution.LeaseExpiresAt):
+			return errors.Wrap(ctx, fmt.Errorf("%w: task_id=%s owner=%s", errors.ErrTaskInProgress, task.ID, execution.Owner))
+		}
+
+		// 失敗・リース切れの記録を引き継ぐ（楽観ロックにより同時に引き継げるのは1ワーカーのみ）
+		execution.Status = domain.TaskExecutionStatusRunning
+		execution.Owner = owner
+		execution.LeaseExpiresAt = now.Add(g.leaseDuration)
+		execution.Attempts++
+		if err := g.executionRepo.Update(ctx, execution); err != nil {
+			if errors.Is(err, errors.ErrOptimisticLock) {
+				return errors.Wrap(ctx, fmt.Errorf("%w: task_id=%s", errors.ErrTaskInProgress, task.ID))
+			}
+			return err
+		}
+		acquired = execution
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acquired, nil
+}
+
+// renewLease は処理中に実行権の期限を定期的に延長する
+// 他のワーカーに引き継がれていた場合はErrTaskLeaseLostで処理をキャンセルする
+// 戻り値の関数は延長を停止し、実行中の更新の完了を待つ
+func (g *TaskExecutionGuard) renewLease(ctx context.Context, execution *domain.TaskExecution, cancel context.CancelCauseFunc) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(g.leaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			version := execution.Version
+			execution.LeaseExpiresAt = g.now().Add(g.leaseDuration)
+			if err := g.executionRepo.Update(ctx, execution); err != nil {
+				if errors.Is(err, errors.ErrOptimisticLock) {
+					cancel(errors.ErrTaskLeaseLost)
+					return
+				}
+				// 一時的な失敗の場合は次回の延長で再試行する
+				execution.Version = version
+				logger.Warn(ctx, "failed to renew task lease", "task_id", execution.TaskID, "error", err)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// finish は実行結果を記録し、最後の試行で失敗した場合はデッドレターに移動する
+func (g *TaskExecutionGuard) finish(ctx context.Context, task *queue.Task, execution *domain.TaskExecution, startedAt time.Time, runErr error) error {
+	now := g.now()
+	// 失敗した場合は次の配信ですぐに引き継げるようリースを失効させる
+	execution.LeaseExpiresAt = now
+	attempt := domain.TaskAttempt{
+		Attempt:    execution.Attempts,
+		Owner:      execution.Owner,
+		StartedAt:  startedAt,
+		FinishedAt: now,
+	}
+	if runErr != nil {
+		attempt.Error = runErr.Error()
+	}
+	execution.AttemptHistory = append(execution.AttemptHistory, attempt)
+
+	if runErr == nil {
+		execution.Status = domain.TaskExecutionStatusCompleted
+		execution.CompletedAt = &now
+		return g.executionRepo.Update(ctx, execution)
+	}
+
+	execution.LastError = runErr.Error()
+	if !queue.IsFinalAttempt(ctx) {
+		execution.Status = domain.TaskExecutionStatusFailed
+		return g.executionRepo.Update(ctx, execution)
+	}
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	execution.Status = domain.TaskExecutionStatusDead
+	return g.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := g.executionRepo.Update(ctx, execution); err != nil {
+			return err
+		}
+		logger.Error(ctx, "task moved to dead letter", "task_id", task.ID, "task_type", task.Type, "attempts", execution.Attempts, "error", runErr)
+		return g.deadLetterRepo.Create(ctx, &domain.DeadLetterTask{
+			TaskType:       task.Type,
+			TaskID:         task.ID,
+			TaskVersion:    task.Version,
+			Payload:        string(payload),
+			Errors:         errorChain(runErr),
+			Attempts:       execution.Attempts,
+			AttemptHistory: execution.AttemptHistory,
+			Status:         domain.DeadLetterStatusPending,
+		})
+	})
+}
+
+// errorChain はラップされたエラーのメッセージを外側から順に返す（同じメッセージは省略する）
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = stdErrors.Unwrap(err) {
+		msg := err.Error()
+		if len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+	}
+	return chain
+}
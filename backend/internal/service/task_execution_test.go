@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/queue"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeTaskExecutionRepo は一意制約と楽観ロックを再現するインメモリのリポジトリ
+type fakeTaskExecutionRepo struct {
+	mu      sync.Mutex
+	records map[string]domain.TaskExecution
+}
+
+func newFakeTaskExecutionRepo() *fakeTaskExecutionRepo {
+	return &fakeTaskExecutionRepo{records: map[string]domain.TaskExecution{}}
+}
+
+func taskExecutionKey(taskType, taskID string, version int) string {
+	return fmt.Sprintf("%s/%s/%d", taskType, taskID, version)
+}
+
+func (r *fakeTaskExecutionRepo) Create(ctx context.Context, execution *domain.TaskExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := taskExecutionKey(execution.TaskType, execution.TaskID, execution.TaskVersion)
+	if _, ok := r.records[key]; ok {
+		return errors.ErrUniqueConstraint
+	}
+	execution.Version = 1
+	r.records[key] = *execution
+	return nil
+}
+
+func (r *fakeTaskExecutionRepo) Update(ctx context.Context, execution *domain.TaskExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := taskExecutionKey(execution.TaskType, execution.TaskID, execution.TaskVersion)
+	current, ok := r.records[key]
+	execution.Version++
+	if !ok || current.Version != execution.Version-1 {
+		return errors.ErrOptimisticLock
+	}
+	r.records[key] = *execution
+	return nil
+}
+
+func (r *fakeTaskExecutionRepo) FindByTask(ctx context.Context, taskType, taskID string, taskVersion int) (*domain.TaskExecution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[taskExecutionKey(taskType, taskID, taskVersion)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (r *fakeTaskExecutionRepo) FindLatestVersion(ctx context.Context, taskType, taskID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	latest := 0
+	for _, record := range r.records {
+		if record.TaskType == taskType && record.TaskID == taskID && record.TaskVersion > latest {
+			latest = record.TaskVersion
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakeTaskExecutionRepo) get(task *queue.Task) domain.TaskExecution {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records[taskExecutionKey(task.Type, task.ID, task.Version)]
+}
+
+// fakeDeadLetterRepo はデッドレターを保持するインメモリのリポジトリ
+type fakeDeadLetterRepo struct {
+	mu      sync.Mutex
+	records []*domain.DeadLetterTask
+}
+
+func (r *fakeDeadLetterRepo) Create(ctx context.Context, task *domain.DeadLetterTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.ID = fmt.Sprintf("dead-letter-%d", len(r.records)+1)
+	task.Version = 1
+	r.records = append(r.records, task)
+	return nil
+}
+
+func (r *fakeDeadLetterRepo) Update(ctx context.Context, task *domain.DeadLetterTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, record := range r.records {
+		if record.ID == task.ID {
+			task.Version++
+			r.records[i] = task
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *fakeDeadLetterRepo) FindByID(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range r.records {
+		if record.ID == id {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeDeadLetterRepo) FindPendingByTask(ctx context.Context, taskType, taskID string) (*domain.DeadLetterTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range r.records {
+		if record.TaskType == taskType && record.TaskID == taskID && record.Status == domain.DeadLetterStatusPending {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeDeadLetterRepo) List(ctx context.Context, status string, opts *domain.ListOpts) ([]*domain.DeadLetterTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []*domain.DeadLetterTask
+	for _, record := range r.records {
+		if status == "" || record.Status == status {
+			tasks = append(tasks, record)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeDeadLetterRepo) Count(ctx context.Context, status string) (int64, error) {
+	tasks, err := r.List(ctx, status, nil)
+	return int64(len(tasks)), err
+}
+
+type fakeTransactionManager struct{}
+
+func (fakeTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestTaskExecutionGuard_Run(t *testing.T) {
+	ctx := context.Background()
+	newGuard := func(repo *fakeTaskExecutionRepo, now *time.Time) *TaskExecutionGuard {
+		g := NewTaskExecutionGuard(repo, &fakeDeadLetterRepo{}, fakeTransactionManager{})
+		g.now = func() time.Time { return *now }
+		return g
+	}
+	newTask := func(version int) *queue.Task {
+		return &queue.Task{ID: "vlog-1", Version: version, Type: queue.TaskTypeProcessVLog, Data: json.RawMessage(`{}`)}
+	}
+	// handleWithRetry は最大再試行回数を設定したタスク定義経由でガードを通して処理関数を実行する
+	handleWithRetry := func(guard *TaskExecutionGuard, maxRetries int, fn queue.HandlerFunc) queue.HandlerFunc {
+		registry := queue.NewRegistry()
+		queue.Register(registry, queue.TaskTypeProcessVLog, "test", retry.Config{MaxRetries: maxRetries}, func(ctx context.Context, task *queue.Task, _ *json.RawMessage) error {
+			return fn(ctx, task)
+		})
+		registry.Use(TaskExecutionMiddleware(guard))
+		def, err := registry.Lookup(queue.TaskTypeProcessVLog)
+		require.NoError(t, err)
+		return def.Handle
+	}
+
+	t.Run("完了済みのタスクが再配信されても再実行しない", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+
+		calls := 0
+		fn := func(ctx context.Context, task *queue.Task) error { calls++; return nil }
+		require.NoError(t, guard.Run(ctx, newTask(1), fn))
+		require.NoError(t, guard.Run(ctx, newTask(1), fn))
+
+		assert.Equal(t, 1, calls)
+		record := repo.get(newTask(1))
+		assert.Equal(t, domain.TaskExecutionStatusCompleted, record.Status)
+		assert.Equal(t, 1, record.Attempts)
+	})
+
+	t.Run("失敗したタスクは再配信時に再実行され、実行回数が増える", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+
+		fail := handleWithRetry(guard, 3, func(ctx context.Context, task *queue.Task) error { return fmt.Errorf("veo error") })
+		assert.Error(t, fail(ctx, newTask(1)))
+		assert.Equal(t, "veo error", repo.get(newTask(1)).LastError)
+		assert.Equal(t, domain.TaskExecutionStatusFailed, repo.get(newTask(1)).Status)
+
+		require.NoError(t, guard.Run(ctx, newTask(1), func(ctx context.Context, task *queue.Task) error { return nil }))
+		record := repo.get(newTask(1))
+		assert.Equal(t, domain.TaskExecutionStatusCompleted, record.Status)
+		assert.Equal(t, 2, record.Attempts)
+		assert.Len(t, record.AttemptHistory, 2)
+	})
+
+	t.Run("最後の試行で失敗したタスクはデッドレターに移動し、再配信されても実行しない", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		deadLetterRepo := &fakeDeadLetterRepo{}
+		now := time.Unix(1790000000, 0)
+		guard := NewTaskExecutionGuard(repo, deadLetterRepo, fakeTransactionManager{})
+		guard.now = func() time.Time { return now }
+
+		calls := 0
+		fail := handleWithRetry(guard, 1, func(ctx context.Context, task *queue.Task) error {
+			calls++
+			return fmt.Errorf("generate video: %w", fmt.Errorf("veo error"))
+		})
+		assert.Error(t, fail(ctx, newTask(1)))
+		assert.Empty(t, deadLetterRepo.records)
+		assert.Error(t, fail(ctx, newTask(1)))
+		require.NoError(t, fail(ctx, newTask(1)))
+		assert.Equal(t, 2, calls)
+
+		record := repo.get(newTask(1))
+		assert.Equal(t, domain.TaskExecutionStatusDead, record.Status)
+		require.Len(t, deadLetterRepo.records, 1)
+		deadLetter := deadLetterRepo.records[0]
+		assert.Equal(t, domain.DeadLetterStatusPending, deadLetter.Status)
+		assert.Equal(t, "vlog-1", deadLetter.TaskID)
+		assert.Equal(t, 2, deadLetter.Attempts)
+		assert.Equal(t, []string{"generate video: veo error", "veo error"}, deadLetter.Errors)
+		assert.Len(t, deadLetter.AttemptHistory, 2)
+		assert.JSONEq(t, `{"id":"vlog-1","version":1,"type":"ProcessVLogTask","data":{},"status":""}`, deadLetter.Payload)
+	})
+
+	t.Run("他のワーカーが実行中のタスクはリース期限まで実行しない", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+		_, err := guard.acquire(ctx, newTask(1), "worker-a")
+		require.NoError(t, err)
+
+		calls := 0
+		fn := func(ctx context.Context, task *queue.Task) error { calls++; return nil }
+		assert.ErrorIs(t, guard.Run(ctx, newTask(1), fn), errors.ErrTaskInProgress)
+		assert.Equal(t, 0, calls)
+
+		// リース期限切れ後は引き継いで実行する
+		now = now.Add(taskLeaseDuration + time.Second)
+		require.NoError(t, guard.Run(ctx, newTask(1), fn))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, repo.get(newTask(1)).Attempts)
+	})
+
+	t.Run("新しいバージョンが登録済みの場合、古いバージョンのタスクは実行しない", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+		require.NoError(t, guard.Run(ctx, newTask(3), func(ctx context.Context, task *queue.Task) error { return nil }))
+
+		calls := 0
+		require.NoError(t, guard.Run(ctx, newTask(2), func(ctx context.Context, task *queue.Task) error { calls++; return nil }))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("実行中に引き継がれた場合は処理をキャンセルし、結果を記録しない", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+		guard.leaseDuration = 30 * time.Millisecond
+
+		err := guard.Run(ctx, newTask(1), func(ctx context.Context, task *queue.Task) error {
+			// 他のワーカーによる引き継ぎを再現する
+			record := repo.get(task)
+			record.Owner = "worker-b"
+			require.NoError(t, repo.Update(ctx, &record))
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, errors.ErrTaskLeaseLost)
+		record := repo.get(newTask(1))
+		assert.Equal(t, "worker-b", record.Owner)
+		assert.Equal(t, domain.TaskExecutionStatusRunning, record.Status)
+	})
+
+	t.Run("IDのないタスクはそのまま実行する", func(t *testing.T) {
+		repo := newFakeTaskExecutionRepo()
+		now := time.Unix(1790000000, 0)
+		guard := newGuard(repo, &now)
+
+		calls := 0
+		fn := func(ctx context.Context, task *queue.Task) error { calls++; return nil }
+		require.NoError(t, guard.Run(ctx, &queue.Task{Type: queue.TaskTypeProcessMediaAnalysis}, fn))
+		require.NoError(t, guard.Run(ctx, &queue.Task{Type: queue.TaskTypeProcessMediaAnalysis}, fn))
+		assert.Equal(t, 2, calls)
+	})
+}
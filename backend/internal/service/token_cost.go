@@ -0,0 +1,67 @@
+package service
+
+import (
+	"math"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/constant"
+)
+
+// TokenCostEstimate はVLog生成に必要なトークン数の見積もり
+type TokenCostEstimate struct {
+	ImageAnalysis    int // 画像分析
+	VideoAnalysis    int // 動画分析
+	ScriptGeneration int // スクリプト生成
+	VideoGeneration  int // 動画生成
+	Total            int // 合計
+}
+
+// EstimateVlogTokenCost はVlogInputからVLog生成に必要なトークン数を見積もる
+// 分析済みのメディアは分析コストを計上しない
+// 長さ不明の動画は10秒として扱う
+// 絵コンテの確認を指定した場合（下書き）は分析・絵コンテ作成のみ、確認済みの絵コンテを指定した場合は絵コンテの長さでの動画生成のみを計上する
+func EstimateVlogTokenCost(input *agent.VlogInput) *TokenCostEstimate {
+	estimate := &TokenCostEstimate{}
+	if input == nil {
+		return estimate
+	}
+
+	// 分析・絵コンテ作成は下書きの作成時に消費済み
+	if input.Storyboard != nil {
+		estimate.VideoGeneration = ceilUnits(float64(input.Storyboard.TotalDuration()), 60) * constant.TokenCostVideoGenerationPerMinute
+		estimate.Total = estimate.VideoGeneration
+		return estimate
+	}
+
+	for _, item := range input.MediaItems {
+		if item.IsAnalyzed {
+			continue
+		}
+		switch item.Type {
+		case "image":
+			estimate.ImageAnalysis += constant.TokenCostImageAnalysis
+		case "video":
+			estimate.VideoAnalysis += ceilUnits(item.Duration, 10) * constant.TokenCostVideoAnalysisPer10Sec
+		}
+	}
+	estimate.ScriptGeneration = constant.TokenCostScriptGeneration
+
+	if !input.ReviewStoryboard {
+		duration := input.Style.Duration
+		if duration <= 0 {
+			duration = constant.DefaultVLogDurationSeconds
+		}
+		estimate.VideoGeneration = ceilUnits(float64(duration), 60) * constant.TokenCostVideoGenerationPerMinute
+	}
+
+	estimate.Total = estimate.ImageAnalysis + estimate.VideoAnalysis + estimate.ScriptGeneration + estimate.VideoGeneration
+	return estimate
+}
+
+// ceilUnits は秒数をunit秒単位に切り上げた数を返す（最低1単位）
+func ceilUnits(seconds float64, unit float64) int {
+	if seconds <= 0 {
+		return 1
+	}
+	return int(math.Ceil(seconds / unit))
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateVlogTokenCost(t *testing.T) {
+	items := []agent.MediaItem{
+		{Type: "image"},
+		{Type: "image", IsAnalyzed: true},
+		{Type: "video", Duration: 25},
+	}
+
+	t.Run("分析・スクリプト生成・動画生成を計上する", func(t *testing.T) {
+		estimate := EstimateVlogTokenCost(&agent.VlogInput{MediaItems: items, Style: agent.VlogStyle{Duration: 90}})
+		assert.Equal(t, &TokenCostEstimate{
+			ImageAnalysis:    10,
+			VideoAnalysis:    60,
+			ScriptGeneration: 20,
+			VideoGeneration:  100,
+			Total:            190,
+		}, estimate)
+	})
+
+	t.Run("下書きの場合は分析・スクリプト生成のみを計上する", func(t *testing.T) {
+		estimate := EstimateVlogTokenCost(&agent.VlogInput{MediaItems: items, Style: agent.VlogStyle{Duration: 90}, ReviewStoryboard: true})
+		assert.Equal(t, 0, estimate.VideoGeneration)
+		assert.Equal(t, 90, estimate.Total)
+	})
+
+	t.Run("確認済みの絵コンテがある場合は絵コンテの長さで動画生成のみを計上する", func(t *testing.T) {
+		estimate := EstimateVlogTokenCost(&agent.VlogInput{
+			MediaItems:       items,
+			Style:            agent.VlogStyle{Duration: 30},
+			ReviewStoryboard: true,
+			Storyboard: &agent.Storyboard{Scenes: []agent.StoryboardScene{
+				{DurationSeconds: 8}, {DurationSeconds: 8}, {DurationSeconds: 8}, {DurationSeconds: 8},
+				{DurationSeconds: 8}, {DurationSeconds: 8}, {DurationSeconds: 8}, {DurationSeconds: 8},
+			}},
+		})
+		assert.Equal(t, &TokenCostEstimate{VideoGeneration: 100, Total: 100}, estimate)
+	})
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ITokenLedger はトークンの仮引き・確定・取り消しを管理するインターフェース
+// いずれの操作もreferenceID（VLog IDなど）単位でべき等に動作する
+type ITokenLedger interface {
+	// Reserve はトークンを仮引きする（同一referenceIDで既に仮引き済みの場合は何もしない）
+	Reserve(ctx context.Context, userID, referenceID string, amount int, description string) error
+	// Confirm は仮引きを消費として確定する
+	Confirm(ctx context.Context, referenceID string) error
+	// Rollback は仮引きを取り消してトークンを返却する
+	Rollback(ctx context.Context, referenceID string) error
+	// Grant はトークンを付与する（同一referenceID・種別で付与済みの場合は何もしない）
+	Grant(ctx context.Context, userID, referenceID, txType string, amount int, description string) error
+}
+
+type TokenLedger struct {
+	userRepo    domain.IUserRepository
+	tokenTxRepo domain.ITokenTransactionRepository
+	txManager   domain.ITransactionManager
+}
+
+func NewTokenLedger(userRepo domain.IUserRepository, tokenTxRepo domain.ITokenTransactionRepository, txManager domain.ITransactionManager) *TokenLedger {
+	return &TokenLedger{
+		userRepo:    userRepo,
+		tokenTxRepo: tokenTxRepo,
+		txManager:   txManager,
+	}
+}
+
+// Reserve はトークンを仮引きする
+func (l *TokenLedger) Reserve(ctx context.Context, userID, referenceID string, amount int, description string) error {
+	return ignoreDuplicate(l.txManager.Do(ctx, func(ctx context.Context) error {
+		state, err := l.findState(ctx, referenceID)
+		if err != nil {
+			return err
+		}
+		// 既に仮引き済み（確定・取り消し済みを含む）の場合は二重に仮引きしない
+		if state.reservation != nil {
+			return nil
+		}
+
+		balance, err := l.userRepo.AddTokenBalance(ctx, userID, -amount)
+		if err != nil {
+			if errors.Is(err, errors.ErrInsufficientTokens) {
+				return errors.MakeBusinessError(ctx, errors.ErrInsufficientTokens.Error())
+			}
+			return errors.Wrap(ctx, err)
+		}
+
+		return l.tokenTxRepo.Create(ctx, &domain.TokenTransaction{
+			UserID:      userID,
+			Type:        domain.TokenTransactionTypeReserve,
+			Amount:      -amount,
+			Balance:     balance,
+			Description: description,
+			ReferenceID: referenceID,
+		})
+	}))
+}
+
+// Confirm は仮引きを消費として確定する
+func (l *TokenLedger) Confirm(ctx context.Context, referenceID string) error {
+	return l.txManager.Do(ctx, func(ctx context.Context) error {
+		state, err := l.findState(ctx, referenceID)
+		if err != nil {
+			return err
+		}
+		if state.reservation == nil {
+			return errors.Wrap(ctx, fmt.Errorf("%w: reference_id=%s", errors.ErrTokenReservationNotFound, referenceID))
+		}
+		if state.rollback != nil {
+			return errors.Wrap(ctx, fmt.Errorf("%w: reference_id=%s", errors.ErrTokenReservationCancelled, referenceID))
+		}
+		// 確定済みの場合は何もしない
+		if state.reservation.Type == domain.TokenTransactionTypeConsumption {
+			return nil
+		}
+
+		state.reservation.Type = domain.TokenTransactionTypeConsumption
+		return l.tokenTxRepo.Update(ctx, state.reservation)
+	})
+}
+
+// Rollback は仮引きを取り消してトークンを返却する
+func (l *TokenLedger) Rollback(ctx context.Context, referenceID string) error {
+	return ignoreDuplicate(l.txManager.Do(ctx, func(ctx context.Context) error {
+		state, err := l.findState(ctx, referenceID)
+		if err != nil {
+			return err
+		}
+		// 仮引きがない・取り消し済み・確定済みの場合は返却しない
+		if state.reservation == nil || state.rollback != nil || state.reservation.Type == domain.TokenTransactionTypeConsumption {
+			return nil
+		}
+
+		amount := -state.reservation.Amount
+		balance, err := l.userRepo.AddTokenBalance(ctx, state.reservation.UserID, amount)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+
+		return l.tokenTxRepo.Create(ctx, &domain.TokenTransaction{
+			UserID:      state.reservation.UserID,
+			Type:        domain.TokenTransactionTypeRollback,
+			Amount:      amount,
+			Balance:     balance,
+			Description: state.reservation.Description + "（取り消し）",
+			ReferenceID: referenceID,
+		})
+	}))
+}
+
+// Grant はトークンを付与する
+func (l *TokenLedger) Grant(ctx context.Context, userID, referenceID, txType string, amount int, description string) error {
+	return ignoreDuplicate(l.txManager.Do(ctx, func(ctx context.Context) error {
+		transactions, err := l.tokenTxRepo.FindByReferenceID(ctx, referenceID)
+		if err != nil {
+			return err
+		}
+		for _, tx := range transactions {
+			if tx.Type == txType {
+				return nil
+			}
+		}
+
+		balance, err := l.userRepo.AddTokenBalance(ctx, userID, amount)
+		if err != nil {
+			return errors.Wrap(ctx, err)
+		}
+
+		return l.tokenTxRepo.Create(ctx, &domain.TokenTransaction{
+			UserID:      userID,
+			Type:        txType,
+			Amount:      amount,
+			Balance:     balance,
+			Description: description,
+			ReferenceID: referenceID,
+		})
+	}))
+}
+
+// ignoreDuplicate は同じreferenceID・種別の取引が並行して記録されていた場合（UNIQUE制約違反）に処理済みとして扱う
+// 残高の加減算はトランザクションのロールバックで取り消される
+func ignoreDuplicate(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil
+	}
+	return err
+}
+
+// ledgerState はreferenceIDに紐づく仮引きと取り消しの記録
+type ledgerState struct {
+	reservation *domain.TokenTransaction // reserve または consumption
+	rollback    *domain.TokenTransaction
+}
+
+func (l *TokenLedger) findState(ctx context.Context, referenceID string) (*ledgerState, error) {
+	transactions, err := l.tokenTxRepo.FindByReferenceID(ctx, referenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ledgerState{}
+	for _, tx := range transactions {
+		switch tx.Type {
+		case domain.TokenTransactionTypeReserve, domain.TokenTransactionTypeConsumption:
+			state.reservation = tx
+		case domain.TokenTransactionTypeRollback:
+			state.rollback = tx
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	domain.IUserRepository
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, model *domain.User) (*domain.User, error) {
+	user, ok := r.users[model.ID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, model *domain.User) error {
+	r.users[model.ID] = model
+	return nil
+}
+
+func (r *fakeUserRepo) AddTokenBalance(ctx context.Context, id string, delta int) (int, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return 0, gorm.ErrRecordNotFound
+	}
+	if user.TokenBalance.Int64+int64(delta) < 0 {
+		return 0, errors.ErrInsufficientTokens
+	}
+	user.TokenBalance.Int64 += int64(delta)
+	user.TokenBalance.Valid = true
+	return int(user.TokenBalance.Int64), nil
+}
+
+func (r *fakeUserRepo) balance(id string) int {
+	return int(r.users[id].TokenBalance.Int64)
+}
+
+// fakeTokenTransactionRepo はUNIQUE(reference_id, type)を再現したトークン取引リポジトリ
+type fakeTokenTransactionRepo struct {
+	domain.ITokenTransactionRepository
+	transactions []*domain.TokenTransaction
+}
+
+func (r *fakeTokenTransactionRepo) Create(ctx context.Context, transaction *domain.TokenTransaction) error {
+	for _, tx := range r.transactions {
+		if tx.ReferenceID == transaction.ReferenceID && tx.Type == transaction.Type {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	copied := *transaction
+	r.transactions = append(r.transactions, &copied)
+	return nil
+}
+
+func (r *fakeTokenTransactionRepo) Update(ctx context.Context, transaction *domain.TokenTransaction) error {
+	for i, tx := range r.transactions {
+		if tx.ReferenceID == transaction.ReferenceID && tx.Type == domain.TokenTransactionTypeReserve {
+			copied := *transaction
+			r.transactions[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenTransactionRepo) FindByReferenceID(ctx context.Context, referenceID string) ([]*domain.TokenTransaction, error) {
+	var found []*domain.TokenTransaction
+	for _, tx := range r.transactions {
+		if tx.ReferenceID == referenceID {
+			copied := *tx
+			found = append(found, &copied)
+		}
+	}
+	return found, nil
+}
+
+// types はreferenceIDに紐づく取引の種別を記録順に返す
+func (r *fakeTokenTransactionRepo) types(referenceID string) []string {
+	var types []string
+	for _, tx := range r.transactions {
+		if tx.ReferenceID == referenceID {
+			types = append(types, tx.Type)
+		}
+	}
+	return types
+}
+
+func newTestUser(id string, balance int64) *domain.User {
+	user := &domain.User{BaseModel: domain.BaseModel{ID: id}}
+	user.TokenBalance.Int64 = balance
+	user.TokenBalance.Valid = true
+	return user
+}
+
+func TestTokenLedger(t *testing.T) {
+	ctx := context.Background()
+	setup := func() (*TokenLedger, *fakeUserRepo, *fakeTokenTransactionRepo) {
+		userRepo := &fakeUserRepo{users: map[string]*domain.User{"user-1": newTestUser("user-1", 100)}}
+		txRepo := &fakeTokenTransactionRepo{}
+		return NewTokenLedger(userRepo, txRepo, fakeTransactionManager{}), userRepo, txRepo
+	}
+
+	t.Run("同じreferenceIDでの仮引きは一度だけ残高から引く", func(t *testing.T) {
+		ledger, userRepo, txRepo := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+
+		assert.Equal(t, 70, userRepo.balance("user-1"))
+		assert.Equal(t, []string{domain.TokenTransactionTypeReserve}, txRepo.types("vlog-1"))
+	})
+
+	t.Run("確定は何度呼んでも消費として一度だけ記録する", func(t *testing.T) {
+		ledger, userRepo, txRepo := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Confirm(ctx, "vlog-1"))
+		require.NoError(t, ledger.Confirm(ctx, "vlog-1"))
+
+		assert.Equal(t, 70, userRepo.balance("user-1"))
+		assert.Equal(t, []string{domain.TokenTransactionTypeConsumption}, txRepo.types("vlog-1"))
+	})
+
+	t.Run("取り消しは何度呼んでも一度だけ返却する", func(t *testing.T) {
+		ledger, userRepo, txRepo := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+
+		assert.Equal(t, 100, userRepo.balance("user-1"))
+		assert.Equal(t, []string{domain.TokenTransactionTypeReserve, domain.TokenTransactionTypeRollback}, txRepo.types("vlog-1"))
+	})
+
+	t.Run("取り消し済みの仮引きは再度仮引きしない", func(t *testing.T) {
+		ledger, userRepo, _ := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+
+		assert.Equal(t, 100, userRepo.balance("user-1"))
+	})
+
+	t.Run("確定後の取り消しは何もしない", func(t *testing.T) {
+		ledger, userRepo, txRepo := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Confirm(ctx, "vlog-1"))
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+
+		assert.Equal(t, 70, userRepo.balance("user-1"))
+		assert.Equal(t, []string{domain.TokenTransactionTypeConsumption}, txRepo.types("vlog-1"))
+	})
+
+	t.Run("取り消し後の確定はエラー", func(t *testing.T) {
+		ledger, _, _ := setup()
+		require.NoError(t, ledger.Reserve(ctx, "user-1", "vlog-1", 30, "動画生成"))
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+
+		err := ledger.Confirm(ctx, "vlog-1")
+		assert.ErrorIs(t, err, errors.ErrTokenReservationCancelled)
+	})
+
+	t.Run("仮引きしていないreferenceIDの確定はエラー、取り消しは何もしない", func(t *testing.T) {
+		ledger, userRepo, _ := setup()
+		assert.ErrorIs(t, ledger.Confirm(ctx, "vlog-1"), errors.ErrTokenReservationNotFound)
+		require.NoError(t, ledger.Rollback(ctx, "vlog-1"))
+		assert.Equal(t, 100, userRepo.balance("user-1"))
+	})
+
+	t.Run("残高不足の場合はビジネスエラーを返し、仮引きを記録しない", func(t *testing.T) {
+		ledger, userRepo, txRepo := setup()
+		err := ledger.Reserve(ctx, "user-1", "vlog-1", 101, "動画生成")
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrCodeBussiness, errors.GetCode(err))
+		assert.Equal(t, 100, userRepo.balance("user-1"))
+		assert.Empty(t, txRepo.types("vlog-1"))
+	})
+
+	t.Run("同じreferenceID・種別での付与は一度だけ", func(t *testing.T) {
+		ledger, userRepo, _ := setup()
+		require.NoError(t, ledger.Grant(ctx, "user-1", "cs_1", domain.TokenTransactionTypePurchase, 500, "トークン購入"))
+		require.NoError(t, ledger.Grant(ctx, "user-1", "cs_1", domain.TokenTransactionTypePurchase, 500, "トークン購入"))
+
+		assert.Equal(t, 600, userRepo.balance("user-1"))
+	})
+}
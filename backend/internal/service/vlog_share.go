@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/sharecode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// IVlogShareService はVLogの共有リンクを管理するインターフェース
+type IVlogShareService interface {
+	// Issue は共有リンクを発行し、VLogの共有URLを更新する（発行済みのリンクは無効にする）
+	Issue(ctx context.Context, vlog *domain.Vlog, opts ShareOptions) (*domain.VlogShare, error)
+	// Current はVLogの有効な共有リンクを取得する
+	Current(ctx context.Context, vlogID string) (*domain.VlogShare, error)
+	// Revoke はVLogの共有リンクを無効にし、VLogの共有URLを削除する
+	Revoke(ctx context.Context, vlog *domain.Vlog) error
+	// Resolve は共有コードからVLogを取得し、閲覧数を加算する
+	Resolve(ctx context.Context, code, password string) (*domain.VlogShare, *domain.Vlog, error)
+	// ShareURL は共有コードの公開URLを返す
+	ShareURL(code string) string
+}
+
+// ShareOptions は共有リンクの発行の設定
+type ShareOptions struct {
+	Code      string     // 空の場合は生成する
+	Password  string     // 空の場合はパスワードなし
+	ExpiresAt *time.Time // nilの場合は無期限
+}
+
+type VlogShareService struct {
+	shareRepo domain.IVlogShareRepository
+	vlogRepo  domain.IVLogRepository
+	txManager domain.ITransactionManager
+	baseURL   string
+	now       func() time.Time
+}
+
+func NewVlogShareService(shareRepo domain.IVlogShareRepository, vlogRepo domain.IVLogRepository, txManager domain.ITransactionManager, baseURL string) *VlogShareService {
+	return &VlogShareService{
+		shareRepo: shareRepo,
+		vlogRepo:  vlogRepo,
+		txManager: txManager,
+		baseURL:   baseURL,
+		now:       time.Now,
+	}
+}
+
+// Issue は共有リンクを発行する
+func (s *VlogShareService) Issue(ctx context.Context, vlog *domain.Vlog, opts ShareOptions) (*domain.VlogShare, error) {
+	now := s.now()
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(now) {
+		return nil, errors.MakeInvalidArgumentError(ctx, "有効期限には未来の日時を指定してください")
+	}
+
+	code := opts.Code
+	if code == "" {
+		var err error
+		code, err = sharecode.Generate()
+		if err != nil {
+			return nil, errors.Wrap(ctx, fmt.Errorf("failed to generate share code: %w", err))
+		}
+	}
+	share := &domain.VlogShare{
+		VlogID:    vlog.ID,
+		Code:      code,
+		ExpiresAt: opts.ExpiresAt,
+	}
+	if opts.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err)
+		}
+		passwordHash := string(hash)
+		share.PasswordHash = &passwordHash
+	}
+
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.shareRepo.RevokeByVlogID(ctx, vlog.ID, now); err != nil {
+			return err
+		}
+		if err := s.shareRepo.Create(ctx, share); err != nil {
+			return err
+		}
+		vlog.ShareURL = s.ShareURL(code)
+		return s.vlogRepo.Update(ctx, vlog)
+	})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err)
+	}
+	return share, nil
+}
+
+// Current はVLogの有効な共有リンクを取得する
+func (s *VlogShareService) Current(ctx context.Context, vlogID string) (*domain.VlogShare, error) {
+	share, err := s.shareRepo.FindActiveByVlogID(ctx, vlogID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.MakeNotFoundError(ctx, "共有リンクが発行されていません")
+		}
+		return nil, errors.Wrap(ctx, err)
+	}
+	return share, nil
+}
+
+// Revoke はVLogの共有リンクを無効にする
+func (s *VlogShareService) Revoke(ctx context.Context, vlog *domain.Vlog) error {
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.shareRepo.RevokeByVlogID(ctx, vlog.ID, s.now()); err != nil {
+			return err
+		}
+		vlog.ShareURL = ""
+		return s.vlogRepo.Update(ctx, vlog)
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err)
+	}
+	return nil
+}
+
+// Resolve は共有コードからVLogを取得する
+// 無効化・期限切れの共有リンクは存在しないものとして扱い、パスワードが一致しない場合は認証エラーにする
+func (s *VlogShareService) Resolve(ctx context.Context, code, password string) (*domain.VlogShare, *domain.Vlog, error) {
+	share, err := s.shareRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.MakeNotFoundError(ctx, "共有リンクが見つかりません")
+		}
+		return nil, nil, errors.Wrap(ctx, err)
+	}
+	if share.IsExpired(s.now()) {
+		return nil, nil, errors.MakeNotFoundError(ctx, "共有リンクの有効期限が切れています")
+	}
+	if share.HasPassword() {
+		if password == "" {
+			return nil, nil, errors.MakeAuthorizationError(ctx, "この共有リンクにはパスワードが必要です")
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(*share.PasswordHash), []byte(password)); err != nil {
+			return nil, nil, errors.MakeAuthorizationError(ctx, "パスワードが正しくありません")
+		}
+	}
+
+	vlog, err := s.vlogRepo.GetByID(ctx, &domain.Vlog{BaseModel: domain.BaseModel{ID: share.VlogID}})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.MakeNotFoundError(ctx, "共有リンクが見つかりません")
+		}
+		return nil, nil, errors.Wrap(ctx, err)
+	}
+	if vlog.Status != domain.VlogStatusCompleted {
+		return nil, nil, errors.MakeNotFoundError(ctx, "共有リンクが見つかりません")
+	}
+
+	// 閲覧数の加算に失敗しても閲覧はできるようにする
+	if err := s.shareRepo.IncrementViewCount(ctx, share.ID); err != nil {
+		logger.Warn(ctx, fmt.Sprintf("failed to increment view count of share %s: %v", share.ID, err))
+	} else {
+		share.ViewCount++
+	}
+	return share, vlog, nil
+}
+
+// ShareURL は共有コードの公開URLを返す
+func (s *VlogShareService) ShareURL(code string) string {
+	return fmt.Sprintf("%s/share/%s", s.baseURL, code)
+}
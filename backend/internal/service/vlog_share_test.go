@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeVlogShareRepo struct {
+	shares []*domain.VlogShare
+}
+
+func (r *fakeVlogShareRepo) Create(ctx context.Context, share *domain.VlogShare) error {
+	share.ID = fmt.Sprintf("share-%d", len(r.shares)+1)
+	r.shares = append(r.shares, share)
+	return nil
+}
+
+func (r *fakeVlogShareRepo) FindByCode(ctx context.Context, code string) (*domain.VlogShare, error) {
+	for _, share := range r.shares {
+		if share.Code == code && share.RevokedAt == nil {
+			found := *share
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeVlogShareRepo) FindActiveByVlogID(ctx context.Context, vlogID string) (*domain.VlogShare, error) {
+	for i := len(r.shares) - 1; i >= 0; i-- {
+		if r.shares[i].VlogID == vlogID && r.shares[i].RevokedAt == nil {
+			found := *r.shares[i]
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeVlogShareRepo) RevokeByVlogID(ctx context.Context, vlogID string, revokedAt time.Time) error {
+	for _, share := range r.shares {
+		if share.VlogID == vlogID && share.RevokedAt == nil {
+			share.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeVlogShareRepo) IncrementViewCount(ctx context.Context, id string) error {
+	for _, share := range r.shares {
+		if share.ID == id {
+			share.ViewCount++
+		}
+	}
+	return nil
+}
+
+type fakeVLogRepo struct {
+	domain.IVLogRepository
+	vlogs map[string]*domain.Vlog
+}
+
+func (r *fakeVLogRepo) GetByID(ctx context.Context, model *domain.Vlog) (*domain.Vlog, error) {
+	vlog, ok := r.vlogs[model.ID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return vlog, nil
+}
+
+func (r *fakeVLogRepo) Update(ctx context.Context, vlog *domain.Vlog) error {
+	r.vlogs[vlog.ID] = vlog
+	return nil
+}
+
+func TestVlogShareService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	setup := func() (*VlogShareService, *fakeVlogShareRepo, *domain.Vlog) {
+		vlog := &domain.Vlog{BaseModel: domain.BaseModel{ID: "vlog-1"}, VideoURL: "https://r2.example.com/vlog.mp4", Status: domain.VlogStatusCompleted}
+		shareRepo := &fakeVlogShareRepo{}
+		svc := NewVlogShareService(shareRepo, &fakeVLogRepo{vlogs: map[string]*domain.Vlog{vlog.ID: vlog}}, fakeTransactionManager{}, "https://tavinikkiy.example.com")
+		svc.now = func() time.Time { return now }
+		return svc, shareRepo, vlog
+	}
+
+	t.Run("共有コードを保存して共有URLから閲覧できるようにする", func(t *testing.T) {
+		svc, _, vlog := setup()
+
+		share, err := svc.Issue(ctx, vlog, ShareOptions{Code: "code1"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://tavinikkiy.example.com/share/code1", vlog.ShareURL)
+		assert.False(t, share.HasPassword())
+
+		resolved, resolvedVlog, err := svc.Resolve(ctx, "code1", "")
+		require.NoError(t, err)
+		assert.Equal(t, vlog.VideoURL, resolvedVlog.VideoURL)
+		assert.Equal(t, 1, resolved.ViewCount)
+
+		resolved, _, err = svc.Resolve(ctx, "code1", "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, resolved.ViewCount)
+	})
+
+	t.Run("再発行すると以前の共有コードは無効になる", func(t *testing.T) {
+		svc, _, vlog := setup()
+
+		_, err := svc.Issue(ctx, vlog, ShareOptions{Code: "code1"})
+		require.NoError(t, err)
+		share, err := svc.Issue(ctx, vlog, ShareOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, share.Code)
+		assert.Equal(t, svc.ShareURL(share.Code), vlog.ShareURL)
+
+		_, _, err = svc.Resolve(ctx, "code1", "")
+		assert.Equal(t, errors.ErrCodeNotFound, errors.GetCode(err))
+		current, err := svc.Current(ctx, vlog.ID)
+		require.NoError(t, err)
+		assert.Equal(t, share.Code, current.Code)
+	})
+
+	t.Run("無効にした共有コードは閲覧できない", func(t *testing.T) {
+		svc, _, vlog := setup()
+
+		_, err := svc.Issue(ctx, vlog, ShareOptions{Code: "code1"})
+		require.NoError(t, err)
+		require.NoError(t, svc.Revoke(ctx, vlog))
+		assert.Empty(t, vlog.ShareURL)
+
+		_, _, err = svc.Resolve(ctx, "code1", "")
+		assert.Equal(t, errors.ErrCodeNotFound, errors.GetCode(err))
+		_, err = svc.Current(ctx, vlog.ID)
+		assert.Equal(t, errors.ErrCodeNotFound, errors.GetCode(err))
+	})
+
+	t.Run("パスワード付きの共有リンクはパスワードが一致する場合のみ閲覧できる", func(t *testing.T) {
+		svc, shareRepo, vlog := setup()
+
+		share, err := svc.Issue(ctx, vlog, ShareOptions{Code: "code1", Password: "secret"})
+		require.NoError(t, err)
+		assert.True(t, share.HasPassword())
+		assert.NotEqual(t, "secret", *share.PasswordHash)
+
+		_, _, err = svc.Resolve(ctx, "code1", "")
+		assert.Equal(t, errors.ErrCodeUnAuthorized, errors.GetCode(err))
+		_, _, err = svc.Resolve(ctx, "code1", "wrong")
+		assert.Equal(t, errors.ErrCodeUnAuthorized, errors.GetCode(err))
+		assert.Equal(t, 0, shareRepo.shares[0].ViewCount)
+
+		_, _, err = svc.Resolve(ctx, "code1", "secret")
+		require.NoError(t, err)
+	})
+
+	t.Run("有効期限が過ぎた共有リンクは閲覧できない", func(t *testing.T) {
+		svc, _, vlog := setup()
+
+		expiresAt := now.Add(time.Hour)
+		_, err := svc.Issue(ctx, vlog, ShareOptions{Code: "code1", ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+		_, _, err = svc.Resolve(ctx, "code1", "")
+		require.NoError(t, err)
+
+		svc.now = func() time.Time { return now.Add(time.Hour) }
+		_, _, err = svc.Resolve(ctx, "code1", "")
+		assert.Equal(t, errors.ErrCodeNotFound, errors.GetCode(err))
+	})
+
+	t.Run("過去の有効期限は指定できない", func(t *testing.T) {
+		svc, shareRepo, vlog := setup()
+
+		_, err := svc.Issue(ctx, vlog, ShareOptions{ExpiresAt: &now})
+		assert.Equal(t, errors.ErrCodeInValidArgument, errors.GetCode(err))
+		assert.Empty(t, shareRepo.shares)
+	})
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/o-ga09/zenn-hackthon-2026/pkg/logger"
+)
+
+type vlogStepKey struct {
+	name string
+	item int
+}
+
+// VlogStepRecorder はVLog生成フローのステップの実行状況と出力（チェックポイント）をvlog_stepsに記録する
+// agent.StepObserver・agent.Checkpointerとしてフローのコンテキストに設定して使用する
+// 記録に失敗してもVLogの生成は継続する
+// キャンセルされたステップの終了も記録できるよう、記録はコンテキストのキャンセルの影響を受けない
+type VlogStepRecorder struct {
+	repo   domain.IVlogStepRepository
+	vlogID string
+	now    func() time.Time
+
+	mu    sync.Mutex
+	steps map[vlogStepKey]*domain.VlogStep
+}
+
+// NewVlogStepRecorder は記録済みのステップを読み込んでレコーダーを作成する
+// タスクの再実行時は同じステップの記録を更新し、再実行回数を加算する
+func NewVlogStepRecorder(ctx context.Context, repo domain.IVlogStepRepository, vlogID string) (*VlogStepRecorder, error) {
+	existing, err := repo.ListByVlogID(ctx, vlogID)
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[vlogStepKey]*domain.VlogStep, len(existing))
+	for _, step := range existing {
+		steps[vlogStepKey{name: step.Name, item: step.ItemIndex}] = step
+	}
+	return &VlogStepRecorder{
+		repo:   repo,
+		vlogID: vlogID,
+		now:    time.Now,
+		steps:  steps,
+	}, nil
+}
+
+// StepStarted はステップを実行中として記録する
+func (r *VlogStepRecorder) StepStarted(ctx context.Context, name agent.StepName, item int) {
+	ctx = context.WithoutCancel(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saveLocked(ctx, name, item, func(step *domain.VlogStep) {
+		step.Status = domain.VlogStepStatusRunning
+		step.StartedAt = r.now()
+		step.EndedAt = nil
+		step.ErrorMessage = ""
+	})
+}
+
+// StepFinished はステップの終了を記録する
+func (r *VlogStepRecorder) StepFinished(ctx context.Context, name agent.StepName, item int, err error) {
+	ctx = context.WithoutCancel(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := vlogStepKey{name: string(name), item: item}
+	step, ok := r.steps[key]
+	if !ok {
+		return
+	}
+	endedAt := r.now()
+	step.EndedAt = &endedAt
+	step.Status = domain.VlogStepStatusCompleted
+	if err != nil {
+		step.Status = domain.VlogStepStatusFailed
+		step.ErrorMessage = err.Error()
+	}
+	if updateErr := r.repo.Update(ctx, step); updateErr != nil {
+		logger.Warn(ctx, "failed to record vlog step", "vlog_id", r.vlogID, "step", name, "error", updateErr)
+	}
+}
+
+// StepSkipped は実行しなかったステップを記録する
+func (r *VlogStepRecorder) StepSkipped(ctx context.Context, name agent.StepName, item int) {
+	ctx = context.WithoutCancel(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saveLocked(ctx, name, item, func(step *domain.VlogStep) {
+		now := r.now()
+		step.Status = domain.VlogStepStatusSkipped
+		step.StartedAt = now
+		step.EndedAt = &now
+		step.ErrorMessage = ""
+	})
+}
+
+// Finished は終了したステップを開始日時の順に返す
+func (r *VlogStepRecorder) Finished() []*domain.VlogStep {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	steps := make([]*domain.VlogStep, 0, len(r.steps))
+	for _, step := range r.steps {
+		if step.EndedAt == nil {
+			continue
+		}
+		copied := *step
+		steps = append(steps, &copied)
+	}
+	sortVlogSteps(steps)
+	return steps
+}
+
+// LoadCheckpoint は保存済みのステップの出力を返す
+func (r *VlogStepRecorder) LoadCheckpoint(ctx context.Context, name agent.StepName, item int) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	step, ok := r.steps[vlogStepKey{name: string(name), item: item}]
+	if !ok || step.Checkpoint == nil {
+		return nil, false
+	}
+	return []byte(*step.Checkpoint), true
+}
+
+// SaveCheckpoint は実行中のステップの出力を保存する
+func (r *VlogStepRecorder) SaveCheckpoint(ctx context.Context, name agent.StepName, item int, data []byte) error {
+	ctx = context.WithoutCancel(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	step, ok := r.steps[vlogStepKey{name: string(name), item: item}]
+	if !ok {
+		return fmt.Errorf("step %s(%d) is not started", name, item)
+	}
+	checkpoint := string(data)
+	step.Checkpoint = &checkpoint
+	return r.repo.Update(ctx, step)
+}
+
+// saveLocked はステップの記録を作成または更新する
+// 記録済みのステップを再度開始した場合は再実行回数を加算する
+func (r *VlogStepRecorder) saveLocked(ctx context.Context, name agent.StepName, item int, apply func(step *domain.VlogStep)) {
+	key := vlogStepKey{name: string(name), item: item}
+	step, ok := r.steps[key]
+	if !ok {
+		step = &domain.VlogStep{VlogID: r.vlogID, Name: string(name), ItemIndex: item}
+		apply(step)
+		if err := r.repo.Create(ctx, step); err != nil {
+			logger.Warn(ctx, "failed to record vlog step", "vlog_id", r.vlogID, "step", name, "error", err)
+			return
+		}
+		r.steps[key] = step
+		return
+	}
+
+	step.RetryCount++
+	apply(step)
+	if err := r.repo.Update(ctx, step); err != nil {
+		logger.Warn(ctx, "failed to record vlog step", "vlog_id", r.vlogID, "step", name, "error", err)
+	}
+}
+
+// sortVlogSteps はステップを開始日時・アイテム番号の順に並べる
+func sortVlogSteps(steps []*domain.VlogStep) {
+	sort.SliceStable(steps, func(i, j int) bool {
+		if !steps[i].StartedAt.Equal(steps[j].StartedAt) {
+			return steps[i].StartedAt.Before(steps[j].StartedAt)
+		}
+		return steps[i].ItemIndex < steps[j].ItemIndex
+	})
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/zenn-hackthon-2026/internal/agent"
+	"github.com/o-ga09/zenn-hackthon-2026/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVlogStepRepo struct {
+	steps []*domain.VlogStep
+}
+
+func (r *fakeVlogStepRepo) Create(ctx context.Context, step *domain.VlogStep) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	step.ID = fmt.Sprintf("step-%d", len(r.steps)+1)
+	copied := *step
+	r.steps = append(r.steps, &copied)
+	return nil
+}
+
+func (r *fakeVlogStepRepo) Update(ctx context.Context, step *domain.VlogStep) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i, s := range r.steps {
+		if s.ID == step.ID {
+			copied := *step
+			r.steps[i] = &copied
+			return nil
+		}
+	}
+	return fmt.Errorf("step not found: %s", step.ID)
+}
+
+func (r *fakeVlogStepRepo) ListByVlogID(ctx context.Context, vlogID string) ([]*domain.VlogStep, error) {
+	var steps []*domain.VlogStep
+	for _, s := range r.steps {
+		if s.VlogID == vlogID {
+			copied := *s
+			steps = append(steps, &copied)
+		}
+	}
+	return steps, nil
+}
+
+func TestVlogStepRecorder(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	newRecorder := func(t *testing.T, repo *fakeVlogStepRepo) *VlogStepRecorder {
+		recorder, err := NewVlogStepRecorder(ctx, repo, "vlog-1")
+		require.NoError(t, err)
+		now := base
+		recorder.now = func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}
+		return recorder
+	}
+
+	t.Run("ステップの開始・終了・スキップを記録する", func(t *testing.T) {
+		repo := &fakeVlogStepRepo{}
+		recorder := newRecorder(t, repo)
+		stepCtx := agent.WithStepObserver(ctx, recorder)
+
+		agent.SkipStep(stepCtx, agent.StepNameMediaAnalysis, 1)
+		require.NoError(t, agent.RunStep(stepCtx, agent.StepNameMediaAnalysis, 2, func() error { return nil }))
+		err := agent.RunStep(stepCtx, agent.StepNameVeoRequest, 0, func() error { return fmt.Errorf("veo unavailable") })
+		assert.Error(t, err)
+
+		require.Len(t, repo.steps, 3)
+		assert.Equal(t, domain.VlogStepStatusSkipped, repo.steps[0].Status)
+		assert.Equal(t, domain.VlogStepStatusCompleted, repo.steps[1].Status)
+		assert.Equal(t, 2, repo.steps[1].ItemIndex)
+		assert.Equal(t, time.Second, repo.steps[1].Duration())
+		assert.Equal(t, domain.VlogStepStatusFailed, repo.steps[2].Status)
+		assert.Equal(t, "veo unavailable", repo.steps[2].ErrorMessage)
+
+		finished := recorder.Finished()
+		require.Len(t, finished, 3)
+		assert.Equal(t, string(agent.StepNameVeoRequest), finished[2].Name)
+	})
+
+	t.Run("再実行したステップは記録を更新して再実行回数を加算する", func(t *testing.T) {
+		repo := &fakeVlogStepRepo{}
+		first := newRecorder(t, repo)
+		_ = agent.RunStep(agent.WithStepObserver(ctx, first), agent.StepNameR2Upload, 0, func() error { return fmt.Errorf("timeout") })
+
+		// タスクの再実行時は記録済みのステップを読み込む
+		second := newRecorder(t, repo)
+		stepCtx := agent.WithStepObserver(ctx, second)
+		second.StepStarted(stepCtx, agent.StepNameR2Upload, 0)
+		assert.Empty(t, second.Finished())
+		second.StepFinished(stepCtx, agent.StepNameR2Upload, 0, nil)
+
+		require.Len(t, repo.steps, 1)
+		assert.Equal(t, 1, repo.steps[0].RetryCount)
+		assert.Equal(t, domain.VlogStepStatusCompleted, repo.steps[0].Status)
+		assert.Empty(t, repo.steps[0].ErrorMessage)
+	})
+
+	t.Run("キャンセルされたステップの終了も記録する", func(t *testing.T) {
+		repo := &fakeVlogStepRepo{}
+		recorder := newRecorder(t, repo)
+		stepCtx, cancel := context.WithCancel(agent.WithStepObserver(ctx, recorder))
+
+		err := agent.RunStep(stepCtx, agent.StepNameVeoRequest, 0, func() error {
+			cancel()
+			return stepCtx.Err()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		require.Len(t, repo.steps, 1)
+		assert.Equal(t, domain.VlogStepStatusFailed, repo.steps[0].Status)
+	})
+
+	t.Run("チェックポイントを保存し、タスクの再実行時に復元する", func(t *testing.T) {
+		type veoCheckpoint struct {
+			OperationName string `json:"operation_name"`
+		}
+		repo := &fakeVlogStepRepo{}
+		first := newRecorder(t, repo)
+		firstCtx := agent.WithCheckpointer(agent.WithStepObserver(ctx, first), first)
+		_ = agent.RunStep(firstCtx, agent.StepNameVeoRequest, 0, func() error {
+			agent.SaveCheckpoint(firstCtx, agent.StepNameVeoRequest, 0, veoCheckpoint{OperationName: "operations/1"})
+			return fmt.Errorf("process restarted")
+		})
+
+		second := newRecorder(t, repo)
+		secondCtx := agent.WithCheckpointer(agent.WithStepObserver(ctx, second), second)
+		var restored veoCheckpoint
+		require.NoError(t, agent.RunStep(secondCtx, agent.StepNameVeoRequest, 0, func() error {
+			// 再実行でステップを開始してもチェックポイントは保持される
+			require.True(t, agent.LoadCheckpoint(secondCtx, agent.StepNameVeoRequest, 0, &restored))
+			return nil
+		}))
+		assert.Equal(t, "operations/1", restored.OperationName)
+		assert.False(t, agent.LoadCheckpoint(secondCtx, agent.StepNameR2Upload, 0, &restored))
+
+		// 開始していないステップのチェックポイントは保存しない
+		assert.Error(t, second.SaveCheckpoint(secondCtx, agent.StepNameR2Upload, 0, []byte(`{}`)))
+	})
+}
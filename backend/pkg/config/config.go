@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/storage"
 	firebase "firebase.google.com/go/v4"
@@ -23,23 +24,44 @@ const CtxEnvKey Env = "env"
 const CtxGenAIKey GenAIConfig = "genai_config"
 
 type Config struct {
-	Env                       string `env:"ENV" envDefault:"dev"`
-	Port                      string `env:"PORT" envDefault:"8080"`
-	Database_url              string `env:"DATABASE_URL_FOR_AGENT_TAVINIKKIY" envDefault:""`
-	Sentry_DSN                string `env:"SENTRY_DSN" envDefault:""`
-	ProjectID                 string `env:"PROJECT_ID" envDefault:"tavinikkiy"`
-	CLOUDFLARE_R2_ACCOUNT_ID  string `env:"CLOUDFLARE_R2_ACCOUNT_ID" envDefault:""`
-	CLOUDFLARE_R2_ACCESSKEY   string `env:"CLOUDFLARE_R2_ACCESSKEY" envDefault:""`
-	CLOUDFLARE_R2_SECRETKEY   string `env:"CLOUDFLARE_R2_SECRETKEY" envDefault:""`
-	CLOUDFLARE_R2_BUCKET_NAME string `env:"CLOUDFLARE_R2_BUCKET_NAME" envDefault:"tavinikkiy-local"`
-	CLOUDFLARE_R2_PUBLIC_URL  string `env:"CLOUDFLARE_R2_PUBLIC_URL" envDefault:"http://localhost:4566"`
-	COOKIE_DOMAIN             string `env:"COOKIE_DOMAIN" envDefault:"localhost"`
-	BASE_URL                  string `env:"BASE_URL" envDefault:"http://localhost:3000"`
-	GCS_TEMP_BUCKET           string `env:"GCS_TEMP_BUCKET" envDefault:"tavinikkiy-temp"`
-	GCS_LOCATION              string `env:"GCS_LOCATION" envDefault:"us-central1"`
-	CLOUD_TASKS_QUEUE_NAME    string `env:"CLOUD_TASKS_QUEUE_NAME" envDefault:"tavinikkiy-agent-queue"`
-	CLOUD_TASKS_LOCATION      string `env:"CLOUD_TASKS_LOCATION" envDefault:"asia-northeast1"`
-	SERVICE_ACCOUNT_EMAIL     string `env:"SERVICE_ACCOUNT_EMAIL" envDefault:""`
+	Env                       string        `env:"ENV" envDefault:"dev"`
+	Port                      string        `env:"PORT" envDefault:"8080"`
+	Database_url              string        `env:"DATABASE_URL_FOR_AGENT_TAVINIKKIY" envDefault:""`
+	Sentry_DSN                string        `env:"SENTRY_DSN" envDefault:""`
+	ProjectID                 string        `env:"PROJECT_ID" envDefault:"tavinikkiy"`
+	CLOUDFLARE_R2_ACCOUNT_ID  string        `env:"CLOUDFLARE_R2_ACCOUNT_ID" envDefault:""`
+	CLOUDFLARE_R2_ACCESSKEY   string        `env:"CLOUDFLARE_R2_ACCESSKEY" envDefault:""`
+	CLOUDFLARE_R2_SECRETKEY   string        `env:"CLOUDFLARE_R2_SECRETKEY" envDefault:""`
+	CLOUDFLARE_R2_BUCKET_NAME string        `env:"CLOUDFLARE_R2_BUCKET_NAME" envDefault:"tavinikkiy-local"`
+	CLOUDFLARE_R2_PUBLIC_URL  string        `env:"CLOUDFLARE_R2_PUBLIC_URL" envDefault:"http://localhost:4566"`
+	COOKIE_DOMAIN             string        `env:"COOKIE_DOMAIN" envDefault:"localhost"`
+	BASE_URL                  string        `env:"BASE_URL" envDefault:"http://localhost:3000"`
+	GCS_TEMP_BUCKET           string        `env:"GCS_TEMP_BUCKET" envDefault:"tavinikkiy-temp"`
+	GCS_LOCATION              string        `env:"GCS_LOCATION" envDefault:"us-central1"`
+	CLOUD_TASKS_QUEUE_NAME    string        `env:"CLOUD_TASKS_QUEUE_NAME" envDefault:"tavinikkiy-agent-queue"`
+	CLOUD_TASKS_LOCATION      string        `env:"CLOUD_TASKS_LOCATION" envDefault:"asia-northeast1"`
+	SERVICE_ACCOUNT_EMAIL     string        `env:"SERVICE_ACCOUNT_EMAIL" envDefault:""`
+	SCHEDULER_SERVICE_ACCOUNT string        `env:"SCHEDULER_SERVICE_ACCOUNT" envDefault:""` // Cloud Schedulerのサービスアカウント（空の場合はSERVICE_ACCOUNT_EMAIL）
+	QUEUE_DRIVER              string        `env:"QUEUE_DRIVER" envDefault:"cloudtasks"`    // cloudtasks または local（プロセス内キュー）
+	LOCAL_QUEUE_WORKERS       int           `env:"LOCAL_QUEUE_WORKERS" envDefault:"4"`
+	LOCAL_QUEUE_CAPACITY      int           `env:"LOCAL_QUEUE_CAPACITY" envDefault:"100"`
+	TASK_AUTH_MODE            string        `env:"TASK_AUTH_MODE" envDefault:"oidc"` // oidc または local（ローカル署名鍵で検証）
+	TASK_OIDC_ISSUER          string        `env:"TASK_OIDC_ISSUER" envDefault:"https://accounts.google.com"`
+	TASK_OIDC_JWKS_URL        string        `env:"TASK_OIDC_JWKS_URL" envDefault:"https://www.googleapis.com/oauth2/v3/certs"`
+	PROGRESS_BUS_DRIVER       string        `env:"PROGRESS_BUS_DRIVER" envDefault:"mysql"` // mysql（複数インスタンス間で配信）または memory（プロセス内）
+	PROGRESS_POLL_INTERVAL    time.Duration `env:"PROGRESS_POLL_INTERVAL" envDefault:"1s"`
+	VIDEO_GENERATOR_DRIVER    string        `env:"VIDEO_GENERATOR_DRIVER" envDefault:"veo"` // veo または fake（ffmpegで単色のクリップを生成）
+	THUMBNAIL_PREVIEW_FORMAT  string        `env:"THUMBNAIL_PREVIEW_FORMAT" envDefault:""`  // gif または webp（空の場合はアニメーションプレビューを作成しない）
+	SUBTITLE_FONT_NAME        string        `env:"SUBTITLE_FONT_NAME" envDefault:""`        // 字幕を動画に焼き込むときのフォント（空の場合はNoto Sans CJK JP）
+	VOICEVOX_URL              string        `env:"VOICEVOX_URL" envDefault:""`              // ナレーションを合成するVOICEVOX ENGINEのURL（空の場合はナレーションを付けない）
+	STRIPE_API_BASE_URL       string        `env:"STRIPE_API_BASE_URL" envDefault:"https://api.stripe.com"`
+	STRIPE_SECRET_KEY         string        `env:"STRIPE_SECRET_KEY" envDefault:""`
+	STRIPE_WEBHOOK_SECRET     string        `env:"STRIPE_WEBHOOK_SECRET" envDefault:""`
+	STRIPE_PRICE_100_TOKENS   string        `env:"STRIPE_PRICE_100_TOKENS" envDefault:""`
+	STRIPE_PRICE_500_TOKENS   string        `env:"STRIPE_PRICE_500_TOKENS" envDefault:""`
+	STRIPE_PRICE_1000_TOKENS  string        `env:"STRIPE_PRICE_1000_TOKENS" envDefault:""`
+	STRIPE_PRICE_MONTHLY      string        `env:"STRIPE_PRICE_MONTHLY" envDefault:""`
+	STRIPE_PRICE_YEARLY       string        `env:"STRIPE_PRICE_YEARLY" envDefault:""`
 }
 
 func New(ctx context.Context) (context.Context, error) {
@@ -0,0 +1,22 @@
+package constant
+
+// トークン消費コスト定数（docs/requirements/012_billing_and_pricing.md）
+const (
+	// 画像分析（1枚）
+	TokenCostImageAnalysis = 10
+
+	// 動画分析（10秒ごと）
+	TokenCostVideoAnalysisPer10Sec = 20
+
+	// スクリプト生成（1回）
+	TokenCostScriptGeneration = 20
+
+	// 動画生成（1分ごと）
+	TokenCostVideoGenerationPerMinute = 50
+)
+
+// プランごとのトークン付与数
+const (
+	// プレミアムプラン（月額・年額）の毎月の付与トークン数
+	PremiumPlanMonthlyTokens = 300
+)
@@ -9,4 +9,13 @@ const (
 
 	// Vlogデフォルト秒数
 	DefaultVLogDurationSeconds = 8
+
+	// Veoで生成する1クリップの最大秒数
+	VeoClipMaxSeconds = 8
+
+	// ナレーションのデフォルトの話者（VOICEVOXのスタイルID: ずんだもん ノーマル）
+	DefaultNarrationSpeakerID = 3
+
+	// VLogの最大秒数（最大シーン数 × 1クリップの最大秒数）
+	MaxVLogDurationSeconds = MaxVLogScenes * VeoClipMaxSeconds
 )
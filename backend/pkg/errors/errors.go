@@ -70,6 +70,21 @@ var (
 	ErrForeignKeyConstraint   = errors.New("foreign key constraint error")
 	ErrUniqueConstraint       = errors.New("unique constraint error")
 
+	// トークンエラー
+	ErrInsufficientTokens        = errors.New("トークン残高が不足しています。")
+	ErrTokenReservationNotFound  = errors.New("トークンの仮引きが見つかりません。")
+	ErrTokenReservationCancelled = errors.New("トークンの仮引きは取り消し済みです。")
+
+	// 決済エラー
+	ErrInvalidStripeSignature = errors.New("Stripeの署名が不正です。")
+
+	// タスクエラー
+	ErrTaskInProgress = errors.New("タスクは他のワーカーで実行中です。")
+	ErrTaskLeaseLost  = errors.New("タスクの実行権が他のワーカーに移りました。")
+
+	// VLogエラー
+	ErrVlogCancelled = errors.New("VLogの生成はキャンセルされました。")
+
 	// 画像エラー
 	ErrInvalidImageType  = errors.New("ファイルの種類が不正です。")
 	ErrFailedImageName   = errors.New("ファイル名の生成に失敗しました。")
@@ -129,6 +144,20 @@ func MakeSystemError(ctx context.Context, msg string) {
 	logger.Error(ctx, errMessage, callStack, stack)
 }
 
+// 認可エラーを作成して、ログ出力する
+func MakeForbiddenError(ctx context.Context, msg string) error {
+	var wrapped error
+	if msg == "" {
+		wrapped = failure.Translate(ErrUnauthorized, ErrTypeForbidden)
+	} else {
+		wrapped = failure.Translate(errors.New(msg), ErrTypeForbidden)
+	}
+	stack := getCallstack(wrapped)
+	errMessage := GetMessage(wrapped)
+	logger.Warn(ctx, errMessage, callStack, stack)
+	return wrapped
+}
+
 func MakeBusinessError(ctx context.Context, msg string) error {
 	var wrapped error
 	if msg == "" {
@@ -0,0 +1,289 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EXIFのタグ
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagDateTimeDigitized  = 0x9004
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+)
+
+// EXIFの値の型ごとのバイト数
+var exifTypeSizes = map[uint16]uint32{
+	1:  1, // BYTE
+	2:  1, // ASCII
+	3:  2, // SHORT
+	4:  4, // LONG
+	5:  8, // RATIONAL
+	7:  1, // UNDEFINED
+	9:  4, // SLONG
+	10: 8, // SRATIONAL
+}
+
+// exifDateTimeLayout はEXIFの日時の形式
+const exifDateTimeLayout = "2006:01:02 15:04:05"
+
+var (
+	pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	exifHeader   = []byte("Exif\x00\x00")
+)
+
+// extractJPEG はJPEGのAPP1セグメントのEXIFから撮影情報を取得する
+func extractJPEG(data []byte) (*Metadata, error) {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("invalid JPEG marker at %d", pos)
+		}
+		marker := data[pos+1]
+		// 画像データの開始・終了以降にメタデータはない
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if size < 2 || pos+2+size > len(data) {
+			return nil, fmt.Errorf("invalid JPEG segment size at %d", pos)
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			return parseTIFF(segment[len(exifHeader):])
+		}
+		pos += 2 + size
+	}
+	return &Metadata{}, nil
+}
+
+// extractPNG はPNGのeXIfチャンクから撮影情報を取得する
+func extractPNG(data []byte) (*Metadata, error) {
+	pos := len(pngSignature)
+	for pos+8 <= len(data) {
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		if size < 0 || pos+12+size > len(data) {
+			return nil, fmt.Errorf("invalid PNG chunk size at %d", pos)
+		}
+		switch chunkType {
+		case "eXIf":
+			return parseTIFF(data[pos+8 : pos+8+size])
+		case "IDAT", "IEND":
+			return &Metadata{}, nil
+		}
+		pos += 12 + size // 長さ・種類・データ・CRC
+	}
+	return &Metadata{}, nil
+}
+
+// extractWebP はWebPのEXIFチャンクから撮影情報を取得する
+func extractWebP(data []byte) (*Metadata, error) {
+	pos := 12
+	for pos+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if size < 0 || pos+8+size > len(data) {
+			return nil, fmt.Errorf("invalid WebP chunk size at %d", pos)
+		}
+		if string(data[pos:pos+4]) == "EXIF" {
+			// "Exif\0\0" から始まるファイルもある
+			return parseTIFF(bytes.TrimPrefix(data[pos+8:pos+8+size], exifHeader))
+		}
+		pos += 8 + size + size%2 // チャンクは偶数バイトに揃える
+	}
+	return &Metadata{}, nil
+}
+
+// tiffReader はEXIF（TIFF形式）のIFDを読み取る
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// ifdEntry はIFDのエントリー
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// parseTIFF はEXIF（TIFF形式）から撮影情報を取得する
+func parseTIFF(data []byte) (*Metadata, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("EXIF data too short")
+	}
+	r := &tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid EXIF byte order %q", data[:2])
+	}
+	if r.order.Uint16(data[2:]) != 42 {
+		return nil, fmt.Errorf("invalid EXIF header")
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+	meta := &Metadata{
+		CameraModel: cameraModel(r.ascii(ifd0[tagMake]), r.ascii(ifd0[tagModel])),
+		Orientation: int(r.uint(ifd0[tagOrientation])),
+	}
+	if meta.Orientation < 1 || meta.Orientation > 8 {
+		meta.Orientation = 0
+	}
+
+	capturedAt := r.ascii(ifd0[tagDateTime])
+	offset := ""
+	if entry, ok := ifd0[tagExifIFD]; ok {
+		exif, err := r.readIFD(r.uint(entry))
+		if err != nil {
+			return nil, err
+		}
+		if v := r.ascii(exif[tagDateTimeDigitized]); v != "" {
+			capturedAt = v
+		}
+		if v := r.ascii(exif[tagDateTimeOriginal]); v != "" {
+			capturedAt = v
+		}
+		offset = r.ascii(exif[tagOffsetTimeOriginal])
+	}
+	meta.CapturedAt = parseExifTime(capturedAt, offset)
+
+	if entry, ok := ifd0[tagGPSIFD]; ok {
+		gps, err := r.readIFD(r.uint(entry))
+		if err != nil {
+			return nil, err
+		}
+		meta.Location = r.gpsLocation(gps)
+	}
+	return meta, nil
+}
+
+// readIFD は指定した位置のIFDのエントリーをタグごとに返す
+func (r *tiffReader) readIFD(offset uint32) (map[uint16]ifdEntry, error) {
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return nil, fmt.Errorf("IFD offset out of range: %d", offset)
+	}
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(r.data) {
+		return nil, fmt.Errorf("IFD entries out of range: %d", offset)
+	}
+
+	entries := make(map[uint16]ifdEntry, count)
+	for i := range count {
+		raw := r.data[start+i*12 : start+(i+1)*12]
+		typ := r.order.Uint16(raw[2:])
+		size, ok := exifTypeSizes[typ]
+		if !ok {
+			continue
+		}
+		n := r.order.Uint32(raw[4:])
+		length := uint64(size) * uint64(n)
+		value := raw[8:12]
+		if length > 4 {
+			valueOffset := uint64(r.order.Uint32(raw[8:]))
+			if valueOffset+length > uint64(len(r.data)) {
+				continue
+			}
+			value = r.data[valueOffset : valueOffset+length]
+		}
+		entries[r.order.Uint16(raw)] = ifdEntry{typ: typ, count: n, value: value[:min(length, uint64(len(value)))]}
+	}
+	return entries, nil
+}
+
+// ascii は文字列の値を返す
+func (r *tiffReader) ascii(entry ifdEntry) string {
+	if entry.typ != 2 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+}
+
+// uint はSHORT・LONGの値を返す
+func (r *tiffReader) uint(entry ifdEntry) uint32 {
+	switch {
+	case entry.typ == 3 && len(entry.value) >= 2:
+		return uint32(r.order.Uint16(entry.value))
+	case entry.typ == 4 && len(entry.value) >= 4:
+		return r.order.Uint32(entry.value)
+	}
+	return 0
+}
+
+// rationals はRATIONALの値を返す
+func (r *tiffReader) rationals(entry ifdEntry) []float64 {
+	if entry.typ != 5 {
+		return nil
+	}
+	values := make([]float64, 0, entry.count)
+	for i := 0; i+8 <= len(entry.value); i += 8 {
+		denominator := r.order.Uint32(entry.value[i+4:])
+		if denominator == 0 {
+			return nil
+		}
+		values = append(values, float64(r.order.Uint32(entry.value[i:]))/float64(denominator))
+	}
+	return values
+}
+
+// gpsLocation はGPS IFDの度・分・秒の緯度・経度を10進数に変換する
+func (r *tiffReader) gpsLocation(gps map[uint16]ifdEntry) *Location {
+	lat := r.rationals(gps[tagGPSLatitude])
+	lon := r.rationals(gps[tagGPSLongitude])
+	if len(lat) != 3 || len(lon) != 3 {
+		return nil
+	}
+	latitude := lat[0] + lat[1]/60 + lat[2]/3600
+	if r.ascii(gps[tagGPSLatitudeRef]) == "S" {
+		latitude = -latitude
+	}
+	longitude := lon[0] + lon[1]/60 + lon[2]/3600
+	if r.ascii(gps[tagGPSLongitudeRef]) == "W" {
+		longitude = -longitude
+	}
+	location, err := validLocation(latitude, longitude)
+	if err != nil {
+		return nil
+	}
+	return location
+}
+
+// parseExifTime はEXIFの日時を返す
+// タイムゾーン（OffsetTimeOriginal）が記録されていない場合は撮影地の時刻をUTCとして扱う
+func parseExifTime(value, offset string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	loc := time.UTC
+	if offset != "" {
+		if t, err := time.Parse("-07:00", offset); err == nil {
+			_, seconds := t.Zone()
+			loc = time.FixedZone(offset, seconds)
+		}
+	}
+	t, err := time.ParseInLocation(exifDateTimeLayout, value, loc)
+	if err != nil || t.Year() < 1900 {
+		return nil
+	}
+	return &t
+}
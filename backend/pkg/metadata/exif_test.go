@@ -0,0 +1,193 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exifEntry はテスト用のEXIFのエントリー
+type exifEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// buildTIFF はIFD0・Exif IFD・GPS IFDを持つリトルエンディアンのEXIFを作成する
+func buildTIFF(ifd0, exif, gps []exifEntry) []byte {
+	order := binary.LittleEndian
+	ifdSize := func(entries []exifEntry) int { return 2 + len(entries)*12 + 4 }
+
+	// IFD0の後にExif IFD・GPS IFD、その後に4バイトを超える値を置く
+	ifd0 = append([]exifEntry{}, ifd0...)
+	if len(exif) > 0 {
+		ifd0 = append(ifd0, exifEntry{tag: tagExifIFD, typ: 4, count: 1})
+	}
+	if len(gps) > 0 {
+		ifd0 = append(ifd0, exifEntry{tag: tagGPSIFD, typ: 4, count: 1})
+	}
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset + ifdSize(exif)
+	valueOffset := gpsOffset + ifdSize(gps)
+
+	var values bytes.Buffer
+	writeIFD := func(buf *bytes.Buffer, entries []exifEntry) {
+		_ = binary.Write(buf, order, uint16(len(entries)))
+		for _, e := range entries {
+			_ = binary.Write(buf, order, e.tag)
+			_ = binary.Write(buf, order, e.typ)
+			_ = binary.Write(buf, order, e.count)
+			switch {
+			case e.tag == tagExifIFD:
+				_ = binary.Write(buf, order, uint32(exifOffset))
+			case e.tag == tagGPSIFD:
+				_ = binary.Write(buf, order, uint32(gpsOffset))
+			case len(e.value) > 4:
+				_ = binary.Write(buf, order, uint32(valueOffset+values.Len()))
+				values.Write(e.value)
+			default:
+				buf.Write(append(e.value, make([]byte, 4-len(e.value))...))
+			}
+		}
+		_ = binary.Write(buf, order, uint32(0))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("II")
+	_ = binary.Write(&buf, order, uint16(42))
+	_ = binary.Write(&buf, order, uint32(8))
+	writeIFD(&buf, ifd0)
+	writeIFD(&buf, exif)
+	writeIFD(&buf, gps)
+	buf.Write(values.Bytes())
+	return buf.Bytes()
+}
+
+func asciiEntry(tag uint16, value string) exifEntry {
+	return exifEntry{tag: tag, typ: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+func shortEntry(tag uint16, value uint16) exifEntry {
+	return exifEntry{tag: tag, typ: 3, count: 1, value: binary.LittleEndian.AppendUint16(nil, value)}
+}
+
+func rationalEntry(tag uint16, values ...[2]uint32) exifEntry {
+	var data []byte
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v[0])
+		data = binary.LittleEndian.AppendUint32(data, v[1])
+	}
+	return exifEntry{tag: tag, typ: 5, count: uint32(len(values)), value: data}
+}
+
+// testTIFF は東京タワー付近で撮影したEXIF
+func testTIFF() []byte {
+	return buildTIFF(
+		[]exifEntry{
+			asciiEntry(tagMake, "Apple"),
+			asciiEntry(tagModel, "iPhone 15 Pro"),
+			shortEntry(tagOrientation, 6),
+			asciiEntry(tagDateTime, "2026:05:04 12:00:00"),
+		},
+		[]exifEntry{
+			asciiEntry(tagDateTimeOriginal, "2026:05:03 10:15:30"),
+			asciiEntry(tagOffsetTimeOriginal, "+09:00"),
+		},
+		[]exifEntry{
+			asciiEntry(tagGPSLatitudeRef, "N"),
+			rationalEntry(tagGPSLatitude, [2]uint32{35, 1}, [2]uint32{39, 1}, [2]uint32{3096, 100}),
+			asciiEntry(tagGPSLongitudeRef, "E"),
+			rationalEntry(tagGPSLongitude, [2]uint32{139, 1}, [2]uint32{44, 1}, [2]uint32{4344, 100}),
+		},
+	)
+}
+
+// testJPEG はAPP1セグメントにEXIFを埋め込んだJPEGを作成する
+func testJPEG(t *testing.T, tiff []byte) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	require.NoError(t, jpeg.Encode(&img, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil))
+	if tiff == nil {
+		return img.Bytes()
+	}
+	segment := append(append([]byte{}, exifHeader...), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, img.Bytes()[2:]...)
+}
+
+func assertTokyoTower(t *testing.T, meta *Metadata) {
+	t.Helper()
+	require.NotNil(t, meta.CapturedAt)
+	assert.True(t, meta.CapturedAt.Equal(time.Date(2026, 5, 3, 1, 15, 30, 0, time.UTC)))
+	_, offset := meta.CapturedAt.Zone()
+	assert.Equal(t, 9*60*60, offset)
+	require.NotNil(t, meta.Location)
+	assert.InDelta(t, 35.6586, meta.Location.Latitude, 0.0001)
+	assert.InDelta(t, 139.7454, meta.Location.Longitude, 0.0001)
+	assert.Equal(t, "Apple iPhone 15 Pro", meta.CameraModel)
+	assert.Equal(t, 6, meta.Orientation)
+}
+
+func TestExtract_Image(t *testing.T) {
+	t.Run("JPEGのEXIFから撮影日時・位置・機種・向きを取得する", func(t *testing.T) {
+		meta, err := Extract(testJPEG(t, testTIFF()))
+		require.NoError(t, err)
+		assertTokyoTower(t, meta)
+	})
+
+	t.Run("PNGのeXIfチャンクから撮影情報を取得する", func(t *testing.T) {
+		tiff := testTIFF()
+		data := append([]byte{}, pngSignature...)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(tiff)))
+		data = append(append(data, "eXIf"...), tiff...)
+		data = append(data, 0, 0, 0, 0) // CRC
+		meta, err := Extract(data)
+		require.NoError(t, err)
+		assertTokyoTower(t, meta)
+	})
+
+	t.Run("WebPのEXIFチャンクから撮影情報を取得する", func(t *testing.T) {
+		tiff := testTIFF()
+		chunk := binary.LittleEndian.AppendUint32([]byte("EXIF"), uint32(len(tiff)))
+		chunk = append(chunk, tiff...)
+		data := binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(len(chunk)+4))
+		data = append(append(data, "WEBP"...), chunk...)
+		meta, err := Extract(data)
+		require.NoError(t, err)
+		assertTokyoTower(t, meta)
+	})
+
+	t.Run("タイムゾーンが記録されていない場合は撮影地の時刻をUTCとして扱う", func(t *testing.T) {
+		meta, err := Extract(testJPEG(t, buildTIFF([]exifEntry{
+			asciiEntry(tagModel, "Canon EOS R5"),
+			asciiEntry(tagMake, "Canon"),
+			asciiEntry(tagDateTime, "2026:05:04 12:00:00"),
+		}, nil, nil)))
+		require.NoError(t, err)
+		require.NotNil(t, meta.CapturedAt)
+		assert.Equal(t, time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC), *meta.CapturedAt)
+		assert.Nil(t, meta.Location)
+		assert.Equal(t, "Canon EOS R5", meta.CameraModel)
+		assert.Zero(t, meta.Orientation)
+	})
+
+	t.Run("EXIFがない場合は空のMetadataを返す", func(t *testing.T) {
+		meta, err := Extract(testJPEG(t, nil))
+		require.NoError(t, err)
+		assert.Equal(t, &Metadata{}, meta)
+	})
+
+	t.Run("壊れたEXIFの場合はエラーを返す", func(t *testing.T) {
+		_, err := Extract(testJPEG(t, []byte("II*\x00\xff\xff\xff\xff")))
+		assert.Error(t, err)
+	})
+}
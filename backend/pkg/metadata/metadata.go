@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata はメディアファイルに記録された撮影情報
+type Metadata struct {
+	CapturedAt  *time.Time // 撮影日時（記録されていない場合はnil）
+	Location    *Location  // 撮影地点（記録されていない場合はnil）
+	CameraModel string     // カメラの機種（メーカー名を含む）
+	Orientation int        // 向き（EXIFのOrientation、1〜8。記録されていない場合は0）
+	Duration    float64    // 動画の長さ（秒、画像や記録されていない場合は0）
+}
+
+// Location は撮影地点の緯度・経度
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Extract はJPEG・PNG・WebPのEXIF、MP4・MOVのメタデータから撮影情報（動画は長さも）を取得する
+// 対応していない形式やメタデータがないファイルの場合は空のMetadataを返す
+func Extract(data []byte) (*Metadata, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return extractJPEG(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return extractPNG(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return extractWebP(data)
+	case isBMFF(data):
+		return extractBMFF(data)
+	}
+	return &Metadata{}, nil
+}
+
+// cameraModel はメーカー名と機種名をつなげる（機種名にメーカー名が含まれる場合は機種名のみ）
+func cameraModel(maker, model string) string {
+	switch {
+	case model == "":
+		return maker
+	case maker == "" || strings.HasPrefix(strings.ToLower(model), strings.ToLower(maker)):
+		return model
+	default:
+		return maker + " " + model
+	}
+}
+
+// iso6709Pattern はISO 6709形式の位置（例: +35.6586+139.7454+040.000/）
+var iso6709Pattern = regexp.MustCompile(`^([+-]\d+(?:\.\d+)?)([+-]\d+(?:\.\d+)?)`)
+
+// parseISO6709 はISO 6709形式の位置から緯度・経度を取得する
+func parseISO6709(value string) (*Location, error) {
+	m := iso6709Pattern.FindStringSubmatch(value)
+	if m == nil {
+		return nil, fmt.Errorf("invalid ISO 6709 location %q", value)
+	}
+	lat, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q: %w", m[1], err)
+	}
+	lon, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q: %w", m[2], err)
+	}
+	return validLocation(lat, lon)
+}
+
+// validLocation は緯度・経度が範囲内の場合にLocationを返す
+func validLocation(lat, lon float64) (*Location, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("location out of range: %f,%f", lat, lon)
+	}
+	return &Location{Latitude: lat, Longitude: lon}, nil
+}
@@ -0,0 +1,253 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// mp4Epoch はMP4の日時の基準（1904-01-01 UTC）
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// QuickTimeのメタデータ（mdta）のキー
+const (
+	keyLocation     = "com.apple.quicktime.location.ISO6709"
+	keyMake         = "com.apple.quicktime.make"
+	keyModel        = "com.apple.quicktime.model"
+	keyCreationDate = "com.apple.quicktime.creationdate"
+)
+
+// box はMP4（ISO BMFF）のボックス
+type box struct {
+	typ  string
+	data []byte // ヘッダーを除いた中身
+}
+
+// isBMFF はMP4・MOV（ISO BMFF）のファイルかどうかを返す
+func isBMFF(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+	switch string(data[4:8]) {
+	case "ftyp", "moov", "mdat", "wide", "free", "skip":
+		return true
+	}
+	return false
+}
+
+// readBoxes はボックスの並びを読み取る
+func readBoxes(data []byte) ([]box, error) {
+	var boxes []box
+	for pos := 0; pos+8 <= len(data); {
+		size := uint64(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		header := uint64(8)
+		switch size {
+		case 0: // ファイルの終わりまで
+			size = uint64(len(data) - pos)
+		case 1: // 64bitのサイズ
+			if pos+16 > len(data) {
+				return nil, fmt.Errorf("invalid box %q at %d", typ, pos)
+			}
+			size = binary.BigEndian.Uint64(data[pos+8:])
+			header = 16
+		}
+		if size < header || uint64(pos)+size > uint64(len(data)) {
+			return nil, fmt.Errorf("invalid box %q size at %d", typ, pos)
+		}
+		boxes = append(boxes, box{typ: typ, data: data[uint64(pos)+header : uint64(pos)+size]})
+		pos += int(size)
+	}
+	return boxes, nil
+}
+
+// findBox は指定した種類の最初のボックスを返す
+func findBox(boxes []box, typ string) (box, bool) {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b, true
+		}
+	}
+	return box{}, false
+}
+
+// extractBMFF はMP4・MOVのメタデータから撮影情報を取得する
+// 撮影日時・位置・機種はQuickTimeのメタデータ（iPhoneなど）を優先し、ない場合はmvhd・©xyzを使用する
+func extractBMFF(data []byte) (*Metadata, error) {
+	top, err := readBoxes(data)
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return &Metadata{}, nil
+	}
+	children, err := readBoxes(moov.data)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{}
+	if mvhd, ok := findBox(children, "mvhd"); ok {
+		meta.CapturedAt = mvhdCreationTime(mvhd.data)
+		meta.Duration = mvhdDuration(mvhd.data)
+	}
+	for _, trak := range children {
+		if trak.typ != "trak" {
+			continue
+		}
+		if orientation := trackOrientation(trak.data); orientation != 0 {
+			meta.Orientation = orientation
+			break
+		}
+	}
+	if udta, ok := findBox(children, "udta"); ok {
+		if items, err := readBoxes(udta.data); err == nil {
+			if xyz, ok := findBox(items, "\xa9xyz"); ok && len(xyz.data) > 4 {
+				// 文字列の長さ（2バイト）・言語（2バイト）の後にISO 6709形式の位置が続く
+				if location, err := parseISO6709(string(xyz.data[4:])); err == nil {
+					meta.Location = location
+				}
+			}
+		}
+	}
+	if metaBox, ok := findBox(children, "meta"); ok {
+		values := quickTimeMetadata(metaBox.data)
+		if v := values[keyCreationDate]; v != "" {
+			if t, err := time.Parse("2006-01-02T15:04:05-0700", v); err == nil {
+				meta.CapturedAt = &t
+			}
+		}
+		if v := values[keyLocation]; v != "" {
+			if location, err := parseISO6709(v); err == nil {
+				meta.Location = location
+			}
+		}
+		meta.CameraModel = cameraModel(values[keyMake], values[keyModel])
+	}
+	return meta, nil
+}
+
+// mvhdCreationTime はmvhdの作成日時を返す（記録されていない場合はnil）
+func mvhdCreationTime(data []byte) *time.Time {
+	var seconds uint64
+	switch {
+	case len(data) >= 12 && data[0] == 1:
+		seconds = binary.BigEndian.Uint64(data[4:])
+	case len(data) >= 8 && data[0] == 0:
+		seconds = uint64(binary.BigEndian.Uint32(data[4:]))
+	}
+	if seconds == 0 {
+		return nil
+	}
+	t := mp4Epoch.Add(time.Duration(seconds) * time.Second)
+	return &t
+}
+
+// mvhdDuration はmvhdの長さを秒で返す（記録されていない場合は0）
+func mvhdDuration(data []byte) float64 {
+	var timescale uint32
+	var duration uint64
+	switch {
+	case len(data) >= 32 && data[0] == 1:
+		// バージョン1は作成・更新日時と長さが64bit
+		timescale = binary.BigEndian.Uint32(data[20:])
+		duration = binary.BigEndian.Uint64(data[24:])
+		if duration == math.MaxUint64 {
+			return 0
+		}
+	case len(data) >= 20 && data[0] == 0:
+		timescale = binary.BigEndian.Uint32(data[12:])
+		duration = uint64(binary.BigEndian.Uint32(data[16:]))
+		if duration == math.MaxUint32 {
+			return 0
+		}
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return float64(duration) / float64(timescale)
+}
+
+// trackOrientation はトラックの変換行列の回転をEXIFのOrientationに変換する（回転していない場合は0）
+func trackOrientation(data []byte) int {
+	children, err := readBoxes(data)
+	if err != nil {
+		return 0
+	}
+	tkhd, ok := findBox(children, "tkhd")
+	if !ok || len(tkhd.data) == 0 {
+		return 0
+	}
+	// バージョン1は日時・長さが64bit
+	matrix := 40
+	if tkhd.data[0] == 1 {
+		matrix = 52
+	}
+	if len(tkhd.data) < matrix+36 {
+		return 0
+	}
+	a := int32(binary.BigEndian.Uint32(tkhd.data[matrix:]))
+	b := int32(binary.BigEndian.Uint32(tkhd.data[matrix+4:]))
+	const one = 1 << 16 // 16.16の固定小数点の1
+	switch {
+	case a == 0 && b == one:
+		return 6 // 90度
+	case a == -one && b == 0:
+		return 3 // 180度
+	case a == 0 && b == -one:
+		return 8 // 270度
+	}
+	return 0
+}
+
+// quickTimeMetadata はQuickTimeのメタデータ（keys・ilst）の文字列の値をキーごとに返す
+func quickTimeMetadata(data []byte) map[string]string {
+	values := map[string]string{}
+	children, err := readBoxes(data)
+	if err != nil {
+		return values
+	}
+	keysBox, ok := findBox(children, "keys")
+	if !ok || len(keysBox.data) < 8 {
+		return values
+	}
+	ilst, ok := findBox(children, "ilst")
+	if !ok {
+		return values
+	}
+
+	// keysはバージョン・フラグ（4バイト）・件数（4バイト）の後に、サイズ・名前空間・キー名が続く
+	var keys []string
+	for pos := 8; pos+8 <= len(keysBox.data); {
+		size := int(binary.BigEndian.Uint32(keysBox.data[pos:]))
+		if size < 8 || pos+size > len(keysBox.data) {
+			break
+		}
+		keys = append(keys, string(keysBox.data[pos+8:pos+size]))
+		pos += size
+	}
+
+	items, err := readBoxes(ilst.data)
+	if err != nil {
+		return values
+	}
+	for _, item := range items {
+		// ilstの各項目の種類はkeysの1始まりの番号
+		index := int(binary.BigEndian.Uint32([]byte(item.typ)))
+		if index < 1 || index > len(keys) {
+			continue
+		}
+		entries, err := readBoxes(item.data)
+		if err != nil {
+			continue
+		}
+		// dataは型（4バイト）・ロケール（4バイト）の後に値が続く（型1はUTF-8の文字列）
+		if value, ok := findBox(entries, "data"); ok && len(value.data) > 8 && binary.BigEndian.Uint32(value.data) == 1 {
+			values[keys[index-1]] = strings.TrimSpace(string(value.data[8:]))
+		}
+	}
+	return values
+}
@@ -0,0 +1,130 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mp4Box はテスト用のボックスを作成する
+func mp4Box(typ string, children ...[]byte) []byte {
+	size := 8
+	for _, c := range children {
+		size += len(c)
+	}
+	data := binary.BigEndian.AppendUint32(nil, uint32(size))
+	data = append(data, typ...)
+	for _, c := range children {
+		data = append(data, c...)
+	}
+	return data
+}
+
+// mvhdBox は作成日時・長さ（ミリ秒単位）を記録したバージョン0のmvhdを作成する
+func mvhdBox(createdAt time.Time, duration time.Duration) []byte {
+	data := make([]byte, 100)
+	binary.BigEndian.PutUint32(data[4:], uint32(createdAt.Sub(mp4Epoch)/time.Second))
+	binary.BigEndian.PutUint32(data[12:], 1000)
+	binary.BigEndian.PutUint32(data[16:], uint32(duration.Milliseconds()))
+	return mp4Box("mvhd", data)
+}
+
+// mvhdBoxV1 は長さを記録したバージョン1のmvhdを作成する
+func mvhdBoxV1(timescale uint32, duration uint64) []byte {
+	data := make([]byte, 112)
+	data[0] = 1
+	binary.BigEndian.PutUint32(data[20:], timescale)
+	binary.BigEndian.PutUint64(data[24:], duration)
+	return mp4Box("mvhd", data)
+}
+
+// tkhdBox は変換行列の先頭（a・b）を指定したバージョン0のtkhdを作成する
+func tkhdBox(a, b int32) []byte {
+	data := make([]byte, 84)
+	binary.BigEndian.PutUint32(data[40:], uint32(a))
+	binary.BigEndian.PutUint32(data[44:], uint32(b))
+	return mp4Box("tkhd", data)
+}
+
+// quickTimeMetaBox はQuickTimeのメタデータ（keys・ilst）を作成する
+func quickTimeMetaBox(values map[string]string, order []string) []byte {
+	keys := binary.BigEndian.AppendUint32(make([]byte, 4), uint32(len(order)))
+	var items [][]byte
+	for i, key := range order {
+		keys = binary.BigEndian.AppendUint32(keys, uint32(len(key)+8))
+		keys = append(append(keys, "mdta"...), key...)
+		value := binary.BigEndian.AppendUint32(nil, 1) // UTF-8
+		value = append(append(value, 0, 0, 0, 0), values[key]...)
+		index := string(binary.BigEndian.AppendUint32(nil, uint32(i+1)))
+		items = append(items, mp4Box(index, mp4Box("data", value)))
+	}
+	return mp4Box("meta", mp4Box("keys", keys), mp4Box("ilst", items...))
+}
+
+func TestExtract_Video(t *testing.T) {
+	ftyp := mp4Box("ftyp", []byte("qt  \x00\x00\x00\x00qt  "))
+	createdAt := time.Date(2026, 5, 3, 1, 0, 0, 0, time.UTC)
+
+	t.Run("mvhdの作成日時・長さ・©xyzの位置・トラックの回転を取得する", func(t *testing.T) {
+		xyz := append([]byte{0, 26, 0x15, 0xC7}, "+35.0394+135.7292+050.000/"...)
+		data := append(ftyp, mp4Box("moov",
+			mvhdBox(createdAt, 12500*time.Millisecond),
+			mp4Box("trak", tkhdBox(0, 1<<16)),
+			mp4Box("udta", mp4Box("\xa9xyz", xyz)),
+		)...)
+		data = append(data, mp4Box("mdat", make([]byte, 16))...)
+
+		meta, err := Extract(data)
+		require.NoError(t, err)
+		require.NotNil(t, meta.CapturedAt)
+		assert.Equal(t, createdAt, *meta.CapturedAt)
+		assert.InDelta(t, 12.5, meta.Duration, 0.001)
+		require.NotNil(t, meta.Location)
+		assert.InDelta(t, 35.0394, meta.Location.Latitude, 0.0001)
+		assert.InDelta(t, 135.7292, meta.Location.Longitude, 0.0001)
+		assert.Equal(t, 6, meta.Orientation)
+		assert.Empty(t, meta.CameraModel)
+	})
+
+	t.Run("QuickTimeのメタデータの撮影日時・位置・機種を優先する", func(t *testing.T) {
+		data := append(ftyp, mp4Box("moov",
+			mvhdBox(createdAt, 0),
+			mp4Box("trak", tkhdBox(1<<16, 0)),
+			quickTimeMetaBox(map[string]string{
+				keyCreationDate: "2026-05-03T19:30:00+0900",
+				keyLocation:     "+34.9671+135.7727+030.000/",
+				keyMake:         "Apple",
+				keyModel:        "iPhone 15 Pro",
+			}, []string{keyCreationDate, keyLocation, keyMake, keyModel}),
+		)...)
+
+		meta, err := Extract(data)
+		require.NoError(t, err)
+		require.NotNil(t, meta.CapturedAt)
+		assert.True(t, meta.CapturedAt.Equal(time.Date(2026, 5, 3, 10, 30, 0, 0, time.UTC)))
+		require.NotNil(t, meta.Location)
+		assert.InDelta(t, 34.9671, meta.Location.Latitude, 0.0001)
+		assert.Equal(t, "Apple iPhone 15 Pro", meta.CameraModel)
+		assert.Zero(t, meta.Orientation)
+	})
+
+	t.Run("バージョン1のmvhdの長さを取得する", func(t *testing.T) {
+		meta, err := Extract(append(ftyp, mp4Box("moov", mvhdBoxV1(90000, 90000*95))...))
+		require.NoError(t, err)
+		assert.InDelta(t, 95.0, meta.Duration, 0.001)
+	})
+
+	t.Run("moovがない場合は空のMetadataを返す", func(t *testing.T) {
+		meta, err := Extract(append(ftyp, mp4Box("mdat", make([]byte, 16))...))
+		require.NoError(t, err)
+		assert.Equal(t, &Metadata{}, meta)
+	})
+
+	t.Run("ボックスのサイズが壊れている場合はエラーを返す", func(t *testing.T) {
+		_, err := Extract(append(ftyp, 0, 0, 0xFF, 0xFF, 'm', 'o', 'o', 'v'))
+		assert.Error(t, err)
+	})
+}
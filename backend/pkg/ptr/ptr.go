@@ -28,6 +28,13 @@ func BoolToPtr(b bool) *bool {
 	return &b
 }
 
+func PtrToBool(b *bool) bool {
+	if b == nil {
+		return false
+	}
+	return *b
+}
+
 func PtrToInt(i *int) int {
 	if i == nil {
 		return 0
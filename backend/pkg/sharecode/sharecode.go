@@ -0,0 +1,23 @@
+package sharecode
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+// codeBytes は共有コードの乱数のバイト数（128bit）
+const codeBytes = 16
+
+// encoding は共有コードに使用する英数字の符号化（パディングなし）
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate は推測できない共有コードを生成する
+// URLに含めるため、小文字の英数字のみを使用する
+func Generate() (string, error) {
+	b := make([]byte, codeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(encoding.EncodeToString(b)), nil
+}
@@ -0,0 +1,131 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format は字幕ファイルの形式
+type Format string
+
+const (
+	FormatSRT  Format = "srt"
+	FormatVTT  Format = "vtt"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat は字幕ファイルの形式を変換する（未指定の場合はSRT、不明な値の場合はfalse）
+func ParseFormat(value string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FormatSRT:
+		return FormatSRT, true
+	case FormatVTT:
+		return FormatVTT, true
+	case FormatJSON:
+		return FormatJSON, true
+	default:
+		return "", false
+	}
+}
+
+// ContentType は字幕ファイルのMIMEタイプを返す
+func (f Format) ContentType() string {
+	switch f {
+	case FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "application/x-subrip; charset=utf-8"
+	}
+}
+
+// Cue は字幕の1区間
+type Cue struct {
+	Start float64 `json:"start_time"` // 秒
+	End   float64 `json:"end_time"`   // 秒
+	Text  string  `json:"text"`
+}
+
+// Encode は字幕を指定した形式のファイルにする
+func Encode(format Format, cues []Cue) ([]byte, error) {
+	switch format {
+	case FormatVTT:
+		return EncodeVTT(cues), nil
+	case FormatJSON:
+		if cues == nil {
+			cues = []Cue{}
+		}
+		return json.Marshal(cues)
+	default:
+		return EncodeSRT(cues), nil
+	}
+}
+
+// EncodeSRT は字幕をSRT形式にする
+func EncodeSRT(cues []Cue) []byte {
+	var buf bytes.Buffer
+	for i, cue := range cues {
+		fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1, FormatTime(cue.Start, FormatSRT), FormatTime(cue.End, FormatSRT), normalizeText(cue.Text))
+	}
+	return buf.Bytes()
+}
+
+// EncodeVTT は字幕をWebVTT形式にする
+func EncodeVTT(cues []Cue) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n\n")
+	for i, cue := range cues {
+		// WebVTTでは "-->" を本文に含められないため置き換える
+		text := strings.ReplaceAll(normalizeText(cue.Text), "-->", "->")
+		fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1, FormatTime(cue.Start, FormatVTT), FormatTime(cue.End, FormatVTT), text)
+	}
+	return buf.Bytes()
+}
+
+// FormatTime は秒を字幕の時間（SRTは "00:00:01,000"、WebVTTは "00:00:01.000"）にする
+func FormatTime(seconds float64, format Format) string {
+	ms := int64(math.Round(math.Max(seconds, 0) * 1000))
+	separator := ","
+	if format == FormatVTT {
+		separator = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
+
+// ParseTime はSRT・WebVTT形式の時間を秒にする
+func ParseTime(value string) (float64, error) {
+	parts := strings.Split(strings.Replace(strings.TrimSpace(value), ",", ".", 1), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid subtitle time: %q", value)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid subtitle time: %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid subtitle time: %q", value)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid subtitle time: %q", value)
+	}
+	return float64(hours*3600+minutes*60) + seconds, nil
+}
+
+// normalizeText は空行で字幕の区切りと誤認されないよう、本文の空行を取り除く
+func normalizeText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
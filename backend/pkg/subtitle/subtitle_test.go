@@ -0,0 +1,75 @@
+package subtitle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, ok := ParseFormat("")
+	assert.True(t, ok)
+	assert.Equal(t, FormatSRT, format)
+
+	format, ok = ParseFormat(" VTT ")
+	assert.True(t, ok)
+	assert.Equal(t, FormatVTT, format)
+	assert.Equal(t, "text/vtt; charset=utf-8", format.ContentType())
+
+	_, ok = ParseFormat("ass")
+	assert.False(t, ok)
+}
+
+func TestTime(t *testing.T) {
+	t.Run("SRTはカンマ、WebVTTはピリオドでミリ秒を区切る", func(t *testing.T) {
+		assert.Equal(t, "01:02:03,450", FormatTime(3723.45, FormatSRT))
+		assert.Equal(t, "01:02:03.450", FormatTime(3723.45, FormatVTT))
+		assert.Equal(t, "00:00:00,000", FormatTime(-1, FormatSRT))
+	})
+
+	t.Run("SRT・WebVTT形式の時間を秒にする", func(t *testing.T) {
+		sec, err := ParseTime("00:01:02,500")
+		require.NoError(t, err)
+		assert.InDelta(t, 62.5, sec, 0.0001)
+
+		sec, err = ParseTime("00:01:02.500")
+		require.NoError(t, err)
+		assert.InDelta(t, 62.5, sec, 0.0001)
+
+		_, err = ParseTime("62.5")
+		assert.Error(t, err)
+	})
+}
+
+func TestEncode(t *testing.T) {
+	cues := []Cue{
+		{Start: 0, End: 3.5, Text: "清水寺に到着"},
+		{Start: 4, End: 7.25, Text: "抹茶パフェ\n\nおいしい --> 最高"},
+	}
+
+	t.Run("SRT形式にする", func(t *testing.T) {
+		data, err := Encode(FormatSRT, cues)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n00:00:00,000 --> 00:00:03,500\n清水寺に到着\n\n"+
+			"2\n00:00:04,000 --> 00:00:07,250\n抹茶パフェ\nおいしい --> 最高\n\n", string(data))
+	})
+
+	t.Run("WebVTT形式にする", func(t *testing.T) {
+		data, err := Encode(FormatVTT, cues)
+		require.NoError(t, err)
+		assert.Equal(t, "WEBVTT\n\n"+
+			"1\n00:00:00.000 --> 00:00:03.500\n清水寺に到着\n\n"+
+			"2\n00:00:04.000 --> 00:00:07.250\n抹茶パフェ\nおいしい -> 最高\n\n", string(data))
+	})
+
+	t.Run("JSON形式にする（字幕がない場合は空の配列）", func(t *testing.T) {
+		data, err := Encode(FormatJSON, cues[:1])
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"start_time":0,"end_time":3.5,"text":"清水寺に到着"}]`, string(data))
+
+		data, err = Encode(FormatJSON, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+	})
+}
@@ -0,0 +1,79 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MusicOptions はBGMのミックスの設定
+type MusicOptions struct {
+	Volume         float64 // BGMの音量（0の場合は0.25）
+	FadeOutSeconds float64 // 動画の終わりでBGMをフェードアウトする長さ（秒）
+}
+
+// MixMusic はBGMを動画の長さに合わせて（短い場合はループして）動画の音声の下に重ねる
+// 動画の音声（Veoの音声・ナレーション）が鳴っている間はBGMの音量を下げる（ダッキング）
+// 映像はそのままコピーし、動画の長さは変えない
+func (t Tools) MixMusic(ctx context.Context, data, music []byte, opts MusicOptions) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "vlog-music-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.mp4")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write input: %w", err)
+	}
+	musicPath := filepath.Join(dir, "music")
+	if err := os.WriteFile(musicPath, music, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write music: %w", err)
+	}
+	info, err := t.Probe(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	output := filepath.Join(dir, "output.mp4")
+	err = t.run(ctx, t.FFmpeg, "-y", "-hide_banner", "-loglevel", "error",
+		"-i", input, "-stream_loop", "-1", "-i", musicPath,
+		"-filter_complex", buildMusicFilter(info.HasAudio, info.Duration, opts),
+		"-map", "0:v", "-map", "[aout]", "-c:v", "copy", "-c:a", "aac", "-b:a", "128k",
+		"-t", formatSeconds(info.Duration), "-movflags", "+faststart", output)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
+
+// buildMusicFilter はBGMを動画の長さで切り、動画の音声をキーにしてダッキングするfilter_complexを組み立てる
+// 入力0が動画、入力1がBGMで、出力のラベルは[aout]
+func buildMusicFilter(hasAudio bool, duration float64, opts MusicOptions) string {
+	volume := opts.Volume
+	if volume <= 0 {
+		volume = 0.25
+	}
+	chain := []string{
+		fmt.Sprintf("aresample=%d", outputSampleRate),
+		"aformat=channel_layouts=stereo",
+		"volume=" + formatSeconds(volume),
+		"atrim=end=" + formatSeconds(duration),
+	}
+	if fade := min(opts.FadeOutSeconds, duration); fade > 0 {
+		chain = append(chain, fmt.Sprintf("afade=t=out:st=%s:d=%s", formatSeconds(duration-fade), formatSeconds(fade)))
+	}
+
+	if !hasAudio {
+		return fmt.Sprintf("[1:a]%s[aout]", strings.Join(chain, ","))
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("[1:a]%s[music]", strings.Join(chain, ",")),
+		fmt.Sprintf("[0:a]aresample=%d,aformat=channel_layouts=stereo,asplit=2[main][key]", outputSampleRate),
+		// 動画の音声が鳴っている間だけBGMを圧縮する
+		"[music][key]sidechaincompress=threshold=0.02:ratio=8:attack=20:release=400[ducked]",
+		"[main][ducked]amix=inputs=2:duration=first:normalize=0[aout]",
+	}, ";")
+}
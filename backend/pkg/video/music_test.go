@@ -0,0 +1,49 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMusicFilter(t *testing.T) {
+	t.Run("動画の音声をキーにしてBGMをダッキングする", func(t *testing.T) {
+		filter := buildMusicFilter(true, 10, MusicOptions{Volume: 0.3, FadeOutSeconds: 2})
+		assert.Equal(t, "[1:a]aresample=48000,aformat=channel_layouts=stereo,volume=0.300,atrim=end=10.000,afade=t=out:st=8.000:d=2.000[music];"+
+			"[0:a]aresample=48000,aformat=channel_layouts=stereo,asplit=2[main][key];"+
+			"[music][key]sidechaincompress=threshold=0.02:ratio=8:attack=20:release=400[ducked];"+
+			"[main][ducked]amix=inputs=2:duration=first:normalize=0[aout]", filter)
+	})
+
+	t.Run("音声のない動画はBGMだけを使用する", func(t *testing.T) {
+		filter := buildMusicFilter(false, 1, MusicOptions{FadeOutSeconds: 2})
+		assert.Equal(t, "[1:a]aresample=48000,aformat=channel_layouts=stereo,volume=0.250,atrim=end=1.000,afade=t=out:st=0.000:d=1.000[aout]", filter)
+	})
+}
+
+func TestMixMusic(t *testing.T) {
+	tools := DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	clip, err := tools.RenderSolidClip(ctx, "blue", 3*time.Second, 320, 180)
+	require.NoError(t, err)
+
+	// 動画より短いBGMはループする
+	mixed, err := tools.MixMusic(ctx, clip, silentWAV(1), MusicOptions{FadeOutSeconds: 1})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "mixed.mp4")
+	require.NoError(t, os.WriteFile(path, mixed, 0o600))
+	info, err := tools.Probe(ctx, path)
+	require.NoError(t, err)
+	assert.InDelta(t, 3, info.Duration, 0.1)
+	assert.True(t, info.HasAudio)
+}
@@ -0,0 +1,107 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 区間に収まらないナレーションを速める最大の倍率（これ以上速めると聞き取りにくくなるため、残りは切り捨てる）
+const maxNarrationTempo = 1.5
+
+// NarrationClip は動画に重ねるナレーションの音声
+type NarrationClip struct {
+	Data        []byte  // WAV
+	Start       float64 // 再生を開始する位置（秒）
+	Duration    float64 // 音声の長さ（秒）
+	MaxDuration float64 // 再生できる長さ（秒、0の場合は制限しない）
+}
+
+// MixOptions はナレーションのミックスの設定
+type MixOptions struct {
+	NarrationVolume  float64 // ナレーションの音量（0の場合は1.0）
+	BackgroundVolume float64 // 元の動画の音声の音量（ナレーションが聞き取れるよう下げる）
+}
+
+// MixNarration はナレーションの音声をそれぞれの開始位置に配置し、動画の音声に重ねる
+// 映像はそのままコピーし、動画の長さは変えない
+func (t Tools) MixNarration(ctx context.Context, data []byte, clips []NarrationClip, opts MixOptions) ([]byte, error) {
+	if len(clips) == 0 {
+		return data, nil
+	}
+
+	dir, err := os.MkdirTemp("", "vlog-narration-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.mp4")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write input: %w", err)
+	}
+	info, err := t.Probe(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	args := []string{"-y", "-hide_banner", "-loglevel", "error", "-i", input}
+	for i, clip := range clips {
+		path := filepath.Join(dir, fmt.Sprintf("narration%03d.wav", i))
+		if err := os.WriteFile(path, clip.Data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write narration: %w", err)
+		}
+		args = append(args, "-i", path)
+	}
+
+	output := filepath.Join(dir, "output.mp4")
+	args = append(args, "-filter_complex", buildNarrationFilter(info.HasAudio, clips, opts),
+		"-map", "0:v", "-map", "[aout]", "-c:v", "copy", "-c:a", "aac", "-b:a", "128k",
+		"-t", formatSeconds(info.Duration), "-movflags", "+faststart", output)
+	if err := t.run(ctx, t.FFmpeg, args...); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
+
+// buildNarrationFilter はナレーションを開始位置まで遅らせて重ね、動画の音声とミックスするfilter_complexを組み立てる
+// 入力0が動画、入力1以降がナレーションで、出力のラベルは[aout]
+func buildNarrationFilter(hasAudio bool, clips []NarrationClip, opts MixOptions) string {
+	volume := opts.NarrationVolume
+	if volume <= 0 {
+		volume = 1
+	}
+
+	var filters []string
+	labels := make([]string, 0, len(clips))
+	for i, clip := range clips {
+		chain := []string{fmt.Sprintf("aresample=%d", outputSampleRate), "aformat=channel_layouts=stereo"}
+		if clip.MaxDuration > 0 && clip.Duration > clip.MaxDuration {
+			tempo := min(clip.Duration/clip.MaxDuration, maxNarrationTempo)
+			chain = append(chain, "atempo="+formatSeconds(tempo), "atrim=end="+formatSeconds(clip.MaxDuration))
+		}
+		delay := int64(clip.Start * 1000)
+		chain = append(chain, fmt.Sprintf("adelay=%d:all=1", delay), "volume="+formatSeconds(volume))
+		label := fmt.Sprintf("n%d", i)
+		filters = append(filters, fmt.Sprintf("[%d:a]%s[%s]", i+1, strings.Join(chain, ","), label))
+		labels = append(labels, "["+label+"]")
+	}
+
+	narration := labels[0]
+	if len(labels) > 1 {
+		filters = append(filters, fmt.Sprintf("%samix=inputs=%d:duration=longest:normalize=0[narration]", strings.Join(labels, ""), len(labels)))
+		narration = "[narration]"
+	}
+
+	if hasAudio {
+		filters = append(filters,
+			fmt.Sprintf("[0:a]aresample=%d,aformat=channel_layouts=stereo,volume=%s[background]", outputSampleRate, formatSeconds(opts.BackgroundVolume)),
+			fmt.Sprintf("[background]%samix=inputs=2:duration=first:normalize=0[aout]", narration))
+	} else {
+		// 音声のない動画は、ナレーションの後を無音で埋めて動画の長さに合わせる
+		filters = append(filters, fmt.Sprintf("%sapad[aout]", narration))
+	}
+	return strings.Join(filters, ";")
+}
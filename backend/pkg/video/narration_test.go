@@ -0,0 +1,75 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// silentWAV は指定した長さの無音のWAV（24kHz・16bit・モノラル）を作成する
+func silentWAV(seconds float64) []byte {
+	const sampleRate, bytesPerSample = 24000, 2
+	size := uint32(seconds * sampleRate * bytesPerSample)
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+size)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * bytesPerSample), uint16(bytesPerSample), uint16(16)} {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, size)
+	buf.Write(make([]byte, size))
+	return buf.Bytes()
+}
+
+func TestBuildNarrationFilter(t *testing.T) {
+	t.Run("ナレーションを開始位置まで遅らせ、動画の音声を下げてミックスする", func(t *testing.T) {
+		filter := buildNarrationFilter(true, []NarrationClip{
+			{Start: 0.5, Duration: 2, MaxDuration: 3},
+			{Start: 4, Duration: 3, MaxDuration: 2},
+		}, MixOptions{BackgroundVolume: 0.3})
+		assert.Equal(t, "[1:a]aresample=48000,aformat=channel_layouts=stereo,adelay=500:all=1,volume=1.000[n0];"+
+			"[2:a]aresample=48000,aformat=channel_layouts=stereo,atempo=1.500,atrim=end=2.000,adelay=4000:all=1,volume=1.000[n1];"+
+			"[n0][n1]amix=inputs=2:duration=longest:normalize=0[narration];"+
+			"[0:a]aresample=48000,aformat=channel_layouts=stereo,volume=0.300[background];"+
+			"[background][narration]amix=inputs=2:duration=first:normalize=0[aout]", filter)
+	})
+
+	t.Run("音声のない動画はナレーションの後を無音で埋める", func(t *testing.T) {
+		filter := buildNarrationFilter(false, []NarrationClip{{Start: 1, Duration: 2}}, MixOptions{NarrationVolume: 0.8})
+		assert.Equal(t, "[1:a]aresample=48000,aformat=channel_layouts=stereo,adelay=1000:all=1,volume=0.800[n0];[n0]apad[aout]", filter)
+	})
+}
+
+func TestMixNarration(t *testing.T) {
+	tools := DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	clip, err := tools.RenderSolidClip(ctx, "blue", 3*time.Second, 320, 180)
+	require.NoError(t, err)
+
+	mixed, err := tools.MixNarration(ctx, clip, []NarrationClip{
+		{Data: silentWAV(1), Start: 0.5, Duration: 1, MaxDuration: 1.5},
+		{Data: silentWAV(2), Start: 2, Duration: 2},
+	}, MixOptions{BackgroundVolume: 0.3})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "mixed.mp4")
+	require.NoError(t, os.WriteFile(path, mixed, 0o600))
+	info, err := tools.Probe(ctx, path)
+	require.NoError(t, err)
+	// 動画の長さを超えるナレーションは切り捨てる
+	assert.InDelta(t, 3, info.Duration, 0.1)
+	assert.True(t, info.HasAudio)
+}
@@ -0,0 +1,66 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SubtitleStyle は動画に焼き込む字幕の見た目
+type SubtitleStyle struct {
+	FontName string // 空の場合はlibassのデフォルトのフォント
+	FontSize int    // 0の場合はlibassのデフォルトの文字サイズ
+}
+
+// BurnSubtitles はSRT形式の字幕を動画に焼き込む（音声はそのままコピーする）
+func (t Tools) BurnSubtitles(ctx context.Context, data, srt []byte, style SubtitleStyle) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "vlog-subtitles-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.mp4")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write input: %w", err)
+	}
+	subtitlePath := filepath.Join(dir, "subtitles.srt")
+	if err := os.WriteFile(subtitlePath, srt, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write subtitles: %w", err)
+	}
+
+	output := filepath.Join(dir, "output.mp4")
+	err = t.run(ctx, t.FFmpeg, "-y", "-hide_banner", "-loglevel", "error", "-i", input,
+		"-vf", subtitlesFilter(subtitlePath, style),
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-c:a", "copy", "-movflags", "+faststart", output)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
+
+// subtitlesFilter はSRTファイルを焼き込むsubtitlesフィルターを組み立てる
+func subtitlesFilter(path string, style SubtitleStyle) string {
+	filter := fmt.Sprintf("subtitles=filename='%s':charenc=UTF-8", escapeFilterValue(path))
+	var styles []string
+	if style.FontName != "" {
+		styles = append(styles, "FontName="+style.FontName)
+	}
+	if style.FontSize > 0 {
+		styles = append(styles, fmt.Sprintf("FontSize=%d", style.FontSize))
+	}
+	if len(styles) > 0 {
+		// force_styleのカンマがフィルターの区切りと誤認されないようシングルクォートで囲む
+		filter += fmt.Sprintf(":force_style='%s'", escapeFilterValue(strings.Join(styles, ",")))
+	}
+	return filter
+}
+
+// escapeFilterValue はシングルクォートで囲むフィルターの引数の値をエスケープする
+// シングルクォート内ではシングルクォート以外の文字はそのまま解釈される
+func escapeFilterValue(value string) string {
+	return strings.ReplaceAll(value, `'`, `'\''`)
+}
@@ -0,0 +1,50 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtitlesFilter(t *testing.T) {
+	t.Run("フォントを指定した場合はforce_styleで指定する", func(t *testing.T) {
+		filter := subtitlesFilter("/tmp/vlog/subtitles.srt", SubtitleStyle{FontName: "Noto Sans CJK JP", FontSize: 24})
+		assert.Equal(t, "subtitles=filename='/tmp/vlog/subtitles.srt':charenc=UTF-8:force_style='FontName=Noto Sans CJK JP,FontSize=24'", filter)
+	})
+
+	t.Run("パスのシングルクォートをエスケープする", func(t *testing.T) {
+		filter := subtitlesFilter("/tmp/it's/subtitles.srt", SubtitleStyle{})
+		assert.Equal(t, `subtitles=filename='/tmp/it'\''s/subtitles.srt':charenc=UTF-8`, filter)
+	})
+}
+
+func TestBurnSubtitles(t *testing.T) {
+	tools := DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	clip, err := tools.RenderSolidClip(ctx, "blue", 2*time.Second, 320, 180)
+	require.NoError(t, err)
+	srt := []byte("1\n00:00:00,000 --> 00:00:01,500\n清水寺\n\n")
+
+	burned, err := tools.BurnSubtitles(ctx, clip, srt, SubtitleStyle{FontSize: 24})
+	if err != nil {
+		// libassなしでビルドされたffmpegではsubtitlesフィルターを使用できない
+		t.Skipf("subtitlesフィルターを使用できないためスキップ: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "burned.mp4")
+	require.NoError(t, os.WriteFile(path, burned, 0o600))
+	info, err := tools.Probe(ctx, path)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, info.Duration, 0.1)
+	assert.Equal(t, 320, info.Width)
+	assert.True(t, info.HasAudio)
+}
@@ -0,0 +1,101 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// 代表フレームを選ぶ対象のフレーム数（30fpsで約10秒）
+	thumbnailCandidateFrames = 300
+	// サムネイルのJPEGの品質（ffmpegのqscale、2が最高品質）
+	thumbnailQuality = 3
+	// アニメーションプレビューのフレームレート
+	previewFrameRate = 10
+)
+
+// PreviewFormat はアニメーションプレビューの形式
+type PreviewFormat string
+
+const (
+	PreviewFormatGIF  PreviewFormat = "gif"
+	PreviewFormatWebP PreviewFormat = "webp"
+)
+
+// ParsePreviewFormat はアニメーションプレビューの形式を変換する（未指定・不明な値の場合はfalse）
+func ParsePreviewFormat(value string) (PreviewFormat, bool) {
+	switch PreviewFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case PreviewFormatGIF:
+		return PreviewFormatGIF, true
+	case PreviewFormatWebP:
+		return PreviewFormatWebP, true
+	default:
+		return "", false
+	}
+}
+
+// ContentType はプレビューのMIMEタイプを返す
+func (f PreviewFormat) ContentType() string {
+	if f == PreviewFormatWebP {
+		return "image/webp"
+	}
+	return "image/gif"
+}
+
+// PreviewOptions はアニメーションプレビューの設定
+type PreviewOptions struct {
+	Format   PreviewFormat
+	Width    int           // 高さは縦横比を保って決める
+	Duration time.Duration // 動画の先頭から切り出す長さ
+}
+
+// ExtractThumbnail は動画から代表的なフレームを選び、width×heightに切り抜いたJPEGにする
+// 画像を渡した場合は画像をそのまま切り抜く
+func (t Tools) ExtractThumbnail(ctx context.Context, data []byte, width, height int) ([]byte, error) {
+	return t.convert(ctx, data, "thumb.jpg",
+		"-vf", thumbnailFilter(width, height),
+		"-frames:v", "1", "-q:v", fmt.Sprint(thumbnailQuality))
+}
+
+// RenderPreview は動画の先頭をループ再生するアニメーションプレビュー（GIF・WebP）にする
+func (t Tools) RenderPreview(ctx context.Context, data []byte, opts PreviewOptions) ([]byte, error) {
+	args := []string{"-t", formatSeconds(opts.Duration.Seconds()), "-an"}
+	scale := fmt.Sprintf("fps=%d,scale=%d:-2:flags=lanczos", previewFrameRate, opts.Width)
+	if opts.Format == PreviewFormatWebP {
+		args = append(args, "-vf", scale, "-c:v", "libwebp", "-lossless", "0", "-q:v", "70", "-loop", "0")
+		return t.convert(ctx, data, "preview.webp", args...)
+	}
+	// GIFは動画から作成したパレットで減色する
+	args = append(args, "-filter_complex", fmt.Sprintf("[0:v]%s,split[a][b];[a]palettegen[p];[b][p]paletteuse", scale), "-loop", "0")
+	return t.convert(ctx, data, "preview.gif", args...)
+}
+
+// thumbnailFilter は代表フレームを選び、縦横比を保ったまま拡大してwidth×heightに切り抜くフィルター
+func thumbnailFilter(width, height int) string {
+	return fmt.Sprintf("thumbnail=%d,scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,setsar=1",
+		thumbnailCandidateFrames, width, height, width, height)
+}
+
+// convert は入力をffmpegで変換し、出力ファイルの内容を返す
+func (t Tools) convert(ctx context.Context, data []byte, outputName string, args ...string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "vlog-convert-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write input: %w", err)
+	}
+	output := filepath.Join(dir, outputName)
+	ffmpegArgs := append([]string{"-y", "-hide_banner", "-loglevel", "error", "-i", input}, args...)
+	if err := t.run(ctx, t.FFmpeg, append(ffmpegArgs, output)...); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
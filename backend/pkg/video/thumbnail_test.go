@@ -0,0 +1,73 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePreviewFormat(t *testing.T) {
+	format, ok := ParsePreviewFormat("GIF")
+	assert.True(t, ok)
+	assert.Equal(t, PreviewFormatGIF, format)
+	assert.Equal(t, "image/gif", format.ContentType())
+
+	format, ok = ParsePreviewFormat(" webp ")
+	assert.True(t, ok)
+	assert.Equal(t, "image/webp", format.ContentType())
+
+	_, ok = ParsePreviewFormat("")
+	assert.False(t, ok)
+	_, ok = ParsePreviewFormat("apng")
+	assert.False(t, ok)
+}
+
+func TestExtractThumbnail(t *testing.T) {
+	tools := DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	clip, err := tools.RenderSolidClip(ctx, "red", 2*time.Second, 360, 640)
+	require.NoError(t, err)
+
+	t.Run("動画から指定したサイズのJPEGを切り出す", func(t *testing.T) {
+		thumb, err := tools.ExtractThumbnail(ctx, clip, 320, 180)
+		require.NoError(t, err)
+
+		img, format, err := image.Decode(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, image.Rect(0, 0, 320, 180), img.Bounds())
+	})
+
+	t.Run("画像を指定したサイズに切り抜く", func(t *testing.T) {
+		var src bytes.Buffer
+		require.NoError(t, jpeg.Encode(&src, image.NewRGBA(image.Rect(0, 0, 400, 400)), nil))
+
+		thumb, err := tools.ExtractThumbnail(ctx, src.Bytes(), 320, 180)
+		require.NoError(t, err)
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		assert.Equal(t, 320, cfg.Width)
+		assert.Equal(t, 180, cfg.Height)
+	})
+
+	t.Run("GIFのアニメーションプレビューを作成する", func(t *testing.T) {
+		preview, err := tools.RenderPreview(ctx, clip, PreviewOptions{Format: PreviewFormatGIF, Width: 120, Duration: time.Second})
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(preview, []byte("GIF89a")))
+	})
+
+	t.Run("動画でないデータはエラーになる", func(t *testing.T) {
+		_, err := tools.ExtractThumbnail(ctx, []byte("not a video"), 320, 180)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,277 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transition はクリップ間のトランジション効果
+type Transition string
+
+const (
+	TransitionFade  Transition = "fade"
+	TransitionSlide Transition = "slide"
+	TransitionZoom  Transition = "zoom"
+)
+
+// ParseTransition はVlogStyle.Transitionをトランジション効果に変換する（未指定・不明な値はフェード）
+func ParseTransition(value string) Transition {
+	switch Transition(strings.ToLower(strings.TrimSpace(value))) {
+	case TransitionSlide:
+		return TransitionSlide
+	case TransitionZoom:
+		return TransitionZoom
+	default:
+		return TransitionFade
+	}
+}
+
+// xfade はffmpegのxfadeフィルターのトランジション名を返す
+func (t Transition) xfade() string {
+	switch t {
+	case TransitionSlide:
+		return "slideleft"
+	case TransitionZoom:
+		return "zoomin"
+	default:
+		return "fade"
+	}
+}
+
+const (
+	// 出力動画のフレームレート
+	outputFrameRate = 30
+	// 出力音声のサンプリングレート
+	outputSampleRate = 48000
+)
+
+// Tools はffmpeg・ffprobeの実行ファイルのパス
+type Tools struct {
+	FFmpeg  string
+	FFprobe string
+}
+
+// DefaultTools はPATH上のffmpeg・ffprobeを使用する
+var DefaultTools = Tools{FFmpeg: "ffmpeg", FFprobe: "ffprobe"}
+
+// Available はffmpeg・ffprobeが実行可能かどうかを返す
+func (t Tools) Available() bool {
+	if _, err := exec.LookPath(t.FFmpeg); err != nil {
+		return false
+	}
+	_, err := exec.LookPath(t.FFprobe)
+	return err == nil
+}
+
+// ClipInfo はクリップのメタデータ
+type ClipInfo struct {
+	Duration float64 // 秒
+	Width    int
+	Height   int
+	HasAudio bool
+}
+
+// ConcatOptions はクリップの結合の設定
+type ConcatOptions struct {
+	Transition         Transition
+	TransitionDuration time.Duration
+}
+
+// Concat はクリップを順にトランジションでつなげて1つのMP4にする
+// 解像度は最初のクリップに揃え、音声はすべてのクリップに音声がある場合のみクロスフェードでつなげる
+func (t Tools) Concat(ctx context.Context, clips [][]byte, opts ConcatOptions) ([]byte, error) {
+	if len(clips) == 0 {
+		return nil, fmt.Errorf("no clips to concatenate")
+	}
+	if len(clips) == 1 {
+		return clips[0], nil
+	}
+
+	dir, err := os.MkdirTemp("", "vlog-concat-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	infos := make([]ClipInfo, 0, len(clips))
+	args := []string{"-y", "-hide_banner", "-loglevel", "error"}
+	for i, clip := range clips {
+		path := filepath.Join(dir, fmt.Sprintf("clip%03d.mp4", i))
+		if err := os.WriteFile(path, clip, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write clip: %w", err)
+		}
+		info, err := t.Probe(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to probe clip %d: %w", i, err)
+		}
+		infos = append(infos, *info)
+		args = append(args, "-i", path)
+	}
+
+	filter, hasAudio := buildConcatFilter(infos, opts)
+	output := filepath.Join(dir, "output.mp4")
+	args = append(args, "-filter_complex", filter, "-map", "[vout]")
+	if hasAudio {
+		args = append(args, "-map", "[aout]", "-c:a", "aac", "-b:a", "128k")
+	} else {
+		args = append(args, "-an")
+	}
+	args = append(args, "-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p", "-movflags", "+faststart", output)
+
+	if err := t.run(ctx, t.FFmpeg, args...); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
+
+// Probe はクリップの長さ・解像度・音声の有無を取得する
+func (t Tools) Probe(ctx context.Context, path string) (*ClipInfo, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.FFprobe, "-v", "error", "-show_entries", "format=duration:stream=codec_type,width,height", "-of", "json", path)
+	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var probe struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %q: %w", probe.Format.Duration, err)
+	}
+
+	info := &ClipInfo{Duration: duration}
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			if info.Width == 0 {
+				info.Width, info.Height = stream.Width, stream.Height
+			}
+		case "audio":
+			info.HasAudio = true
+		}
+	}
+	if info.Width == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+	return info, nil
+}
+
+// RenderSolidClip は単色の映像と無音の音声のテスト用クリップを生成する
+// 同じ引数からは同じ内容のクリップを生成する
+func (t Tools) RenderSolidClip(ctx context.Context, color string, duration time.Duration, width, height int) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "vlog-clip-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	seconds := formatSeconds(duration.Seconds())
+	output := filepath.Join(dir, "clip.mp4")
+	err = t.run(ctx, t.FFmpeg, "-y", "-hide_banner", "-loglevel", "error",
+		"-f", "lavfi", "-i", fmt.Sprintf("color=c=%s:s=%dx%d:r=%d:d=%s", color, width, height, outputFrameRate, seconds),
+		"-f", "lavfi", "-i", fmt.Sprintf("anullsrc=r=%d:cl=stereo", outputSampleRate),
+		"-t", seconds, "-c:v", "libx264", "-preset", "ultrafast", "-threads", "1", "-pix_fmt", "yuv420p", "-c:a", "aac", "-shortest",
+		"-flags", "+bitexact", "-fflags", "+bitexact", output)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(output)
+}
+
+// buildConcatFilter はクリップをxfade・acrossfadeでつなげるfilter_complexを組み立てる
+// 出力のラベルは映像が[vout]、音声が[aout]（すべてのクリップに音声がある場合のみ）
+func buildConcatFilter(infos []ClipInfo, opts ConcatOptions) (string, bool) {
+	width, height := infos[0].Width, infos[0].Height
+	hasAudio := true
+	minDuration := infos[0].Duration
+	for _, info := range infos {
+		hasAudio = hasAudio && info.HasAudio
+		minDuration = min(minDuration, info.Duration)
+	}
+	// トランジションは最も短いクリップの半分を超えないようにする
+	transition := min(opts.TransitionDuration.Seconds(), minDuration/2)
+
+	var filters []string
+	for i := range infos {
+		filters = append(filters, fmt.Sprintf(
+			"[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%d,format=yuv420p[v%d]",
+			i, width, height, width, height, outputFrameRate, i))
+		if hasAudio {
+			filters = append(filters, fmt.Sprintf("[%d:a]aresample=%d,aformat=channel_layouts=stereo[a%d]", i, outputSampleRate, i))
+		}
+	}
+
+	// n番目のトランジションの開始位置は、直前までのクリップの長さの合計からトランジションn回分を引いた位置
+	videoLabel, audioLabel := "v0", "a0"
+	offset := 0.0
+	for i := 1; i < len(infos); i++ {
+		offset += infos[i-1].Duration - transition
+		next := fmt.Sprintf("vx%d", i)
+		if i == len(infos)-1 {
+			next = "vout"
+		}
+		filters = append(filters, fmt.Sprintf("[%s][v%d]xfade=transition=%s:duration=%s:offset=%s[%s]",
+			videoLabel, i, opts.Transition.xfade(), formatSeconds(transition), formatSeconds(offset), next))
+		videoLabel = next
+
+		if hasAudio {
+			next := fmt.Sprintf("ax%d", i)
+			if i == len(infos)-1 {
+				next = "aout"
+			}
+			filters = append(filters, fmt.Sprintf("[%s][a%d]acrossfade=d=%s[%s]", audioLabel, i, formatSeconds(transition), next))
+			audioLabel = next
+		}
+	}
+	return strings.Join(filters, ";"), hasAudio
+}
+
+// TotalDuration はクリップをトランジションでつなげた動画の長さを返す
+func TotalDuration(durations []float64, transition time.Duration) float64 {
+	if len(durations) == 0 {
+		return 0
+	}
+	total := durations[0]
+	minDuration := durations[0]
+	for _, d := range durations[1:] {
+		total += d
+		minDuration = min(minDuration, d)
+	}
+	return total - float64(len(durations)-1)*min(transition.Seconds(), minDuration/2)
+}
+
+func (t Tools) run(ctx context.Context, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
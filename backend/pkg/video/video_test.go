@@ -0,0 +1,108 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransition(t *testing.T) {
+	assert.Equal(t, TransitionFade, ParseTransition("fade"))
+	assert.Equal(t, TransitionSlide, ParseTransition("Slide"))
+	assert.Equal(t, TransitionZoom, ParseTransition(" zoom "))
+	assert.Equal(t, TransitionFade, ParseTransition(""))
+	assert.Equal(t, TransitionFade, ParseTransition("wipe"))
+}
+
+func TestBuildConcatFilter(t *testing.T) {
+	t.Run("前のクリップの終わりに重ねてトランジションを入れる", func(t *testing.T) {
+		infos := []ClipInfo{
+			{Duration: 8, Width: 1280, Height: 720, HasAudio: true},
+			{Duration: 6, Width: 720, Height: 1280, HasAudio: true},
+			{Duration: 8, Width: 1280, Height: 720, HasAudio: true},
+		}
+		filter, hasAudio := buildConcatFilter(infos, ConcatOptions{Transition: TransitionSlide, TransitionDuration: 500 * time.Millisecond})
+
+		assert.True(t, hasAudio)
+		assert.Contains(t, filter, "[1:v]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720")
+		assert.Contains(t, filter, "[v0][v1]xfade=transition=slideleft:duration=0.500:offset=7.500[vx1]")
+		assert.Contains(t, filter, "[vx1][v2]xfade=transition=slideleft:duration=0.500:offset=13.000[vout]")
+		assert.Contains(t, filter, "[ax1][a2]acrossfade=d=0.500[aout]")
+	})
+
+	t.Run("音声のないクリップがある場合は映像のみつなげる", func(t *testing.T) {
+		infos := []ClipInfo{
+			{Duration: 4, Width: 1280, Height: 720, HasAudio: true},
+			{Duration: 4, Width: 1280, Height: 720},
+		}
+		filter, hasAudio := buildConcatFilter(infos, ConcatOptions{Transition: TransitionFade, TransitionDuration: 500 * time.Millisecond})
+
+		assert.False(t, hasAudio)
+		assert.NotContains(t, filter, "acrossfade")
+		assert.Contains(t, filter, "xfade=transition=fade:duration=0.500:offset=3.500[vout]")
+	})
+
+	t.Run("トランジションは最も短いクリップの半分までに抑える", func(t *testing.T) {
+		infos := []ClipInfo{
+			{Duration: 4, Width: 1280, Height: 720},
+			{Duration: 1, Width: 1280, Height: 720},
+		}
+		filter, _ := buildConcatFilter(infos, ConcatOptions{Transition: TransitionZoom, TransitionDuration: 2 * time.Second})
+
+		assert.Contains(t, filter, "xfade=transition=zoomin:duration=0.500:offset=3.500[vout]")
+	})
+}
+
+func TestTotalDuration(t *testing.T) {
+	assert.InDelta(t, 0, TotalDuration(nil, time.Second), 0.001)
+	assert.InDelta(t, 8, TotalDuration([]float64{8}, time.Second), 0.001)
+	assert.InDelta(t, 15, TotalDuration([]float64{8, 8}, time.Second), 0.001)
+	assert.InDelta(t, 21, TotalDuration([]float64{8, 6, 8}, 500*time.Millisecond), 0.001)
+}
+
+func TestConcat(t *testing.T) {
+	tools := DefaultTools
+	if !tools.Available() {
+		t.Skip("ffmpeg・ffprobeがインストールされていないためスキップ")
+	}
+	ctx := context.Background()
+
+	red, err := tools.RenderSolidClip(ctx, "red", 2*time.Second, 320, 180)
+	require.NoError(t, err)
+	blue, err := tools.RenderSolidClip(ctx, "blue", 2*time.Second, 180, 320)
+	require.NoError(t, err)
+	green, err := tools.RenderSolidClip(ctx, "green", time.Second, 320, 180)
+	require.NoError(t, err)
+
+	t.Run("クリップをトランジションでつなげて1つの動画にする", func(t *testing.T) {
+		output, err := tools.Concat(ctx, [][]byte{red, blue, green}, ConcatOptions{Transition: TransitionFade, TransitionDuration: 500 * time.Millisecond})
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "output.mp4")
+		require.NoError(t, os.WriteFile(path, output, 0o600))
+		info, err := tools.Probe(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, 320, info.Width)
+		assert.Equal(t, 180, info.Height)
+		assert.True(t, info.HasAudio)
+		assert.InDelta(t, TotalDuration([]float64{2, 2, 1}, 500*time.Millisecond), info.Duration, 0.2)
+	})
+
+	t.Run("クリップが1つの場合はそのまま返す", func(t *testing.T) {
+		output, err := tools.Concat(ctx, [][]byte{red}, ConcatOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, red, output)
+	})
+
+	t.Run("動画でないデータはエラーになる", func(t *testing.T) {
+		_, err := tools.Concat(ctx, [][]byte{red, []byte("not a video")}, ConcatOptions{})
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "clip 1"))
+	})
+}
@@ -4,7 +4,12 @@ export interface Notification {
   id: string
   version: number
   user_id: string
-  type: 'media_completed' | 'media_failed' | 'vlog_completed' | 'vlog_failed'
+  type:
+    | 'media_completed'
+    | 'media_failed'
+    | 'vlog_completed'
+    | 'vlog_failed'
+    | 'vlog_storyboard_ready'
   title: string
   message: string
   media_id?: string
@@ -23,8 +23,12 @@ export interface TrialVlogRequest {
   destination?: string
   theme?: string
   musicMood?: string
+  bgm?: File
   duration?: number
   transition?: string
+  referenceMode?: 'inspired' | 'animate'
+  // 絵コンテを確認・編集してから動画を生成する
+  reviewStoryboard?: boolean
 }
 
 /**
@@ -46,8 +50,11 @@ export const createTrialVlog = async (
   if (request.destination) formData.append('destination', request.destination)
   if (request.theme) formData.append('theme', request.theme)
   if (request.musicMood) formData.append('musicMood', request.musicMood)
+  if (request.bgm) formData.append('bgm', request.bgm)
   if (request.duration) formData.append('duration', String(request.duration))
   if (request.transition) formData.append('transition', request.transition)
+  if (request.referenceMode) formData.append('referenceMode', request.referenceMode)
+  if (request.reviewStoryboard) formData.append('reviewStoryboard', 'true')
 
   const response = await noCredentialApiClient.post('/agent/create-vlog', formData, {
     headers: {
@@ -101,6 +101,12 @@ export interface Media {
   status: 'pending' | 'uploading' | 'completed' | 'failed' // アップロード状態
   progress: number // 進捗率（0.0〜1.0）
   error_message?: string // エラーメッセージ
+  captured_at?: string // 撮影日時（EXIF・動画のメタデータから取得）
+  latitude?: number // 撮影地点の緯度
+  longitude?: number // 撮影地点の経度
+  camera_model?: string // カメラの機種
+  orientation?: number // 向き（EXIFのOrientation）
+  duration?: number // 動画の長さ（秒、動画のメタデータから取得）
   created_at: string
   updated_at: string
 }
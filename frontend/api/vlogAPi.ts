@@ -1,21 +1,66 @@
 'use client'
 
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
-import { apiClient } from './client'
+import { apiClient, noCredentialApiClient } from './client'
 
 // VLog 型定義
 export interface Vlog {
   id: string
   video_id: string
+  title: string
+  description?: string
   video_url: string
   share_url: string
   duration: number
   thumbnail: string
-  status: 'pending' | 'processing' | 'completed' | 'failed'
+  status: 'pending' | 'processing' | 'storyboard_ready' | 'completed' | 'failed' | 'cancelled'
   error_message?: string
   progress: number
+  subtitles?: VlogSubtitle[]
+  analytics?: VlogAnalytics
+  music_track_id?: string
   created_at: string
   updated_at: string
+  // SSEで配信される生成中の情報
+  step?: string
+  message?: string
+  steps?: VlogStep[]
+}
+
+// VLogの字幕（時間は秒）
+export interface VlogSubtitle {
+  index: number
+  start_time: number
+  end_time: number
+  text: string
+}
+
+// VLogに使用したメディアの分析結果のサマリー
+export interface VlogAnalytics {
+  locations: string[]
+  activities: string[]
+  mood: string
+  highlights: string[]
+  media_count: number
+}
+
+// VLogの編集（指定しなかった項目は変更しない）
+export interface UpdateVlogRequest {
+  title?: string
+  description?: string
+  subtitles?: Omit<VlogSubtitle, 'index'>[]
+}
+
+// VLog生成フローのステップの実行記録
+export interface VlogStep {
+  name: string
+  item_index?: number
+  status: 'running' | 'completed' | 'failed' | 'skipped'
+  started_at: string
+  ended_at?: string
+  duration_ms: number
+  retry_count: number
+  error_message?: string
 }
 
 export interface VlogsResponse {
@@ -53,6 +98,40 @@ export const useGetVlogById = (vlogId: string) => {
   })
 }
 
+/** VLogのタイトル・説明文・字幕の編集 */
+export const useUpdateVlog = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (params: UpdateVlogRequest): Promise<Vlog> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.put(`/vlogs/${vlogId}`, params)
+      return res.data
+    },
+    onSuccess: data => {
+      if (vlogId) {
+        queryClient.setQueryData(VLOG_QUERY_KEY(vlogId), data)
+      }
+      queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
+    },
+    onError: error => {
+      console.error('VLog編集エラー:', error)
+    },
+  })
+}
+
+/** VLogの字幕ファイル取得（srt / vtt / json） */
+export const fetchVlogSubtitles = async (
+  vlogId: string,
+  format: 'srt' | 'vtt' | 'json' = 'srt'
+): Promise<string> => {
+  const res = await apiClient.get(`/vlogs/${vlogId}/subtitles`, {
+    params: { format },
+    responseType: 'text',
+  })
+  return res.data
+}
+
 /** VLog削除 */
 export const useDeleteVlog = (vlogId?: string) => {
   const queryClient = useQueryClient()
@@ -74,6 +153,350 @@ export const useDeleteVlog = (vlogId?: string) => {
   })
 }
 
+/** VLog生成のキャンセル */
+export const useCancelVlog = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (): Promise<Vlog> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.post(`/vlogs/${vlogId}/cancel`)
+      return res.data
+    },
+    onSuccess: () => {
+      if (vlogId) {
+        queryClient.invalidateQueries({ queryKey: VLOG_QUERY_KEY(vlogId) })
+      }
+      queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
+    },
+    onError: error => {
+      console.error('VLogキャンセルエラー:', error)
+    },
+  })
+}
+
+// 絵コンテの1シーン
+export interface VlogStoryboardScene {
+  media_ids: string[]
+  camera_direction: string
+  veo_prompt: string
+  caption: string
+  duration_seconds: 4 | 6 | 8
+}
+
+// VLogの絵コンテ
+export interface VlogStoryboard {
+  vlog_id: string
+  scenes: VlogStoryboardScene[]
+  total_duration: number
+  approved_at?: string
+}
+
+export const VLOG_STORYBOARD_QUERY_KEY = (id: string) => ['vlogs', id, 'storyboard']
+
+/** VLogの絵コンテ取得 */
+export const useGetVlogStoryboard = (vlogId: string) => {
+  return useQuery({
+    queryKey: VLOG_STORYBOARD_QUERY_KEY(vlogId),
+    queryFn: async (): Promise<VlogStoryboard> => {
+      const res = await apiClient.get(`/vlogs/${vlogId}/storyboard`)
+      return res.data
+    },
+    enabled: !!vlogId,
+    retry: false,
+  })
+}
+
+/** 絵コンテの編集（確認待ちのVLogのみ、シーンをすべて置き換える） */
+export const useUpdateVlogStoryboard = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (params: { scenes: VlogStoryboardScene[] }): Promise<VlogStoryboard> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.put(`/vlogs/${vlogId}/storyboard`, params)
+      return res.data
+    },
+    onSuccess: data => {
+      if (vlogId) {
+        queryClient.setQueryData(VLOG_STORYBOARD_QUERY_KEY(vlogId), data)
+      }
+    },
+    onError: error => {
+      console.error('絵コンテ編集エラー:', error)
+    },
+  })
+}
+
+/** 確認した絵コンテ（下書き）で動画の生成を開始 */
+export const useRenderVlog = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (): Promise<{ vlogId: string; status: string }> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.post(`/vlogs/${vlogId}/render`)
+      return res.data
+    },
+    onSuccess: () => {
+      if (vlogId) {
+        queryClient.invalidateQueries({ queryKey: VLOG_STORYBOARD_QUERY_KEY(vlogId) })
+        queryClient.invalidateQueries({ queryKey: VLOG_DRAFT_QUERY_KEY(vlogId) })
+        queryClient.invalidateQueries({ queryKey: VLOG_QUERY_KEY(vlogId) })
+      }
+      queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
+    },
+    onError: error => {
+      console.error('VLog生成開始エラー:', error)
+    },
+  })
+}
+
+// VLog下書きの作成リクエスト（分析と構成の作成のみを行い、動画は編集後に生成する）
+export interface CreateVlogDraftRequest {
+  files?: File[]
+  mediaIds?: string[]
+  title?: string
+  travelDate?: string
+  destination?: string
+  theme?: string
+  musicMood?: string
+  duration?: number
+  transition?: string
+  referenceMode?: 'inspired' | 'animate'
+}
+
+// 下書きのスタイル設定
+export interface VlogDraftStyle {
+  theme: string
+  music_mood: string
+  transition: string
+  reference_mode: string
+  subtitle_mode: string
+  narration?: { speaker_id: number; speed_scale?: number }
+}
+
+// VLogの下書き
+export interface VlogDraft {
+  vlog_id: string
+  status: Vlog['status']
+  title: string
+  description: string
+  style: VlogDraftStyle
+  media: { id: string; type: string; url: string }[]
+  scenes: VlogStoryboardScene[]
+  total_duration: number
+  estimated_tokens: number
+}
+
+// 下書きの編集リクエスト（指定しなかった項目は変更しない）
+export interface UpdateVlogDraftRequest {
+  title?: string
+  description?: string
+  media_ids?: string[]
+  theme?: string
+  music_mood?: string
+  transition?: string
+  reference_mode?: 'inspired' | 'animate'
+  subtitle_mode?: 'sidecar' | 'burned'
+  narration?: boolean
+  narration_speaker?: number
+  narration_speed?: number
+}
+
+export const VLOG_DRAFT_QUERY_KEY = (id: string) => ['vlogs', 'drafts', id]
+
+/** VLog下書きの作成 */
+export const useCreateVlogDraft = () => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (
+      params: CreateVlogDraftRequest
+    ): Promise<{ vlogId: string; status: string }> => {
+      const formData = new FormData()
+      params.files?.forEach(file => formData.append('files', file))
+      params.mediaIds?.forEach(id => formData.append('mediaIds', id))
+      if (params.title) formData.append('title', params.title)
+      if (params.travelDate) formData.append('travelDate', params.travelDate)
+      if (params.destination) formData.append('destination', params.destination)
+      if (params.theme) formData.append('theme', params.theme)
+      if (params.musicMood) formData.append('musicMood', params.musicMood)
+      if (params.duration) formData.append('duration', String(params.duration))
+      if (params.transition) formData.append('transition', params.transition)
+      if (params.referenceMode) formData.append('referenceMode', params.referenceMode)
+
+      const res = await apiClient.post('/vlogs/drafts', formData, {
+        headers: { 'Content-Type': 'multipart/form-data' },
+      })
+      return res.data
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
+    },
+    onError: error => {
+      console.error('VLog下書き作成エラー:', error)
+    },
+  })
+}
+
+/** VLog下書きの取得 */
+export const useGetVlogDraft = (vlogId: string) => {
+  return useQuery({
+    queryKey: VLOG_DRAFT_QUERY_KEY(vlogId),
+    queryFn: async (): Promise<VlogDraft> => {
+      const res = await apiClient.get(`/vlogs/drafts/${vlogId}`)
+      return res.data
+    },
+    enabled: !!vlogId,
+    retry: false,
+  })
+}
+
+/** 下書きのタイトル・メディアの並び順・スタイルの編集 */
+export const useUpdateVlogDraft = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (params: UpdateVlogDraftRequest): Promise<VlogDraft> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.patch(`/vlogs/drafts/${vlogId}`, params)
+      return res.data
+    },
+    onSuccess: data => {
+      if (vlogId) {
+        queryClient.setQueryData(VLOG_DRAFT_QUERY_KEY(vlogId), data)
+        queryClient.invalidateQueries({ queryKey: VLOG_STORYBOARD_QUERY_KEY(vlogId) })
+      }
+    },
+    onError: error => {
+      console.error('VLog下書き編集エラー:', error)
+    },
+  })
+}
+
+/** 下書きのシーンの編集（indexは1始まり、指定しなかった項目は変更しない） */
+export const useUpdateVlogDraftScene = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({
+      index,
+      ...scene
+    }: Partial<VlogStoryboardScene> & { index: number }): Promise<VlogDraft> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.patch(`/vlogs/drafts/${vlogId}/scenes/${index}`, scene)
+      return res.data
+    },
+    onSuccess: data => {
+      if (vlogId) {
+        queryClient.setQueryData(VLOG_DRAFT_QUERY_KEY(vlogId), data)
+        queryClient.invalidateQueries({ queryKey: VLOG_STORYBOARD_QUERY_KEY(vlogId) })
+      }
+    },
+    onError: error => {
+      console.error('シーン編集エラー:', error)
+    },
+  })
+}
+
+// VLogの共有リンク（所有者向け）
+export interface VlogShare {
+  code: string
+  share_url: string
+  has_password: boolean
+  expires_at?: string
+  view_count: number
+  created_at: string
+}
+
+// 共有リンクから閲覧するVLog
+export interface SharedVlog {
+  video_url: string
+  thumbnail: string
+  duration: number
+  title: string
+  description: string
+  subtitles: { start_time: number; end_time: number; text: string }[]
+  view_count: number
+  expires_at?: string
+}
+
+export const VLOG_SHARE_QUERY_KEY = (id: string) => ['vlogs', id, 'share']
+export const SHARED_VLOG_QUERY_KEY = (code: string) => ['share', code]
+
+/** VLogの共有リンク取得 */
+export const useGetVlogShare = (vlogId: string) => {
+  return useQuery({
+    queryKey: VLOG_SHARE_QUERY_KEY(vlogId),
+    queryFn: async (): Promise<VlogShare> => {
+      const res = await apiClient.get(`/vlogs/${vlogId}/share`)
+      return res.data
+    },
+    enabled: !!vlogId,
+    retry: false,
+  })
+}
+
+/** VLogの共有リンクの再発行（以前のリンクは無効になる） */
+export const useIssueVlogShare = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (params: { password?: string; expires_at?: string }): Promise<VlogShare> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      const res = await apiClient.post(`/vlogs/${vlogId}/share`, params)
+      return res.data
+    },
+    onSuccess: () => {
+      if (vlogId) {
+        queryClient.invalidateQueries({ queryKey: VLOG_SHARE_QUERY_KEY(vlogId) })
+        queryClient.invalidateQueries({ queryKey: VLOG_QUERY_KEY(vlogId) })
+      }
+    },
+    onError: error => {
+      console.error('共有リンク発行エラー:', error)
+    },
+  })
+}
+
+/** VLogの共有リンクの無効化 */
+export const useRevokeVlogShare = (vlogId?: string) => {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (): Promise<void> => {
+      if (!vlogId) throw new Error('vlogId is required')
+      await apiClient.delete(`/vlogs/${vlogId}/share`)
+    },
+    onSuccess: () => {
+      if (vlogId) {
+        queryClient.removeQueries({ queryKey: VLOG_SHARE_QUERY_KEY(vlogId) })
+        queryClient.invalidateQueries({ queryKey: VLOG_QUERY_KEY(vlogId) })
+      }
+    },
+    onError: error => {
+      console.error('共有リンク無効化エラー:', error)
+    },
+  })
+}
+
+/** 共有リンクからVLogを取得（認証不要、パスワード付きの場合はパスワードを送る） */
+export const useGetSharedVlog = (code: string, password?: string) => {
+  return useQuery({
+    queryKey: [...SHARED_VLOG_QUERY_KEY(code), password ?? ''],
+    queryFn: async (): Promise<SharedVlog> => {
+      const res = await noCredentialApiClient.get(`/share/${code}`, {
+        headers: password ? { 'X-Share-Password': password } : undefined,
+      })
+      return res.data
+    },
+    enabled: !!code,
+    retry: false,
+    staleTime: Infinity,
+  })
+}
+
 /**
  * VLog作成の進捗をSSEで監視するフック
  * 接続が切れた場合は自動的にポーリングにフォールバックする
@@ -98,7 +521,12 @@ export const useVlogSSE = (vlogId: string | null) => {
           const res = await apiClient.get(`/vlogs/${vlogId}`)
           const data = res.data as Vlog
           setStatus(data)
-          if (data.status === 'completed' || data.status === 'failed') {
+          if (
+            data.status === 'completed' ||
+            data.status === 'failed' ||
+            data.status === 'cancelled' ||
+            data.status === 'storyboard_ready'
+          ) {
             if (pollInterval) clearInterval(pollInterval)
             queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
           }
@@ -116,7 +544,11 @@ export const useVlogSSE = (vlogId: string | null) => {
       try {
         const data = JSON.parse(event.data) as Vlog
         setStatus(data)
-        if (data.status === 'completed' || data.status === 'failed') {
+        if (
+          data.status === 'completed' ||
+          data.status === 'failed' ||
+          data.status === 'storyboard_ready'
+        ) {
           eventSource.close()
           queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
         }
@@ -125,6 +557,17 @@ export const useVlogSSE = (vlogId: string | null) => {
       }
     }
 
+    // キャンセル時はcancelledイベントが送信される
+    eventSource.addEventListener('cancelled', event => {
+      try {
+        setStatus(JSON.parse((event as MessageEvent).data) as Vlog)
+      } catch (err) {
+        console.error('Failed to parse SSE message:', err)
+      }
+      eventSource.close()
+      queryClient.invalidateQueries({ queryKey: VLOGS_QUERY_KEY })
+    })
+
     eventSource.onerror = err => {
       console.error('SSE connection error:', err)
       eventSource.close()
@@ -0,0 +1,102 @@
+'use client'
+
+import { FormEvent, useEffect, useState } from 'react'
+import { isAxiosError } from 'axios'
+import { Lock } from 'lucide-react'
+import { useGetSharedVlog } from '@/api/vlogAPi'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Skeleton } from '@/components/ui/skeleton'
+import { toWebVTT } from '@/lib/subtitles'
+
+export default function SharedVlog({ code }: { code: string }) {
+  const [password, setPassword] = useState<string>()
+  const [input, setInput] = useState('')
+  const { data, error, isLoading } = useGetSharedVlog(code, password)
+  const [subtitlesUrl, setSubtitlesUrl] = useState<string>()
+
+  // 字幕はWebVTTにして<track>で表示する
+  useEffect(() => {
+    if (!data?.subtitles.length) return
+    const url = URL.createObjectURL(new Blob([toWebVTT(data.subtitles)], { type: 'text/vtt' }))
+    setSubtitlesUrl(url)
+    return () => URL.revokeObjectURL(url)
+  }, [data?.subtitles])
+
+  // パスワード付きの共有リンクは401を返す
+  const needsPassword = isAxiosError(error) && error.response?.status === 401
+
+  const handleSubmit = (e: FormEvent) => {
+    e.preventDefault()
+    setPassword(input)
+  }
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-[#FFCCEA] via-white to-[#BFECFF] p-4 md:p-8">
+      <Card className="w-full max-w-3xl">
+        {isLoading && (
+          <CardContent className="space-y-4 pt-6">
+            <Skeleton className="aspect-video w-full" />
+            <Skeleton className="h-6 w-1/2" />
+          </CardContent>
+        )}
+
+        {needsPassword && (
+          <>
+            <CardHeader>
+              <CardTitle className="flex items-center gap-2">
+                <Lock className="h-5 w-5" />
+                パスワードを入力してください
+              </CardTitle>
+            </CardHeader>
+            <CardContent>
+              <form onSubmit={handleSubmit} className="flex gap-2">
+                <Input
+                  type="password"
+                  value={input}
+                  onChange={e => setInput(e.target.value)}
+                  placeholder="パスワード"
+                  autoFocus
+                />
+                <Button type="submit" disabled={!input}>
+                  表示する
+                </Button>
+              </form>
+              {password && <p className="mt-2 text-sm text-red-500">パスワードが正しくありません</p>}
+            </CardContent>
+          </>
+        )}
+
+        {error && !needsPassword && (
+          <CardContent className="py-12 text-center text-gray-600">
+            この共有リンクは存在しないか、有効期限が切れています。
+          </CardContent>
+        )}
+
+        {data && (
+          <>
+            <CardContent className="pt-6">
+              <video
+                src={data.video_url}
+                poster={data.thumbnail || undefined}
+                controls
+                playsInline
+                className="aspect-video w-full rounded-lg bg-black"
+              >
+                {subtitlesUrl && (
+                  <track kind="subtitles" src={subtitlesUrl} srcLang="ja" label="日本語" default />
+                )}
+              </video>
+            </CardContent>
+            <CardHeader>
+              <CardTitle>{data.title || '旅のVLog'}</CardTitle>
+              {data.description && <p className="text-gray-600">{data.description}</p>}
+              <p className="text-sm text-gray-400">{data.view_count}回視聴</p>
+            </CardHeader>
+          </>
+        )}
+      </Card>
+    </div>
+  )
+}
@@ -0,0 +1,12 @@
+import SharedVlog from './_components/SharedVlog'
+
+export const metadata = {
+  title: '共有されたVLog | Tavinikkiy',
+  description: 'Tavinikkiyで作成された旅のVLogです。',
+  robots: { index: false, follow: false },
+}
+
+export default async function SharePage({ params }: { params: Promise<{ code: string }> }) {
+  const { code } = await params
+  return <SharedVlog code={code} />
+}
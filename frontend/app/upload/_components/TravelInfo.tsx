@@ -7,7 +7,20 @@ import { Label } from '@radix-ui/react-label'
 import { MapPin } from 'lucide-react'
 import React from 'react'
 import { useFormContext } from 'react-hook-form'
-import { TravelFormValues } from './form-schema'
+import { ReferenceMode, TravelFormValues } from './form-schema'
+
+const referenceModeOptions: { value: ReferenceMode; label: string; description: string }[] = [
+  {
+    value: 'inspired',
+    label: '写真を参考にする',
+    description: '写真の風景や雰囲気をもとに新しい映像を生成します',
+  },
+  {
+    value: 'animate',
+    label: '写真を動かす',
+    description: '写真をそのまま最初のシーンにして動きをつけます',
+  },
+]
 
 export default function TravelInfo() {
   const {
@@ -82,6 +95,31 @@ export default function TravelInfo() {
               {...register('travelDescription')}
             />
           </div>
+
+          <div>
+            <p className="text-sm md:text-base mb-1 block">写真の使い方</p>
+            <div className="grid gap-2 md:grid-cols-2">
+              {referenceModeOptions.map(option => (
+                <label
+                  key={option.value}
+                  className="flex cursor-pointer items-start gap-2 rounded-md border p-3 has-[:checked]:border-primary"
+                >
+                  <input
+                    type="radio"
+                    value={option.value}
+                    className="mt-1"
+                    {...register('referenceMode')}
+                  />
+                  <span>
+                    <span className="block text-sm md:text-base font-medium">{option.label}</span>
+                    <span className="block text-xs md:text-sm text-muted-foreground">
+                      {option.description}
+                    </span>
+                  </span>
+                </label>
+              ))}
+            </div>
+          </div>
         </div>
       </CardContent>
     </Card>
@@ -56,6 +56,7 @@ export function UploadFormProvider({ children }: UploadFormProviderProps) {
       travelDate: '',
       travelLocation: '',
       travelDescription: '',
+      referenceMode: 'inspired',
       uploadedFiles: [],
       mediaIds: [],
     },
@@ -109,6 +110,7 @@ export function UploadFormProvider({ children }: UploadFormProviderProps) {
       formData.append('travelDate', formDataValues.travelDate || '')
       formData.append('destination', formDataValues.travelLocation || '')
       formData.append('theme', 'adventure') // デフォルト
+      formData.append('referenceMode', formDataValues.referenceMode)
 
       const res = await apiClient.post('/agent/create-vlog', formData, {
         headers: {
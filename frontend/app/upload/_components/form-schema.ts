@@ -7,6 +7,7 @@ export const travelFormSchema = z.object({
   travelDate: z.string().optional(),
   travelLocation: z.string().optional(),
   travelDescription: z.string().optional(),
+  referenceMode: z.enum(['inspired', 'animate']),
   uploadedFiles: z.array(z.instanceof(File)),
   mediaIds: z.array(z.string()),
 }).refine(data => data.uploadedFiles.length > 0 || data.mediaIds.length > 0, {
@@ -16,4 +17,7 @@ export const travelFormSchema = z.object({
 
 export type TravelFormValues = z.infer<typeof travelFormSchema>
 
+// 写真の使い方（inspired: 写真を参考に生成 / animate: 写真をそのまま動かす）
+export type ReferenceMode = TravelFormValues['referenceMode']
+
 export type UploadStep = 'upload' | 'info' | 'confirm'
@@ -0,0 +1,27 @@
+// 字幕（時間は秒）
+export interface SubtitleCue {
+  start_time: number
+  end_time: number
+  text: string
+}
+
+const pad = (value: number, length = 2) => String(value).padStart(length, '0')
+
+// 秒をWebVTTの時間（"00:00:01.000"）にする
+const formatTime = (seconds: number) => {
+  const ms = Math.round(Math.max(seconds, 0) * 1000)
+  const h = Math.floor(ms / 3600000)
+  const m = Math.floor(ms / 60000) % 60
+  const s = Math.floor(ms / 1000) % 60
+  return `${pad(h)}:${pad(m)}:${pad(s)}.${pad(ms % 1000, 3)}`
+}
+
+/** 字幕をWebVTT形式にする（<track>で表示する） */
+export const toWebVTT = (cues: SubtitleCue[]) => {
+  const body = cues.map((cue, i) => {
+    // 空行は字幕の区切り、"-->" は時間の区切りと誤認されるため取り除く
+    const text = cue.text.replace(/\n{2,}/g, '\n').replaceAll('-->', '->')
+    return `${i + 1}\n${formatTime(cue.start_time)} --> ${formatTime(cue.end_time)}\n${text}`
+  })
+  return `WEBVTT\n\n${body.join('\n\n')}\n`
+}